@@ -2,15 +2,20 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	db "github.com/billy-le/simple-bank/db/sqlc"
 	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
+	Type     string `json:"type" binding:"omitempty,oneof=checking savings"`
+	Nickname string `json:"nickname" binding:"max=50"`
 }
 
 func (server *Server) createAccount(ctx *gin.Context) {
@@ -20,13 +25,20 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
+	accountType := util.AccountTypeChecking
+	if req.Type != "" {
+		accountType = req.Type
+	}
+
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	arg := db.CreateAccountParams{
 		Owner:    authPayload.Username,
 		Currency: req.Currency,
+		Type:     accountType,
+		Nickname: req.Nickname,
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	result, err := server.store.CreateAccountTx(ctx, arg)
 	if err != nil {
 		errCode := db.ErrorCode(err)
 		if errCode == db.ForeignKeyViolation || errCode == db.UniqueViolation {
@@ -38,7 +50,7 @@ func (server *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, result.Account)
 }
 
 type getAccountRequest struct {
@@ -65,9 +77,9 @@ func (server *Server) getAccount(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	_, ok := server.accountMember(ctx, authPayload.Username, account,
+		util.AccountMemberOwner, util.AccountMemberCoOwner, util.AccountMemberViewer, util.AccountMemberTransferor)
+	if !ok {
 		return
 	}
 
@@ -75,8 +87,15 @@ func (server *Server) getAccount(ctx *gin.Context) {
 }
 
 type listAccountsRequest struct {
-	Page_ID   int32 `form:"page_id" binding:"required,min=1"`
-	Page_Size int32 `form:"page_size" binding:"required,min=5,max=10"`
+	Page_Size  int32  `form:"page_size" binding:"required,min=5,max=10"`
+	Page_Token string `form:"page_token"`
+	Type       string `form:"type" binding:"omitempty,oneof=checking savings"`
+	Currency   string `form:"currency" binding:"omitempty,currency"`
+}
+
+type listAccountsResponse struct {
+	Accounts      []db.Account `json:"accounts"`
+	NextPageToken string       `json:"next_page_token"`
 }
 
 func (server *Server) listAccounts(ctx *gin.Context) {
@@ -89,10 +108,24 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
+	cursor, err := server.pageTokenMaker.Decode(req.Page_Token)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// fetch one extra row to know whether there is a next page
 	arg := db.ListAccountsParams{
-		Limit:  req.Page_Size,
-		Offset: (req.Page_ID - 1) * req.Page_Size,
-		Owner:  authPayload.Username,
+		Username:       authPayload.Username,
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterID:        cursor.ID,
+		PageSize:       req.Page_Size + 1,
+	}
+	if req.Type != "" {
+		arg.Type = pgtype.Text{String: req.Type, Valid: true}
+	}
+	if req.Currency != "" {
+		arg.Currency = pgtype.Text{String: req.Currency, Valid: true}
 	}
 
 	accounts, err := server.store.ListAccounts(ctx, arg)
@@ -101,34 +134,31 @@ func (server *Server) listAccounts(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
+	rsp := listAccountsResponse{Accounts: accounts}
+	if len(accounts) > int(req.Page_Size) {
+		rsp.Accounts = accounts[:req.Page_Size]
+		last := rsp.Accounts[len(rsp.Accounts)-1]
+		rsp.NextPageToken = server.pageTokenMaker.Encode(util.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 }
 
-type updateAccountRequest struct {
-	ID     int64  `uri:"id" binding:"required,min=0"`
-	Amount int64  `json:"amount"`
-	Owner  string `json:"owner"`
+type deleteAccountRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
-func (server *Server) updateAccount(ctx *gin.Context) {
-	var req updateAccountRequest
+// deleteAccount closes the account rather than deleting it, so its entries
+// and transfers keep pointing at it
+func (server *Server) deleteAccount(ctx *gin.Context) {
+	var req deleteAccountRequest
 
 	if err := ctx.ShouldBindUri(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	arg := db.AddAccountBalanceParams{
-		ID:     req.ID,
-		Amount: req.Amount,
-	}
-
-	account, err := server.store.AddAccountBalance(ctx, arg)
+	account, err := server.store.GetAccount(ctx, req.ID)
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -138,32 +168,56 @@ func (server *Server) updateAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
-}
-
-type deleteAccountRequest struct {
-	ID    int64  `uri:"id" binding:"required"`
-	Owner string `json:"owner"`
-}
-
-func (server *Server) deleteAccount(ctx *gin.Context) {
-	var req deleteAccountRequest
-
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	_, ok := server.accountMember(ctx, authPayload.Username, account, util.AccountMemberOwner)
+	if !ok {
 		return
 	}
 
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	_, err = server.store.SetAccountStatusTx(ctx, db.SetAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    util.AccountStatusClosed,
+		Reason:    "closed on request",
+		ChangedBy: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrAccountStatusTransition) ||
+			errors.Is(err, db.ErrAccountBalanceNotZero) ||
+			errors.Is(err, db.ErrInterestNotPosted) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	err := server.store.DeleteAccount(ctx, req.ID)
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+// accountMember finds the user's membership of the account, writing an error
+// response unless the user is a member with one of memberRoles
+func (server *Server) accountMember(ctx *gin.Context, username string, account db.Account, memberRoles ...string) (db.AccountMember, bool) {
+	member, err := server.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  username,
+	})
 	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			err := errors.New("account doesn't belong to the authenticated user")
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return member, false
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return member, false
 	}
 
-	ctx.JSON(http.StatusNoContent, nil)
+	for _, role := range memberRoles {
+		if member.Role == role {
+			return member, true
+		}
+	}
+
+	err = fmt.Errorf("a %s of the account cannot do this", member.Role)
+	ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	return member, false
 }
@@ -37,6 +37,7 @@ func TestGetAccount(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
 
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -120,39 +121,75 @@ func TestListAccounts(t *testing.T) {
 
 	testCases := []struct {
 		name           string
-		pageID         int32
 		pageSize       int32
+		pageToken      string
 		setupAuth      func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs     func(store *mockdb.MockStore)
 		checkResponses func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name:     "Ok",
-			pageID:   1,
 			pageSize: 5,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return(accounts[0:5], nil)
+				arg := db.ListAccountsParams{
+					Username: user.Username,
+					PageSize: 6,
+				}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts[0:6], nil)
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				data, err := io.ReadAll(recorder.Body)
 				require.NoError(t, err)
 
-				var gotAccounts []db.Account
-				err = json.Unmarshal(data, &gotAccounts)
+				var rsp listAccountsResponse
+				err = json.Unmarshal(data, &rsp)
 				require.NoError(t, err)
-				require.Len(t, gotAccounts, 5)
-				for i, acc := range gotAccounts {
+				require.Len(t, rsp.Accounts, 5)
+				for i, acc := range rsp.Accounts {
 					require.Equal(t, accounts[i], acc)
 				}
+				require.NotEmpty(t, rsp.NextPageToken)
+			},
+		},
+		{
+			name:     "LastPage",
+			pageSize: 5,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return(accounts[0:3], nil)
+			},
+			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listAccountsResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp.Accounts, 3)
+				require.Empty(t, rsp.NextPageToken)
+			},
+		},
+		{
+			name:      "InvalidPageToken",
+			pageSize:  5,
+			pageToken: "invalid",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
 			name:     "BadRequest",
-			pageID:   1,
 			pageSize: 11,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
@@ -166,7 +203,6 @@ func TestListAccounts(t *testing.T) {
 		},
 		{
 			name:     "InternalServerError",
-			pageID:   1,
 			pageSize: 5,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
@@ -191,7 +227,7 @@ func TestListAccounts(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			url := fmt.Sprintf("/accounts?page_id=%d&page_size=%d", testCase.pageID, testCase.pageSize)
+			url := fmt.Sprintf("/accounts?page_size=%d&page_token=%s", testCase.pageSize, testCase.pageToken)
 
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
@@ -228,7 +264,7 @@ func TestCreateAccount(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, newAccount.Owner, role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).Return(newAccount, nil)
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateAccountTxResult{Account: newAccount}, nil)
 
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -244,7 +280,7 @@ func TestCreateAccount(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, newAccount.Owner, role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrTxClosed)
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateAccountTxResult{}, db.ErrTxClosed)
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -258,7 +294,7 @@ func TestCreateAccount(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, newAccount.Owner, role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
 
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -294,23 +330,13 @@ func TestCreateAccount(t *testing.T) {
 	}
 }
 
-func TestUpdateAccount(t *testing.T) {
+func TestDeleteAccount(t *testing.T) {
 	user, _ := createRandomUser(t)
 	account := createRandomAccount(user.Username)
-	amount := util.RandomMoney()
-
-	updatedAccount := db.Account{
-		ID:       account.ID,
-		Owner:    account.Owner,
-		Currency: account.Currency,
-		Balance:  account.Balance + amount,
-	}
 
 	testCases := []struct {
 		name           string
 		accountID      int64
-		owner          string
-		amount         int64
 		setupAuth      func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs     func(store *mockdb.MockStore)
 		checkResponses func(t *testing.T, recorder *httptest.ResponseRecorder)
@@ -318,43 +344,34 @@ func TestUpdateAccount(t *testing.T) {
 		{
 			name:      "Ok",
 			accountID: account.ID,
-			owner:     account.Owner,
-			amount:    amount,
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Eq(db.AddAccountBalanceParams{ID: account.ID, Amount: amount})).Times(1).Return(updatedAccount, nil)
-			},
-			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyAndMatchAccount(t, recorder.Body, updatedAccount)
-			},
-		},
-		{
-			name:      "InvalidID",
-			accountID: -1,
-			owner:     account.Owner,
-			amount:    amount,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: account.Owner})).Times(1).Return(accountMembership(account, account.Owner))
+
+				arg := db.SetAccountStatusTxParams{
+					AccountID: account.ID,
+					Status:    util.AccountStatusClosed,
+					Reason:    "closed on request",
+					ChangedBy: account.Owner,
+				}
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Eq(arg)).Times(1)
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusNoContent, recorder.Code)
 			},
 		},
 		{
-			name:      "InvalidAmount",
-			accountID: account.ID,
-			owner:     account.Owner,
+			name:      "BadRequest",
+			accountID: 0,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -363,122 +380,57 @@ func TestUpdateAccount(t *testing.T) {
 		{
 			name:      "NotFound",
 			accountID: account.ID,
-			owner:     account.Owner,
-			amount:    amount,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Eq(db.AddAccountBalanceParams{
-					ID:     account.ID,
-					Amount: amount,
-				})).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
 		{
-			name:      "InternalServerError",
+			name:      "UnauthorizedUser",
 			accountID: account.ID,
-			owner:     account.Owner,
-			amount:    amount,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, user.Role, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().AddAccountBalance(gomock.Any(), gomock.Eq(db.AddAccountBalanceParams{
-					ID:     account.ID,
-					Amount: amount,
-				})).Times(1).Return(db.Account{}, db.ErrTxClosed)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: "unauthorized_user"})).Times(1).Return(accountMembership(account, "unauthorized_user"))
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
-	}
-
-	for i := range testCases {
-		testCase := testCases[i]
-		t.Run(testCase.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			testCase.buildStubs(store)
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			jsonString := ""
-			if testCase.amount != 0 {
-				jsonString = fmt.Sprintf(`{"amount": %d, "owner": "%s"}`, testCase.amount, testCase.owner)
-			}
-
-			var jsonBody = []byte(jsonString)
-			bodyReader := bytes.NewReader(jsonBody)
-			url := fmt.Sprintf("/accounts/%d", testCase.accountID)
-
-			request, err := http.NewRequest(http.MethodPut, url, bodyReader)
-			require.NoError(t, err)
-
-			testCase.setupAuth(t, request, server.tokenMaker)
-			server.router.ServeHTTP(recorder, request)
-			testCase.checkResponses(t, recorder)
-		})
-	}
-}
-
-func TestDeleteAccount(t *testing.T) {
-	user, _ := createRandomUser(t)
-	account := createRandomAccount(user.Username)
-
-	testCases := []struct {
-		name           string
-		accountID      int64
-		owner          string
-		setupAuth      func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs     func(store *mockdb.MockStore)
-		checkResponses func(t *testing.T, recorder *httptest.ResponseRecorder)
-	}{
 		{
-			name:      "Ok",
+			name:      "BalanceNotZero",
 			accountID: account.ID,
-			owner:     account.Owner,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1)
-
-			},
-			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNoContent, recorder.Code)
-			},
-		},
-		{
-			name:      "BadRequest",
-			accountID: 0,
-			owner:     account.Owner,
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, user.Role, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Any()).Times(0)
-
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: account.Owner})).Times(1).Return(accountMembership(account, account.Owner))
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.SetAccountStatusTxResult{}, db.ErrAccountBalanceNotZero)
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
 			},
 		},
 		{
-			name:      "LogError",
+			name:      "InternalServerError",
 			accountID: account.ID,
-			owner:     account.Owner,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, user.Role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: account.Owner})).Times(1).Return(accountMembership(account, account.Owner))
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.SetAccountStatusTxResult{}, db.ErrTxClosed)
 			},
 			checkResponses: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -498,11 +450,7 @@ func TestDeleteAccount(t *testing.T) {
 			recorder := httptest.NewRecorder()
 
 			url := fmt.Sprintf("/accounts/%d", testCase.accountID)
-			jsonString := fmt.Sprintf(`{ "owner": "%s"}`, testCase.owner)
-			var jsonBody = []byte(jsonString)
-			bodyReader := bytes.NewReader(jsonBody)
-
-			request, err := http.NewRequest(http.MethodDelete, url, bodyReader)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
 			require.NoError(t, err)
 
 			testCase.setupAuth(t, request, server.tokenMaker)
@@ -512,6 +460,22 @@ func TestDeleteAccount(t *testing.T) {
 	}
 }
 
+// accountMembership looks up username on the account, where only its owner
+// is a member
+func accountMembership(account db.Account, username string) (db.AccountMember, error) {
+	if username != account.Owner {
+		return db.AccountMember{}, db.ErrRecordNotFound
+	}
+
+	member := db.AccountMember{
+		AccountID: account.ID,
+		Username:  account.Owner,
+		Role:      util.AccountMemberOwner,
+		AddedBy:   account.Owner,
+	}
+	return member, nil
+}
+
 func createRandomAccount(owner string) db.Account {
 	return db.Account{
 		ID:       util.RandomInt(0, 1000),
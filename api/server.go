@@ -12,10 +12,11 @@ import (
 )
 
 type Server struct {
-	store      db.Store
-	config     util.Config
-	router     *gin.Engine
-	tokenMaker token.Maker
+	store          db.Store
+	config         util.Config
+	router         *gin.Engine
+	tokenMaker     token.Maker
+	pageTokenMaker *util.PageTokenMaker
 }
 
 func NewServer(config util.Config, store db.Store) (*Server, error) {
@@ -24,7 +25,12 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 		return nil, fmt.Errorf("cannot create token mater: %w", err)
 	}
 
-	server := &Server{store: store, tokenMaker: tokenMaker, config: config}
+	server := &Server{
+		store:          store,
+		tokenMaker:     tokenMaker,
+		pageTokenMaker: util.NewPageTokenMaker(config.TokenSymmetricKey),
+		config:         config,
+	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
@@ -46,7 +52,6 @@ func (server *Server) setupRouter() {
 	authRoutes.POST("/accounts", server.createAccount)
 	authRoutes.GET("/accounts", server.listAccounts)
 	authRoutes.GET("/accounts/:id", server.getAccount)
-	authRoutes.PUT("/accounts/:id", server.updateAccount)
 	authRoutes.DELETE("/accounts/:id", server.deleteAccount)
 
 	authRoutes.POST("/transfers", server.createTransfer)
@@ -50,6 +50,12 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
+	if session.IsRotated {
+		err := fmt.Errorf("rotated session")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
 	if session.Username != refreshPayload.Username {
 		err := fmt.Errorf("incorrect session user")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
@@ -7,14 +7,20 @@ import (
 
 	db "github.com/billy-le/simple-bank/db/sqlc"
 	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
 	"github.com/gin-gonic/gin"
 )
 
+const idempotencyKeyHeaderKey = "Idempotency-Key"
+
 type createTransferRequest struct {
 	FromAccountID int64  `json:"from_account_id" binding:"required,min=0"`
 	ToAccountID   int64  `json:"to_account_id" binding:"required,min=0"`
 	Amount        int64  `json:"amount" binding:"required,gt=0"`
 	Currency      string `json:"currency" binding:"required,currency"`
+	// ToCurrency is the currency of the destination account when it differs from Currency
+	ToCurrency string `json:"to_currency" binding:"omitempty,currency"`
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
@@ -25,6 +31,15 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
+	idempotencyKey := ctx.GetHeader(idempotencyKeyHeaderKey)
+	if idempotencyKey != "" {
+		if err := val.ValidateIdempotencyKey(idempotencyKey); err != nil {
+			err = fmt.Errorf("%s header %s", idempotencyKeyHeaderKey, err)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
 	fromAccount, fromValid := server.validAccount(ctx, req.FromAccountID, req.Currency)
 
 	if !fromValid {
@@ -33,25 +48,63 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	if fromAccount.Owner != authPayload.Username {
-		err := errors.New("from account does not belong to user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	member, ok := server.accountMember(ctx, authPayload.Username, fromAccount,
+		util.AccountMemberOwner, util.AccountMemberCoOwner, util.AccountMemberTransferor)
+	if !ok {
 		return
 	}
 
-	_, toValid := server.validAccount(ctx, req.ToAccountID, req.Currency)
+	if err := db.CheckMemberTransfer(member, req.Amount); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	toCurrency := req.ToCurrency
+	if toCurrency == "" {
+		toCurrency = req.Currency
+	}
+
+	_, toValid := server.validAccount(ctx, req.ToAccountID, toCurrency)
 	if !toValid {
 		return
 	}
 
+	// this API cannot schedule the expiry of a transfer request, so transfers
+	// that need approval go through the gRPC API
+	if util.RequiresApproval(fromAccount.Currency, req.Amount) {
+		err := errors.New("transfer needs a banker's approval and must be requested through the gRPC API")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
 	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
+		FromAccountID:  req.FromAccountID,
+		ToAccountID:    req.ToAccountID,
+		Amount:         req.Amount,
+		CrossCurrency:  toCurrency != req.Currency,
+		Username:       authPayload.Username,
+		IdempotencyKey: idempotencyKey,
 	}
 
 	transfer, err := server.store.TransferTx(ctx, arg)
 	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) ||
+			errors.Is(err, db.ErrExchangeRateNotFound) ||
+			errors.Is(err, db.ErrConvertedAmountTooSmall) ||
+			errors.Is(err, db.ErrAccountFrozen) ||
+			errors.Is(err, db.ErrAccountDormant) ||
+			errors.Is(err, db.ErrAccountClosed) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrTransferLimitExceeded) {
+			ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
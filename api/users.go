@@ -157,6 +157,7 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		ClientIp:     ctx.ClientIP(),
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiresAt.Time,
+		FamilyID:     refreshPayload.ID,
 	})
 
 	if err != nil {
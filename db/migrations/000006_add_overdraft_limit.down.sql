@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "overdraft_limit_non_negative";
+
+ALTER TABLE "accounts" DROP COLUMN "overdraft_limit";
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "transfers_created_at_id_idx";
+
+DROP INDEX IF EXISTS "entries_created_at_id_idx";
+
+DROP INDEX IF EXISTS "accounts_owner_created_at_id_idx";
@@ -0,0 +1,5 @@
+CREATE INDEX "accounts_owner_created_at_id_idx" ON "accounts" ("owner", "created_at", "id");
+
+CREATE INDEX "entries_created_at_id_idx" ON "entries" ("created_at", "id");
+
+CREATE INDEX "transfers_created_at_id_idx" ON "transfers" ("created_at", "id");
@@ -0,0 +1,35 @@
+CREATE TABLE "exchange_rates" (
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" numeric(20, 10) NOT NULL,
+  "spread_bps" integer NOT NULL DEFAULT 0,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("from_currency", "to_currency")
+);
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "exchange_rates" ADD CONSTRAINT "exchange_rate_positive" CHECK ("rate" > 0);
+
+ALTER TABLE "exchange_rates" ADD CONSTRAINT "exchange_rate_spread_range" CHECK ("spread_bps" >= 0 AND "spread_bps" < 10000);
+
+ALTER TABLE "exchange_rates" ADD CONSTRAINT "exchange_rate_distinct_currencies" CHECK ("from_currency" <> "to_currency");
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'units of to_currency per unit of from_currency';
+
+COMMENT ON COLUMN "exchange_rates"."spread_bps" IS 'margin taken off the converted amount, in basis points';
+
+ALTER TABLE "transfers" ADD COLUMN "to_amount" bigint;
+
+UPDATE "transfers" SET "to_amount" = "amount";
+
+ALTER TABLE "transfers" ALTER COLUMN "to_amount" SET NOT NULL;
+
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" numeric(20, 10);
+
+ALTER TABLE "transfers" ADD COLUMN "spread_bps" integer;
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'amount credited to the destination account, in its currency';
+
+COMMENT ON COLUMN "transfers"."exchange_rate" IS 'rate applied to a cross-currency transfer';
@@ -0,0 +1,32 @@
+CREATE TABLE "currencies" (
+  "code" varchar(3) PRIMARY KEY,
+  "numeric_code" varchar(3) UNIQUE NOT NULL,
+  "name" varchar NOT NULL,
+  "minor_units" smallint NOT NULL,
+  "enabled" bool NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currency_minor_units_range" CHECK ("minor_units" BETWEEN 0 AND 4);
+
+COMMENT ON COLUMN "currencies"."minor_units" IS 'ISO 4217 exponent, the number of decimal places';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'whether new accounts and transfers may use the currency';
+
+INSERT INTO "currencies" ("code", "numeric_code", "name", "minor_units", "enabled") VALUES
+  ('USD', '840', 'US Dollar', 2, true),
+  ('EUR', '978', 'Euro', 2, true),
+  ('CAD', '124', 'Canadian Dollar', 2, true),
+  ('GBP', '826', 'Pound Sterling', 2, false),
+  ('CHF', '756', 'Swiss Franc', 2, false),
+  ('AUD', '036', 'Australian Dollar', 2, false),
+  ('JPY', '392', 'Yen', 0, false),
+  ('KRW', '410', 'Won', 0, false),
+  ('BHD', '048', 'Bahraini Dinar', 3, false),
+  ('KWD', '414', 'Kuwaiti Dinar', 3, false);
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("from_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("to_currency") REFERENCES "currencies" ("code");
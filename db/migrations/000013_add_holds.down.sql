@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "holds";
+
+ALTER TABLE "accounts" DROP COLUMN "available_balance";
+
+ALTER TABLE "accounts" DROP COLUMN "held_amount";
@@ -0,0 +1,35 @@
+ALTER TABLE "accounts" ADD COLUMN "held_amount" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD COLUMN "available_balance" bigint NOT NULL GENERATED ALWAYS AS ("balance" - "held_amount") STORED;
+
+COMMENT ON COLUMN "accounts"."held_amount" IS 'sum of the pending holds on the account';
+
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "description" varchar NOT NULL DEFAULT '',
+  "created_by" varchar NOT NULL,
+  "transfer_id" bigint,
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "holds" ADD CONSTRAINT "hold_amount_positive" CHECK ("amount" > 0);
+
+ALTER TABLE "holds" ADD CONSTRAINT "hold_status_valid" CHECK ("status" IN ('pending', 'captured', 'released', 'expired'));
+
+CREATE INDEX ON "holds" ("account_id", "created_at", "id");
+
+COMMENT ON COLUMN "holds"."status" IS 'pending, captured, released or expired';
+
+COMMENT ON COLUMN "holds"."transfer_id" IS 'transfer the hold was captured into';
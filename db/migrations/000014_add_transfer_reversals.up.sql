@@ -0,0 +1,21 @@
+ALTER TABLE "transfers" ADD COLUMN "reversal_of" bigint;
+
+ALTER TABLE "transfers" ADD COLUMN "reason_code" varchar;
+
+ALTER TABLE "transfers" ADD COLUMN "reversed_amount" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfer_reversed_amount_range" CHECK ("reversed_amount" >= 0 AND "reversed_amount" <= "amount");
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfer_reversal_has_reason" CHECK (("reversal_of" IS NULL) = ("reason_code" IS NULL));
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfer_reason_code_valid" CHECK ("reason_code" IN ('duplicate', 'fraud', 'customer_request', 'processing_error'));
+
+CREATE INDEX ON "transfers" ("reversal_of");
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'transfer this one compensates, if it is a reversal';
+
+COMMENT ON COLUMN "transfers"."reason_code" IS 'why the reversal was made';
+
+COMMENT ON COLUMN "transfers"."reversed_amount" IS 'part of amount that has been reversed so far';
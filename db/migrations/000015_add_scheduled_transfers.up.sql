@@ -0,0 +1,67 @@
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "cross_currency" bool NOT NULL DEFAULT false,
+  "rule" varchar NOT NULL,
+  "cron_expression" varchar NOT NULL DEFAULT '',
+  "start_at" timestamptz NOT NULL,
+  "end_at" timestamptz,
+  "next_run_at" timestamptz,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" bigserial PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfer_amount_positive" CHECK ("amount" > 0);
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfer_rule_valid" CHECK ("rule" IN ('once', 'daily', 'weekly', 'monthly', 'cron'));
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfer_status_valid" CHECK ("status" IN ('active', 'paused', 'cancelled', 'completed'));
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD CONSTRAINT "scheduled_transfer_run_status_valid" CHECK ("status" IN ('pending', 'succeeded', 'failed', 'skipped'));
+
+CREATE INDEX ON "scheduled_transfers" ("owner", "created_at", "id");
+
+CREATE INDEX ON "scheduled_transfers" ("next_run_at") WHERE "status" = 'active';
+
+CREATE UNIQUE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "scheduled_for");
+
+CREATE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "created_at", "id");
+
+COMMENT ON COLUMN "scheduled_transfers"."rule" IS 'once, daily, weekly, monthly or cron';
+
+COMMENT ON COLUMN "scheduled_transfers"."cron_expression" IS 'five field cron expression, for the cron rule';
+
+COMMENT ON COLUMN "scheduled_transfers"."start_at" IS 'first occurrence, or the only one for the once rule';
+
+COMMENT ON COLUMN "scheduled_transfers"."end_at" IS 'no occurrence is run after this time';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_run_at" IS 'null once the schedule has no occurrences left';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."scheduled_for" IS 'occurrence this run executes';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."error" IS 'why the transfer failed or was skipped';
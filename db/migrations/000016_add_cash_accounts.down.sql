@@ -0,0 +1,3 @@
+DELETE FROM "accounts" WHERE "owner" = 'simple_bank';
+
+DELETE FROM "users" WHERE "username" = 'simple_bank';
@@ -0,0 +1,7 @@
+-- the bank itself, which can't log in because no password hashes to ''
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email", "is_email_verified")
+VALUES ('simple_bank', '', 'Simple Bank', 'ledger@simple-bank.internal', true);
+
+-- one cash account per currency, the other side of deposits and withdrawals
+INSERT INTO "accounts" ("owner", "balance", "currency")
+SELECT 'simple_bank', 0, "code" FROM "currencies";
@@ -0,0 +1,11 @@
+DELETE FROM "accounts" WHERE "owner" = 'simple_bank' AND "ledger_code" <> 'cash';
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_ledger_code_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+
+COMMENT ON COLUMN "entries"."amount" IS 'can be negative or positive';
+
+ALTER TABLE "accounts" DROP COLUMN "ledger_code";
+
+DROP TABLE IF EXISTS "chart_of_accounts";
@@ -0,0 +1,39 @@
+CREATE TABLE "chart_of_accounts" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "chart_of_accounts" ADD CONSTRAINT "ledger_type_valid" CHECK ("type" IN ('asset', 'liability', 'equity', 'income', 'expense'));
+
+COMMENT ON COLUMN "chart_of_accounts"."type" IS 'asset, liability, equity, income or expense';
+
+INSERT INTO "chart_of_accounts" ("code", "name", "type") VALUES
+  ('cash', 'Cash', 'asset'),
+  ('suspense', 'Suspense', 'asset'),
+  ('customer_deposits', 'Customer deposits', 'liability'),
+  ('fx_position', 'Foreign exchange position', 'equity'),
+  ('fee_income', 'Fee income', 'income'),
+  ('interest_expense', 'Interest expense', 'expense');
+
+ALTER TABLE "accounts" ADD COLUMN "ledger_code" varchar NOT NULL DEFAULT 'customer_deposits';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("ledger_code") REFERENCES "chart_of_accounts" ("code");
+
+COMMENT ON COLUMN "accounts"."ledger_code" IS 'where the account sits in the chart of accounts';
+
+COMMENT ON COLUMN "entries"."amount" IS 'negative for a debit, positive for a credit';
+
+-- the bank holds one account per ledger code and currency
+ALTER TABLE "accounts" DROP CONSTRAINT "owner_currency_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_ledger_code_key" UNIQUE ("owner", "currency", "ledger_code");
+
+UPDATE "accounts" SET "ledger_code" = 'cash' WHERE "owner" = 'simple_bank';
+
+INSERT INTO "accounts" ("owner", "balance", "currency", "ledger_code")
+SELECT 'simple_bank', 0, "currencies"."code", "chart_of_accounts"."code"
+FROM "currencies"
+CROSS JOIN "chart_of_accounts"
+WHERE "chart_of_accounts"."code" IN ('suspense', 'fx_position', 'fee_income', 'interest_expense');
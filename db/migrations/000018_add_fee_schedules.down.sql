@@ -0,0 +1,3 @@
+ALTER TABLE "transfers" DROP COLUMN "fee_of";
+
+DROP TABLE IF EXISTS "fee_schedules";
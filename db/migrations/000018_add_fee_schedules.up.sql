@@ -0,0 +1,41 @@
+CREATE TABLE "fee_schedules" (
+  "transfer_type" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "min_amount" bigint NOT NULL DEFAULT 0,
+  "flat_fee" bigint NOT NULL DEFAULT 0,
+  "rate_bps" integer NOT NULL DEFAULT 0,
+  "min_fee" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("transfer_type", "currency", "role", "min_amount")
+);
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "fee_schedules" ADD CONSTRAINT "fee_transfer_type_valid" CHECK ("transfer_type" IN ('transfer', 'cross_currency', 'deposit', 'withdrawal'));
+
+ALTER TABLE "fee_schedules" ADD CONSTRAINT "fee_amounts_not_negative" CHECK ("min_amount" >= 0 AND "flat_fee" >= 0 AND "min_fee" >= 0);
+
+ALTER TABLE "fee_schedules" ADD CONSTRAINT "fee_rate_range" CHECK ("rate_bps" >= 0 AND "rate_bps" <= 10000);
+
+ALTER TABLE "fee_schedules" ADD CONSTRAINT "fee_caps_ordered" CHECK ("max_fee" IS NULL OR "max_fee" >= "min_fee");
+
+COMMENT ON COLUMN "fee_schedules"."transfer_type" IS 'transfer, cross_currency, deposit or withdrawal';
+
+COMMENT ON COLUMN "fee_schedules"."min_amount" IS 'smallest amount the tier applies to, the tier with the highest min_amount not above the amount wins';
+
+COMMENT ON COLUMN "fee_schedules"."rate_bps" IS 'percentage of the amount charged on top of flat_fee, in basis points';
+
+COMMENT ON COLUMN "fee_schedules"."max_fee" IS 'no cap when null';
+
+ALTER TABLE "transfers" ADD COLUMN "fee_of" bigint;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("fee_of") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "transfers" ("fee_of");
+
+COMMENT ON COLUMN "transfers"."fee_of" IS 'transfer this one charged a fee for, if it is a fee';
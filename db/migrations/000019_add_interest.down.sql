@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "interest_accruals";
+
+ALTER TABLE "accounts" DROP COLUMN "type";
+
+DROP TABLE IF EXISTS "account_products";
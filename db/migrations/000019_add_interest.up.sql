@@ -0,0 +1,51 @@
+CREATE TABLE "account_products" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "interest_rate_bps" integer NOT NULL DEFAULT 0,
+  "day_count" varchar NOT NULL DEFAULT 'act_365',
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "account_products" ADD CONSTRAINT "interest_rate_range" CHECK ("interest_rate_bps" >= 0 AND "interest_rate_bps" <= 10000);
+
+ALTER TABLE "account_products" ADD CONSTRAINT "day_count_valid" CHECK ("day_count" IN ('act_365', '30_360'));
+
+COMMENT ON COLUMN "account_products"."interest_rate_bps" IS 'annual interest rate, in basis points';
+
+COMMENT ON COLUMN "account_products"."day_count" IS 'act_365 or 30_360';
+
+INSERT INTO "account_products" ("code", "name", "interest_rate_bps", "day_count") VALUES
+  ('checking', 'Checking', 0, 'act_365'),
+  ('savings', 'Savings', 150, 'act_365');
+
+ALTER TABLE "accounts" ADD COLUMN "type" varchar NOT NULL DEFAULT 'checking';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("type") REFERENCES "account_products" ("code");
+
+COMMENT ON COLUMN "accounts"."type" IS 'account product, which sets the interest the account earns';
+
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "rate_bps" integer NOT NULL,
+  "day_count" varchar NOT NULL,
+  "amount" numeric(24,10) NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "interest_accruals" ADD CONSTRAINT "account_accrual_date_key" UNIQUE ("account_id", "accrual_date");
+
+CREATE INDEX ON "interest_accruals" ("transfer_id");
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'end-of-day balance the interest was computed on';
+
+COMMENT ON COLUMN "interest_accruals"."amount" IS 'interest earned for the day, in fractions of the minor unit';
+
+COMMENT ON COLUMN "interest_accruals"."transfer_id" IS 'transfer that paid the interest out, null until it is posted';
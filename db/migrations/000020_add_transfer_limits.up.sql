@@ -0,0 +1,60 @@
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "role" varchar,
+  "username" varchar,
+  "account_id" bigint,
+  "scope" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "period" varchar NOT NULL,
+  "max_amount" bigint,
+  "max_count" integer,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "limit_one_subject" CHECK (num_nonnulls("role", "username", "account_id") = 1);
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "limit_scope_valid" CHECK (
+  "scope" IN ('account', 'user')
+  AND ("username" IS NULL OR "scope" = 'user')
+  AND ("account_id" IS NULL OR "scope" = 'account')
+);
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "limit_period_valid" CHECK ("period" IN ('daily', 'monthly'));
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "limit_maximums_not_negative" CHECK ("max_amount" >= 0 AND "max_count" >= 0);
+
+-- one limit per subject, scope, currency and period, whichever of role,
+-- username or account_id the subject is
+CREATE UNIQUE INDEX "transfer_limits_subject_key" ON "transfer_limits" (
+  COALESCE("role", ''),
+  COALESCE("username", ''),
+  COALESCE("account_id", 0),
+  "scope",
+  "currency",
+  "period"
+);
+
+COMMENT ON COLUMN "transfer_limits"."role" IS 'set for the default limit of every user with the role';
+
+COMMENT ON COLUMN "transfer_limits"."username" IS 'set when a banker overrides the user limit of one user';
+
+COMMENT ON COLUMN "transfer_limits"."account_id" IS 'set when a banker overrides the limit of one account';
+
+COMMENT ON COLUMN "transfer_limits"."scope" IS 'account limits add up the transfers out of one account, user limits those out of all the user''s accounts in the currency';
+
+COMMENT ON COLUMN "transfer_limits"."period" IS 'daily or monthly, in UTC';
+
+COMMENT ON COLUMN "transfer_limits"."max_amount" IS 'no amount limit when null';
+
+COMMENT ON COLUMN "transfer_limits"."max_count" IS 'no count limit when null';
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
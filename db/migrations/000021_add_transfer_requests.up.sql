@@ -0,0 +1,49 @@
+ALTER TABLE "currencies" ADD COLUMN "approval_threshold" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "currencies" ADD CONSTRAINT "currency_approval_threshold_not_negative" CHECK ("approval_threshold" >= 0);
+
+COMMENT ON COLUMN "currencies"."approval_threshold" IS 'transfers of more than this need a banker''s approval, 0 turns approvals off';
+
+CREATE TABLE "transfer_requests" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "cross_currency" boolean NOT NULL DEFAULT false,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "requested_by" varchar NOT NULL,
+  "decided_by" varchar,
+  "reason" varchar NOT NULL DEFAULT '',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_requests" ADD CONSTRAINT "transfer_request_amount_positive" CHECK ("amount" > 0);
+
+ALTER TABLE "transfer_requests" ADD CONSTRAINT "transfer_request_status_valid" CHECK ("status" IN ('pending', 'approved', 'rejected', 'expired'));
+
+ALTER TABLE "transfer_requests" ADD CONSTRAINT "transfer_request_not_self_approved" CHECK ("status" <> 'approved' OR "decided_by" <> "requested_by");
+
+CREATE INDEX ON "transfer_requests" ("status", "created_at", "id");
+
+COMMENT ON COLUMN "transfer_requests"."status" IS 'pending, approved, rejected or expired';
+
+COMMENT ON COLUMN "transfer_requests"."requested_by" IS 'the maker, who can never approve the request';
+
+COMMENT ON COLUMN "transfer_requests"."decided_by" IS 'the banker who approved or rejected the request';
+
+COMMENT ON COLUMN "transfer_requests"."reason" IS 'why the request was rejected';
+
+COMMENT ON COLUMN "transfer_requests"."transfer_id" IS 'transfer made when the request was approved';
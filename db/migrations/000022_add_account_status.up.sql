@@ -0,0 +1,23 @@
+ALTER TABLE "accounts" ADD COLUMN "status" varchar NOT NULL DEFAULT 'active';
+
+ALTER TABLE "accounts" ADD CONSTRAINT "account_status_valid" CHECK ("status" IN ('active', 'frozen', 'dormant', 'closed'));
+
+COMMENT ON COLUMN "accounts"."status" IS 'active, frozen, dormant or closed; only active accounts send money and frozen or closed ones receive none';
+
+CREATE TABLE "account_status_changes" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "from_status" varchar NOT NULL,
+  "to_status" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "changed_by" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("changed_by") REFERENCES "users" ("username");
+
+CREATE INDEX ON "account_status_changes" ("account_id", "created_at", "id");
+
+COMMENT ON COLUMN "account_status_changes"."changed_by" IS 'banker or owner who made the change, null when the bank marked the account dormant';
@@ -0,0 +1,31 @@
+CREATE TABLE "account_members" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "transfer_limit" bigint,
+  "added_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("added_by") REFERENCES "users" ("username");
+
+ALTER TABLE "account_members" ADD CONSTRAINT "account_member_role_valid" CHECK ("role" IN ('owner', 'co_owner', 'viewer', 'transferor'));
+
+ALTER TABLE "account_members" ADD CONSTRAINT "account_member_transfer_limit_set" CHECK (("role" = 'transferor') = ("transfer_limit" IS NOT NULL));
+
+ALTER TABLE "account_members" ADD CONSTRAINT "account_member_transfer_limit_positive" CHECK ("transfer_limit" > 0);
+
+CREATE INDEX ON "account_members" ("username", "account_id");
+
+COMMENT ON COLUMN "account_members"."role" IS 'owner, co_owner, viewer or transferor; the owner is the account''s owner and every member can see the account';
+
+COMMENT ON COLUMN "account_members"."transfer_limit" IS 'largest transfer a transferor may make from the account, null for every other role';
+
+INSERT INTO "account_members" ("account_id", "username", "role", "added_by")
+SELECT "id", "owner", 'owner', "owner" FROM "accounts"
+WHERE "owner" <> 'simple_bank';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "owner_currency_type_ledger_code_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_ledger_code_key" UNIQUE ("owner", "currency", "ledger_code");
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "nickname";
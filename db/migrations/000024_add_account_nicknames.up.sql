@@ -0,0 +1,7 @@
+ALTER TABLE "accounts" ADD COLUMN "nickname" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "accounts"."nickname" IS 'name the owner gave the account, empty when it has none';
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_ledger_code_key";
+
+CREATE UNIQUE INDEX "owner_currency_type_ledger_code_key" ON "accounts" ("owner", "currency", "type", "ledger_code") WHERE "status" <> 'closed';
@@ -0,0 +1,7 @@
+DELETE FROM "interest_accruals" WHERE "carried_from" IS NOT NULL;
+
+DROP INDEX IF EXISTS "account_accrual_date_key";
+
+ALTER TABLE "interest_accruals" ADD CONSTRAINT "account_accrual_date_key" UNIQUE ("account_id", "accrual_date");
+
+ALTER TABLE "interest_accruals" DROP COLUMN IF EXISTS "carried_from";
@@ -0,0 +1,9 @@
+ALTER TABLE "interest_accruals" ADD COLUMN "carried_from" bigint;
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("carried_from") REFERENCES "transfers" ("id");
+
+ALTER TABLE "interest_accruals" DROP CONSTRAINT IF EXISTS "account_accrual_date_key";
+
+CREATE UNIQUE INDEX "account_accrual_date_key" ON "interest_accruals" ("account_id", "accrual_date") WHERE "carried_from" IS NULL;
+
+COMMENT ON COLUMN "interest_accruals"."carried_from" IS 'posting that left this fraction of a minor unit over for the next one, null for a day''s own accrual';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "scheduled_transfers_from_account_id_created_at_id_idx";
+
+ALTER TABLE "scheduled_transfers" DROP COLUMN IF EXISTS "created_by";
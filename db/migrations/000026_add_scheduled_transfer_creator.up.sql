@@ -0,0 +1,11 @@
+ALTER TABLE "scheduled_transfers" ADD COLUMN "created_by" varchar;
+
+UPDATE "scheduled_transfers" SET "created_by" = "owner";
+
+ALTER TABLE "scheduled_transfers" ALTER COLUMN "created_by" SET NOT NULL;
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+CREATE INDEX ON "scheduled_transfers" ("from_account_id", "created_at", "id");
+
+COMMENT ON COLUMN "scheduled_transfers"."created_by" IS 'member or banker who set the schedule up; a member''s schedule is paused once they leave the account';
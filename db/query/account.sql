@@ -2,15 +2,21 @@
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  type,
+  nickname
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetAccount :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
+-- name: GetAccountByLedgerCode :one
+SELECT * FROM accounts
+WHERE owner = $1 AND currency = $2 AND ledger_code = $3 LIMIT 1;
+
 -- name: GetAccountForUpdate :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1
@@ -18,23 +24,53 @@ FOR NO KEY UPDATE;
 
 -- name: ListAccounts :many
 SELECT * FROM accounts
-WHERE owner = $1
-ORDER BY id
-LIMIT $2
-OFFSET $3;
+WHERE id IN (
+    SELECT account_id FROM account_members
+    WHERE username = sqlc.arg(username)
+)
+    AND (sqlc.narg(type)::varchar IS NULL OR type = sqlc.narg(type))
+    AND (sqlc.narg(currency)::varchar IS NULL OR currency = sqlc.narg(currency))
+    AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
 
--- name: UpdateAccount :one
+-- name: AddAccountBalance :one
 UPDATE accounts
-SET balance = $2
-WHERE id = $1
+SET balance = balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
 RETURNING *;
 
--- name: AddAccountBalance :one
+-- name: AddAccountHeldAmount :one
 UPDATE accounts
-SET balance = balance + sqlc.arg(amount)
+SET held_amount = held_amount + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
--- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = sqlc.arg(overdraft_limit)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: MarkDormantAccounts :many
+UPDATE accounts
+SET status = 'dormant'
+WHERE status = 'active'
+    AND ledger_code = 'customer_deposits'
+    AND created_at < sqlc.arg(inactive_since)
+    AND NOT EXISTS (
+        SELECT 1 FROM entries
+        WHERE entries.account_id = accounts.id
+            AND entries.created_at >= sqlc.arg(inactive_since)
+            AND NOT EXISTS (
+                SELECT 1 FROM interest_accruals
+                WHERE interest_accruals.transfer_id = entries.transfer_id
+            )
+    )
+RETURNING *;
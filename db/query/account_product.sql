@@ -0,0 +1,16 @@
+-- name: GetAccountProduct :one
+SELECT * FROM account_products
+WHERE code = $1 LIMIT 1;
+
+-- name: ListAccountProducts :many
+SELECT * FROM account_products
+ORDER BY code;
+
+-- name: UpdateAccountProduct :one
+UPDATE account_products
+SET
+    interest_rate_bps = COALESCE(sqlc.narg(interest_rate_bps), interest_rate_bps),
+    day_count = COALESCE(sqlc.narg(day_count), day_count),
+    updated_at = now()
+WHERE code = sqlc.arg(code)
+RETURNING *;
@@ -0,0 +1,15 @@
+-- name: GetCurrency :one
+SELECT * FROM currencies
+WHERE code = $1 LIMIT 1;
+
+-- name: ListCurrencies :many
+SELECT * FROM currencies
+ORDER BY code;
+
+-- name: UpdateCurrency :one
+UPDATE currencies
+SET
+    enabled = COALESCE(sqlc.narg(enabled), enabled),
+    approval_threshold = COALESCE(sqlc.narg(approval_threshold), approval_threshold)
+WHERE code = sqlc.arg(code)
+RETURNING *;
@@ -1,6 +1,6 @@
 -- name: CreateEntry :one
-INSERT INTO entries (amount, account_id)
-VALUES ($1, $2)
+INSERT INTO entries (amount, account_id, transfer_id)
+VALUES ($1, $2, $3)
 RETURNING *;
 
 -- name: GetEntry :one
@@ -10,16 +10,43 @@ LIMIT 1;
 
 -- name: ListEntries :many
 SELECT * FROM entries
-ORDER BY id
-LIMIT $1
-OFFSET $2;
+WHERE (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
 
--- name: UpdateEntry :one
-UPDATE entries
-SET account_id = $2, amount = $3
-WHERE id = $1
-RETURNING *;
+-- name: GetStatementBalances :one
+SELECT
+    (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= sqlc.arg(start_time)), 0))::bigint AS opening_balance,
+    (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= sqlc.arg(end_time)), 0))::bigint AS closing_balance,
+    (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= sqlc.arg(start_time)), 0)
+        + COALESCE(SUM(e.amount) FILTER (
+            WHERE e.created_at >= sqlc.arg(start_time)
+                AND e.created_at < sqlc.arg(end_time)
+                AND (e.created_at, e.id) <= (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+        ), 0))::bigint AS page_opening_balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id = sqlc.arg(account_id)
+GROUP BY a.id;
 
--- name: DeleteEntry :exec
-DELETE FROM entries
-WHERE id = $1;
\ No newline at end of file
+-- name: ListStatementEntries :many
+SELECT
+    e.id,
+    e.account_id,
+    e.amount,
+    e.created_at,
+    e.transfer_id,
+    t.from_account_id,
+    t.to_account_id,
+    fa.owner AS from_account_owner,
+    ta.owner AS to_account_owner
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+LEFT JOIN accounts fa ON fa.id = t.from_account_id
+LEFT JOIN accounts ta ON ta.id = t.to_account_id
+WHERE e.account_id = sqlc.arg(account_id)
+    AND e.created_at >= sqlc.arg(start_time)
+    AND e.created_at < sqlc.arg(end_time)
+    AND (e.created_at, e.id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY e.created_at, e.id
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,30 @@
+-- name: GetExchangeRate :one
+SELECT * FROM exchange_rates
+WHERE from_currency = $1 AND to_currency = $2
+LIMIT 1;
+
+-- name: ListExchangeRates :many
+SELECT * FROM exchange_rates
+ORDER BY from_currency, to_currency;
+
+-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (
+    from_currency,
+    to_currency,
+    rate,
+    spread_bps,
+    updated_by
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (from_currency, to_currency) DO UPDATE
+SET
+    rate = EXCLUDED.rate,
+    spread_bps = EXCLUDED.spread_bps,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteExchangeRate :execrows
+DELETE FROM exchange_rates
+WHERE from_currency = $1 AND to_currency = $2;
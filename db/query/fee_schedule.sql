@@ -0,0 +1,40 @@
+-- name: GetFeeSchedule :one
+SELECT * FROM fee_schedules
+WHERE transfer_type = sqlc.arg(transfer_type)
+    AND currency = sqlc.arg(currency)
+    AND role = sqlc.arg(role)
+    AND min_amount <= sqlc.arg(amount)
+ORDER BY min_amount DESC
+LIMIT 1;
+
+-- name: ListFeeSchedules :many
+SELECT * FROM fee_schedules
+ORDER BY transfer_type, currency, role, min_amount;
+
+-- name: UpsertFeeSchedule :one
+INSERT INTO fee_schedules (
+    transfer_type,
+    currency,
+    role,
+    min_amount,
+    flat_fee,
+    rate_bps,
+    min_fee,
+    max_fee,
+    updated_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (transfer_type, currency, role, min_amount) DO UPDATE
+SET
+    flat_fee = EXCLUDED.flat_fee,
+    rate_bps = EXCLUDED.rate_bps,
+    min_fee = EXCLUDED.min_fee,
+    max_fee = EXCLUDED.max_fee,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteFeeSchedule :execrows
+DELETE FROM fee_schedules
+WHERE transfer_type = $1 AND currency = $2 AND role = $3 AND min_amount = $4;
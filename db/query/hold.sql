@@ -0,0 +1,37 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+    account_id,
+    amount,
+    description,
+    created_by,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListAccountHolds :many
+SELECT * FROM holds
+WHERE account_id = sqlc.arg(account_id)
+    AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
+
+-- name: UpdateHoldStatus :one
+UPDATE holds
+SET
+    status = sqlc.arg(status),
+    captured_amount = sqlc.arg(captured_amount),
+    transfer_id = sqlc.narg(transfer_id),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
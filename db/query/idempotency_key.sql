@@ -0,0 +1,36 @@
+-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    username,
+    key,
+    request_hash,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (username, key) DO NOTHING
+RETURNING *;
+
+-- name: GetIdempotencyKeyForUpdate :one
+SELECT * FROM idempotency_keys
+WHERE username = $1 AND key = $2
+LIMIT 1
+FOR UPDATE;
+
+-- name: ResetIdempotencyKey :one
+UPDATE idempotency_keys
+SET
+    request_hash = sqlc.arg(request_hash),
+    response = NULL,
+    created_at = now(),
+    expires_at = sqlc.arg(expires_at)
+WHERE
+    username = sqlc.arg(username)
+    AND key = sqlc.arg(key)
+RETURNING *;
+
+-- name: UpdateIdempotencyKeyResponse :exec
+UPDATE idempotency_keys
+SET response = sqlc.arg(response)
+WHERE
+    username = sqlc.arg(username)
+    AND key = sqlc.arg(key);
@@ -0,0 +1,59 @@
+-- name: ListInterestBearingBalances :many
+SELECT
+    a.id AS account_id,
+    p.interest_rate_bps,
+    p.day_count,
+    (a.balance - COALESCE(SUM(e.amount), 0))::bigint AS balance
+FROM accounts a
+JOIN account_products p ON p.code = a.type
+LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= sqlc.arg(day_end)
+WHERE p.interest_rate_bps > 0
+    AND a.created_at < sqlc.arg(day_end)
+GROUP BY a.id, p.code
+HAVING a.balance - COALESCE(SUM(e.amount), 0) > 0
+ORDER BY a.id;
+
+-- name: CreateInterestAccrual :execrows
+INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    balance,
+    rate_bps,
+    day_count,
+    amount
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (account_id, accrual_date) WHERE carried_from IS NULL DO NOTHING;
+
+-- name: CreateInterestCarry :one
+INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    balance,
+    rate_bps,
+    day_count,
+    amount,
+    carried_from
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListAccountsWithUnpostedInterest :many
+SELECT DISTINCT account_id FROM interest_accruals
+WHERE transfer_id IS NULL
+    AND accrual_date < sqlc.arg(before)
+ORDER BY account_id;
+
+-- name: ListUnpostedInterestAccrualsForUpdate :many
+SELECT * FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+    AND transfer_id IS NULL
+    AND accrual_date < sqlc.arg(before)
+ORDER BY accrual_date
+FOR NO KEY UPDATE;
+
+-- name: SetInterestAccrualsTransfer :execrows
+UPDATE interest_accruals
+SET transfer_id = sqlc.arg(transfer_id)
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
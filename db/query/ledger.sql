@@ -0,0 +1,60 @@
+-- name: ListAccountBalanceMismatches :many
+SELECT
+    a.id AS account_id,
+    a.owner,
+    a.currency,
+    a.balance,
+    COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListTransferEntryMismatches :many
+SELECT
+    t.id AS transfer_id,
+    t.from_account_id,
+    t.to_account_id,
+    t.amount,
+    t.to_amount,
+    COUNT(e.id)::int AS entry_count,
+    (CASE WHEN fa.currency = ta.currency THEN 2 ELSE 4 END)::int AS expected_entry_count,
+    COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.from_account_id), 0)::bigint AS from_entries_total,
+    COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.to_account_id), 0)::bigint AS to_entries_total
+FROM transfers t
+JOIN accounts fa ON fa.id = t.from_account_id
+JOIN accounts ta ON ta.id = t.to_account_id
+LEFT JOIN entries e ON e.transfer_id = t.id
+GROUP BY t.id, fa.id, ta.id
+HAVING COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.from_account_id), 0) <> -t.amount
+    OR COALESCE(SUM(e.amount) FILTER (WHERE e.account_id = t.to_account_id), 0) <> t.to_amount
+    OR COUNT(e.id) <> CASE WHEN fa.currency = ta.currency THEN 2 ELSE 4 END
+ORDER BY t.id;
+
+-- name: ListUnbalancedJournals :many
+SELECT
+    t.id AS transfer_id,
+    a.currency,
+    SUM(e.amount)::bigint AS entries_total
+FROM transfers t
+JOIN entries e ON e.transfer_id = t.id
+JOIN accounts a ON a.id = e.account_id
+GROUP BY t.id, a.currency
+HAVING SUM(e.amount) <> 0
+ORDER BY t.id, a.currency;
+
+-- name: ListTrialBalance :many
+SELECT
+    a.currency,
+    c.code AS ledger_code,
+    c.name,
+    c.type,
+    COALESCE(SUM(-e.amount) FILTER (WHERE e.amount < 0), 0)::bigint AS debits,
+    COALESCE(SUM(e.amount) FILTER (WHERE e.amount > 0), 0)::bigint AS credits
+FROM entries e
+JOIN accounts a ON a.id = e.account_id
+JOIN chart_of_accounts c ON c.code = a.ledger_code
+WHERE sqlc.narg(as_of)::timestamptz IS NULL OR e.created_at <= sqlc.narg(as_of)
+GROUP BY a.currency, c.code
+ORDER BY a.currency, c.code;
@@ -0,0 +1,84 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+    owner,
+    from_account_id,
+    to_account_id,
+    amount,
+    cross_currency,
+    rule,
+    cron_expression,
+    start_at,
+    end_at,
+    next_run_at,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE from_account_id IN (
+    SELECT account_id FROM account_members
+    WHERE username = sqlc.arg(username)
+)
+    AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
+
+-- name: ListDueScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE status = 'active'
+    AND next_run_at <= sqlc.arg(now)::timestamptz
+ORDER BY next_run_at
+LIMIT sqlc.arg(batch_size)
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: UpdateScheduledTransferStatus :one
+UPDATE scheduled_transfers
+SET
+    status = sqlc.arg(status),
+    next_run_at = sqlc.narg(next_run_at),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+    scheduled_transfer_id,
+    scheduled_for
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (scheduled_transfer_id, scheduled_for) DO NOTHING
+RETURNING *;
+
+-- name: GetScheduledTransferRun :one
+SELECT * FROM scheduled_transfer_runs
+WHERE id = $1 LIMIT 1;
+
+-- name: ListScheduledTransferRuns :many
+SELECT * FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = sqlc.arg(scheduled_transfer_id)
+    AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
+
+-- name: UpdateScheduledTransferRun :one
+UPDATE scheduled_transfer_runs
+SET
+    status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    error = sqlc.arg(error),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -4,26 +4,39 @@ WHERE id = $1 LIMIT 1;
 
 -- name: ListTransfers :many
 SELECT * FROM transfers
-ORDER BY id
-LIMIT $1
-OFFSET $2;
+WHERE (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
+
+-- name: ListAccountTransfers :many
+SELECT * FROM transfers
+WHERE (
+        (sqlc.arg(include_sent)::boolean AND from_account_id = sqlc.arg(account_id))
+        OR (sqlc.arg(include_received)::boolean AND to_account_id = sqlc.arg(account_id))
+    )
+    AND (sqlc.narg(min_amount)::bigint IS NULL OR amount >= sqlc.narg(min_amount))
+    AND (sqlc.narg(max_amount)::bigint IS NULL OR amount <= sqlc.narg(max_amount))
+    AND (sqlc.narg(start_time)::timestamptz IS NULL OR created_at >= sqlc.narg(start_time))
+    AND (sqlc.narg(end_time)::timestamptz IS NULL OR created_at < sqlc.narg(end_time))
+    AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
+
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
 
 -- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
+  from_account_id, to_account_id, amount, to_amount, exchange_rate, spread_bps, reversal_of, reason_code, fee_of
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
--- name: UpdateTransfer :one
+-- name: AddTransferReversedAmount :one
 UPDATE transfers
-SET from_account_id = $2,
-to_account_id = $3,
-amount = $4
-WHERE id = $1
+SET reversed_amount = reversed_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
 RETURNING *;
-
--- name: DeleteTransfer :exec
-DELETE FROM transfers
-WHERE id = $1;
\ No newline at end of file
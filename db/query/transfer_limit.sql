@@ -0,0 +1,60 @@
+-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+    role,
+    username,
+    account_id,
+    scope,
+    currency,
+    period,
+    max_amount,
+    max_count,
+    updated_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (COALESCE(role, ''), COALESCE(username, ''), COALESCE(account_id, 0), scope, currency, period) DO UPDATE
+SET
+    max_amount = EXCLUDED.max_amount,
+    max_count = EXCLUDED.max_count,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListTransferLimits :many
+SELECT * FROM transfer_limits
+ORDER BY scope, currency, period, id;
+
+-- name: DeleteTransferLimit :execrows
+DELETE FROM transfer_limits
+WHERE id = $1;
+
+-- name: GetEffectiveTransferLimit :one
+SELECT * FROM transfer_limits
+WHERE scope = sqlc.arg(scope)
+    AND currency = sqlc.arg(currency)
+    AND period = sqlc.arg(period)
+    AND (role = sqlc.arg(role)::varchar OR username = sqlc.narg(username) OR account_id = sqlc.narg(account_id))
+ORDER BY role IS NOT NULL
+LIMIT 1;
+
+-- name: GetAccountTransferUsage :one
+SELECT
+    COALESCE(SUM(amount), 0)::bigint AS amount,
+    COUNT(*)::int AS count
+FROM transfers
+WHERE from_account_id = sqlc.arg(account_id)
+    AND created_at >= sqlc.arg(since)
+    AND fee_of IS NULL
+    AND reversal_of IS NULL;
+
+-- name: GetUserTransferUsage :one
+SELECT
+    COALESCE(SUM(t.amount), 0)::bigint AS amount,
+    COUNT(*)::int AS count
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE a.owner = sqlc.arg(owner)
+    AND a.currency = sqlc.arg(currency)
+    AND t.created_at >= sqlc.arg(since)
+    AND t.fee_of IS NULL
+    AND t.reversal_of IS NULL;
@@ -0,0 +1,39 @@
+-- name: CreateTransferRequest :one
+INSERT INTO transfer_requests (
+    from_account_id,
+    to_account_id,
+    amount,
+    cross_currency,
+    requested_by,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetTransferRequest :one
+SELECT * FROM transfer_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferRequestForUpdate :one
+SELECT * FROM transfer_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListTransferRequests :many
+SELECT * FROM transfer_requests
+WHERE status = sqlc.arg(status)
+    AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
+
+-- name: UpdateTransferRequestStatus :one
+UPDATE transfer_requests
+SET
+    status = sqlc.arg(status),
+    decided_by = sqlc.narg(decided_by),
+    reason = sqlc.arg(reason),
+    transfer_id = sqlc.narg(transfer_id),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
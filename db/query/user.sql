@@ -13,6 +13,12 @@ SELECT * FROM users
 WHERE username = $1
 LIMIT 1;
 
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: UpdateUser :one
 UPDATE users
 SET
@@ -7,13 +7,16 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname
 `
 
 type AddAccountBalanceParams struct {
@@ -30,6 +33,45 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.LedgerCode,
+		&i.Type,
+		&i.Status,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const addAccountHeldAmount = `-- name: AddAccountHeldAmount :one
+UPDATE accounts
+SET held_amount = held_amount + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname
+`
+
+type AddAccountHeldAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Account, error) {
+	row := q.db.QueryRow(ctx, addAccountHeldAmount, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.LedgerCode,
+		&i.Type,
+		&i.Status,
+		&i.Nickname,
 	)
 	return i, err
 }
@@ -38,20 +80,30 @@ const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
   owner,
   balance,
-  currency
+  currency,
+  type,
+  nickname
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+  $1, $2, $3, $4, $5
+) RETURNING id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname
 `
 
 type CreateAccountParams struct {
 	Owner    string `json:"owner"`
 	Balance  int64  `json:"balance"`
 	Currency string `json:"currency"`
+	Type     string `json:"type"`
+	Nickname string `json:"nickname"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRow(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRow(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Type,
+		arg.Nickname,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -59,22 +111,19 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.LedgerCode,
+		&i.Type,
+		&i.Status,
+		&i.Nickname,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1
-`
-
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteAccount, id)
-	return err
-}
-
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -87,12 +136,50 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.LedgerCode,
+		&i.Type,
+		&i.Status,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const getAccountByLedgerCode = `-- name: GetAccountByLedgerCode :one
+SELECT id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname FROM accounts
+WHERE owner = $1 AND currency = $2 AND ledger_code = $3 LIMIT 1
+`
+
+type GetAccountByLedgerCodeParams struct {
+	Owner      string `json:"owner"`
+	Currency   string `json:"currency"`
+	LedgerCode string `json:"ledger_code"`
+}
+
+func (q *Queries) GetAccountByLedgerCode(ctx context.Context, arg GetAccountByLedgerCodeParams) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountByLedgerCode, arg.Owner, arg.Currency, arg.LedgerCode)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.LedgerCode,
+		&i.Type,
+		&i.Status,
+		&i.Nickname,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
@@ -106,26 +193,48 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.LedgerCode,
+		&i.Type,
+		&i.Status,
+		&i.Nickname,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
-WHERE owner = $1
-ORDER BY id
-LIMIT $2
-OFFSET $3
+SELECT id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname FROM accounts
+WHERE id IN (
+    SELECT account_id FROM account_members
+    WHERE username = $1
+)
+    AND ($2::varchar IS NULL OR type = $2)
+    AND ($3::varchar IS NULL OR currency = $3)
+    AND (created_at, id) > ($4::timestamptz, $5::bigint)
+ORDER BY created_at, id
+LIMIT $6
 `
 
 type ListAccountsParams struct {
-	Owner  string `json:"owner"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	Username       string      `json:"username"`
+	Type           pgtype.Text `json:"type"`
+	Currency       pgtype.Text `json:"currency"`
+	AfterCreatedAt time.Time   `json:"after_created_at"`
+	AfterID        int64       `json:"after_id"`
+	PageSize       int32       `json:"page_size"`
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.Query(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listAccounts,
+		arg.Username,
+		arg.Type,
+		arg.Currency,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -139,6 +248,13 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.HeldAmount,
+			&i.AvailableBalance,
+			&i.LedgerCode,
+			&i.Type,
+			&i.Status,
+			&i.Nickname,
 		); err != nil {
 			return nil, err
 		}
@@ -150,20 +266,103 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
-const updateAccount = `-- name: UpdateAccount :one
+const markDormantAccounts = `-- name: MarkDormantAccounts :many
 UPDATE accounts
-SET balance = $2
-WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+SET status = 'dormant'
+WHERE status = 'active'
+    AND ledger_code = 'customer_deposits'
+    AND created_at < $1
+    AND NOT EXISTS (
+        SELECT 1 FROM entries
+        WHERE entries.account_id = accounts.id
+            AND entries.created_at >= $1
+            AND NOT EXISTS (
+                SELECT 1 FROM interest_accruals
+                WHERE interest_accruals.transfer_id = entries.transfer_id
+            )
+    )
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname
+`
+
+func (q *Queries) MarkDormantAccounts(ctx context.Context, inactiveSince time.Time) ([]Account, error) {
+	rows, err := q.db.Query(ctx, markDormantAccounts, inactiveSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.HeldAmount,
+			&i.AvailableBalance,
+			&i.LedgerCode,
+			&i.Type,
+			&i.Status,
+			&i.Nickname,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	ID             int64 `json:"id"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountOverdraftLimit, arg.OverdraftLimit, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.LedgerCode,
+		&i.Type,
+		&i.Status,
+		&i.Nickname,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, held_amount, available_balance, ledger_code, type, status, nickname
 `
 
-type UpdateAccountParams struct {
-	ID      int64 `json:"id"`
-	Balance int64 `json:"balance"`
+type UpdateAccountStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
 }
 
-func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.db.QueryRow(ctx, updateAccount, arg.ID, arg.Balance)
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountStatus, arg.Status, arg.ID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -171,6 +370,13 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.HeldAmount,
+		&i.AvailableBalance,
+		&i.LedgerCode,
+		&i.Type,
+		&i.Status,
+		&i.Nickname,
 	)
 	return i, err
 }
@@ -0,0 +1,20 @@
+package db
+
+import "github.com/billy-le/simple-bank/util"
+
+// CheckMemberTransfer fails unless the member may send amount from the
+// account. Owners and co-owners send any amount, transferors up to their
+// limit and viewers nothing.
+func CheckMemberTransfer(member AccountMember, amount int64) error {
+	switch member.Role {
+	case util.AccountMemberOwner, util.AccountMemberCoOwner:
+		return nil
+	case util.AccountMemberTransferor:
+		if amount <= member.TransferLimit.Int64 {
+			return nil
+		}
+		return ErrMemberTransferLimit
+	}
+
+	return ErrMemberCannotTransfer
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountMembers(t *testing.T) {
+	ctx := context.Background()
+	account := createRandomAccount(t)
+	coOwner := createRandomUser(t)
+
+	member, err := testStore.CreateAccountMember(ctx, CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  coOwner.Username,
+		Role:      util.AccountMemberCoOwner,
+		AddedBy:   account.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.AccountMemberCoOwner, member.Role)
+	require.False(t, member.TransferLimit.Valid)
+
+	// a shared account is listed for each of its members
+	accounts, err := testStore.ListAccounts(ctx, ListAccountsParams{
+		Username: coOwner.Username,
+		PageSize: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, account.ID, accounts[0].ID)
+
+	members, err := testStore.ListAccountMembers(ctx, account.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	require.Equal(t, account.Owner, members[0].Username)
+	require.Equal(t, coOwner.Username, members[1].Username)
+
+	// transferors need a limit
+	_, err = testStore.CreateAccountMember(ctx, CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  createRandomUser(t).Username,
+		Role:      util.AccountMemberTransferor,
+		AddedBy:   account.Owner,
+	})
+	require.Error(t, err)
+
+	err = testStore.DeleteAccountMember(ctx, DeleteAccountMemberParams{
+		AccountID: account.ID,
+		Username:  coOwner.Username,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.GetAccountMember(ctx, GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  coOwner.Username,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestCheckMemberTransfer(t *testing.T) {
+	transferor := AccountMember{
+		Role:          util.AccountMemberTransferor,
+		TransferLimit: pgtype.Int8{Int64: 100, Valid: true},
+	}
+
+	require.NoError(t, CheckMemberTransfer(AccountMember{Role: util.AccountMemberOwner}, 1_000_000))
+	require.NoError(t, CheckMemberTransfer(AccountMember{Role: util.AccountMemberCoOwner}, 1_000_000))
+	require.NoError(t, CheckMemberTransfer(transferor, 100))
+	require.ErrorIs(t, CheckMemberTransfer(transferor, 101), ErrMemberTransferLimit)
+	require.ErrorIs(t, CheckMemberTransfer(AccountMember{Role: util.AccountMemberViewer}, 1), ErrMemberCannotTransfer)
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func setAccountStatus(t *testing.T, account Account, status string) SetAccountStatusTxResult {
+	result, err := testStore.SetAccountStatusTx(context.Background(), SetAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    status,
+		Reason:    util.RandomString(12),
+		ChangedBy: createRandomUser(t).Username,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, status, result.Account.Status)
+	require.Equal(t, account.ID, result.Change.AccountID)
+	require.Equal(t, account.Status, result.Change.FromStatus)
+	require.Equal(t, status, result.Change.ToStatus)
+	require.True(t, result.Change.ChangedBy.Valid)
+	return result
+}
+
+func TestSetAccountStatusTx(t *testing.T) {
+	ctx := context.Background()
+	user := createRandomUser(t)
+	account1 := createUSDAccount(t, user.Username)
+	account2 := createUSDAccount(t, createRandomUser(t).Username)
+
+	frozen := setAccountStatus(t, account1, util.AccountStatusFrozen).Account
+
+	// a frozen account neither sends nor receives
+	_, err := testStore.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Username:      user.Username,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	_, err = testStore.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        10,
+		Username:      account2.Owner,
+	})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	unchanged, err := testStore.GetAccount(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, unchanged.Balance)
+
+	active := setAccountStatus(t, frozen, util.AccountStatusActive).Account
+
+	_, err = testStore.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Username:      user.Username,
+	})
+	require.NoError(t, err)
+
+	// money is left on the account
+	_, err = testStore.SetAccountStatusTx(ctx, SetAccountStatusTxParams{
+		AccountID: active.ID,
+		Status:    util.AccountStatusClosed,
+		Reason:    util.RandomString(12),
+		ChangedBy: user.Username,
+	})
+	require.ErrorIs(t, err, ErrAccountBalanceNotZero)
+
+	changes, err := testStore.ListAccountStatusChanges(ctx, ListAccountStatusChangesParams{
+		AccountID: account1.ID,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, util.AccountStatusFrozen, changes[0].ToStatus)
+	require.Equal(t, util.AccountStatusActive, changes[1].ToStatus)
+}
+
+func TestSetAccountStatusTxClose(t *testing.T) {
+	ctx := context.Background()
+	account1 := createZeroBalanceAccount(t)
+	account2 := createUSDAccount(t, createRandomUser(t).Username)
+
+	closed := setAccountStatus(t, account1, util.AccountStatusClosed).Account
+
+	_, err := testStore.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   closed.ID,
+		Amount:        10,
+		Username:      account2.Owner,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// a closed account stays closed
+	_, err = testStore.SetAccountStatusTx(ctx, SetAccountStatusTxParams{
+		AccountID: closed.ID,
+		Status:    util.AccountStatusActive,
+		Reason:    util.RandomString(12),
+		ChangedBy: closed.Owner,
+	})
+	require.ErrorIs(t, err, ErrAccountStatusTransition)
+}
+
+func TestMarkDormantAccountsTx(t *testing.T) {
+	ctx := context.Background()
+	user := createRandomUser(t)
+	idle := createUSDAccount(t, user.Username)
+	busy := createUSDAccount(t, createRandomUser(t).Username)
+
+	inactiveSince := time.Now().Add(time.Second)
+	time.Sleep(2 * time.Second)
+
+	_, err := testStore.TransferTx(ctx, TransferTxParams{
+		FromAccountID: busy.ID,
+		ToAccountID:   createZeroBalanceAccount(t).ID,
+		Amount:        10,
+		Username:      busy.Owner,
+	})
+	require.NoError(t, err)
+
+	accounts, err := testStore.MarkDormantAccountsTx(ctx, inactiveSince)
+	require.NoError(t, err)
+
+	dormant := make(map[int64]bool)
+	for _, account := range accounts {
+		require.Equal(t, util.AccountStatusDormant, account.Status)
+		dormant[account.ID] = true
+	}
+	require.True(t, dormant[idle.ID])
+	require.False(t, dormant[busy.ID])
+
+	changes, err := testStore.ListAccountStatusChanges(ctx, ListAccountStatusChangesParams{
+		AccountID: idle.ID,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, util.AccountStatusDormant, changes[0].ToStatus)
+	require.False(t, changes[0].ChangedBy.Valid)
+
+	// dormant accounts receive but cannot send until a banker reactivates them
+	_, err = testStore.TransferTx(ctx, TransferTxParams{
+		FromAccountID: busy.ID,
+		ToAccountID:   idle.ID,
+		Amount:        10,
+		Username:      busy.Owner,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.TransferTx(ctx, TransferTxParams{
+		FromAccountID: idle.ID,
+		ToAccountID:   busy.ID,
+		Amount:        10,
+		Username:      user.Username,
+	})
+	require.ErrorIs(t, err, ErrAccountDormant)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,15 +16,22 @@ func createRandomAccount(t *testing.T) Account {
 		Owner:    user.Username,
 		Balance:  util.RandomMoney(),
 		Currency: util.RandomCurrency(),
+		Type:     util.AccountTypeChecking,
 	}
 
-	account, err := testStore.CreateAccount(context.Background(), arg)
+	result, err := testStore.CreateAccountTx(context.Background(), arg)
 	require.NoError(t, err)
-	require.NotEmpty(t, account)
+	require.NotEmpty(t, result.Account)
+
+	account := result.Account
+	require.Equal(t, account.ID, result.Owner.AccountID)
+	require.Equal(t, arg.Owner, result.Owner.Username)
+	require.Equal(t, util.AccountMemberOwner, result.Owner.Role)
 
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.Type, account.Type)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -47,37 +55,36 @@ func TestGetAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
-func TestUpdateAccount(t *testing.T) {
+func TestUpdateAccountOverdraftLimit(t *testing.T) {
 	account1 := createRandomAccount(t)
 
-	arg := UpdateAccountParams{
-		ID:      account1.ID,
-		Balance: util.RandomMoney(),
+	arg := UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: util.RandomMoney(),
 	}
 
-	account2, err := testStore.UpdateAccount(context.Background(), arg)
+	account2, err := testStore.UpdateAccountOverdraftLimit(context.Background(), arg)
 	require.NoError(t, err)
 	require.NotEmpty(t, account2)
 
 	require.Equal(t, account1.ID, account2.ID)
-	require.Equal(t, account1.Currency, account2.Currency)
-	require.Equal(t, account1.Owner, account2.Owner)
-	require.Equal(t, arg.Balance, account2.Balance)
-
-	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
+	require.Equal(t, account1.Balance, account2.Balance)
+	require.Equal(t, arg.OverdraftLimit, account2.OverdraftLimit)
 }
 
-func TestDeleteAccount(t *testing.T) {
+func TestUpdateAccountStatus(t *testing.T) {
 	account1 := createRandomAccount(t)
+	require.Equal(t, util.AccountStatusActive, account1.Status)
 
-	err := testStore.DeleteAccount(context.Background(), account1.ID)
-
+	account2, err := testStore.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:     account1.ID,
+		Status: util.AccountStatusFrozen,
+	})
 	require.NoError(t, err)
 
-	account2, err := testStore.GetAccount(context.Background(), account1.ID)
-	require.Error(t, err)
-	require.EqualError(t, err, ErrRecordNotFound.Error())
-	require.Empty(t, account2)
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.Balance, account2.Balance)
+	require.Equal(t, util.AccountStatusFrozen, account2.Status)
 }
 
 func TestListAccounts(t *testing.T) {
@@ -87,9 +94,8 @@ func TestListAccounts(t *testing.T) {
 	}
 
 	arg := ListAccountsParams{
-		Limit:  5,
-		Offset: 0,
-		Owner:  lastAccount.Owner,
+		PageSize: 5,
+		Username: lastAccount.Owner,
 	}
 
 	accounts, err := testStore.ListAccounts(context.Background(), arg)
@@ -98,5 +104,61 @@ func TestListAccounts(t *testing.T) {
 
 	for _, account := range accounts {
 		require.NotEmpty(t, account)
+		require.Equal(t, lastAccount.Owner, account.Owner)
 	}
 }
+
+func TestAccountsPerCurrencyAndType(t *testing.T) {
+	checking := createRandomAccount(t)
+
+	savings, err := testStore.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    checking.Owner,
+		Currency: checking.Currency,
+		Type:     util.AccountTypeSavings,
+		Nickname: "rainy day",
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.AccountTypeSavings, savings.Account.Type)
+	require.Equal(t, "rainy day", savings.Account.Nickname)
+
+	// a second account of the same type in the same currency is still refused
+	_, err = testStore.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    checking.Owner,
+		Currency: checking.Currency,
+		Type:     util.AccountTypeChecking,
+	})
+	require.Error(t, err)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	accounts, err := testStore.ListAccounts(context.Background(), ListAccountsParams{
+		Username: checking.Owner,
+		Type:     pgtype.Text{String: util.AccountTypeSavings, Valid: true},
+		Currency: pgtype.Text{String: checking.Currency, Valid: true},
+		PageSize: 5,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, savings.Account.ID, accounts[0].ID)
+}
+
+func TestReopenClosedAccount(t *testing.T) {
+	closed := setAccountStatus(t, createZeroBalanceAccount(t), util.AccountStatusClosed).Account
+
+	// a closed account doesn't count against opening another of its type
+	reopened, err := testStore.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    closed.Owner,
+		Currency: closed.Currency,
+		Type:     closed.Type,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, closed.ID, reopened.Account.ID)
+	require.Equal(t, util.AccountStatusActive, reopened.Account.Status)
+
+	_, err = testStore.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    closed.Owner,
+		Currency: closed.Currency,
+		Type:     closed.Type,
+	})
+	require.Error(t, err)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+}
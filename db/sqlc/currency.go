@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/util"
+)
+
+// LoadCurrencyRegistry replaces util's currency registry with the contents of
+// the currencies table
+func LoadCurrencyRegistry(ctx context.Context, q Querier) error {
+	currencies, err := q.ListCurrencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	registry := make([]util.Currency, len(currencies))
+	for i, currency := range currencies {
+		registry[i] = util.Currency{
+			Code:              currency.Code,
+			NumericCode:       currency.NumericCode,
+			Name:              currency.Name,
+			MinorUnits:        int(currency.MinorUnits),
+			Enabled:           currency.Enabled,
+			ApprovalThreshold: currency.ApprovalThreshold,
+		}
+	}
+
+	util.SetCurrencies(registry)
+	return nil
+}
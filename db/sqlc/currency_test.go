@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testStore.ListCurrencies(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, currencies)
+
+	jpy, err := testStore.GetCurrency(context.Background(), util.JPY)
+	require.NoError(t, err)
+	require.Equal(t, "392", jpy.NumericCode)
+	require.Zero(t, jpy.MinorUnits)
+
+	bhd, err := testStore.GetCurrency(context.Background(), util.BHD)
+	require.NoError(t, err)
+	require.Equal(t, int16(3), bhd.MinorUnits)
+}
+
+func TestUpdateCurrency(t *testing.T) {
+	launched := util.Currencies()
+	defer util.SetCurrencies(launched)
+
+	currency, err := testStore.GetCurrency(context.Background(), util.BHD)
+	require.NoError(t, err)
+	defer func() {
+		_, err := testStore.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+			Code:              currency.Code,
+			Enabled:           pgtype.Bool{Bool: currency.Enabled, Valid: true},
+			ApprovalThreshold: pgtype.Int8{Int64: currency.ApprovalThreshold, Valid: true},
+		})
+		require.NoError(t, err)
+	}()
+
+	updated, err := testStore.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code:    util.BHD,
+		Enabled: pgtype.Bool{Bool: true, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, updated.Enabled)
+	require.Equal(t, currency.ApprovalThreshold, updated.ApprovalThreshold)
+
+	err = LoadCurrencyRegistry(context.Background(), testStore)
+	require.NoError(t, err)
+	require.True(t, util.IsCurrencySupported(util.BHD))
+	require.Equal(t, 3, util.MinorUnits(util.BHD))
+
+	// leaving enabled out keeps it as it is
+	updated, err = testStore.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code:              util.BHD,
+		ApprovalThreshold: pgtype.Int8{Int64: 1_000_000, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, updated.Enabled)
+	require.Equal(t, int64(1_000_000), updated.ApprovalThreshold)
+
+	err = LoadCurrencyRegistry(context.Background(), testStore)
+	require.NoError(t, err)
+	require.True(t, util.RequiresApproval(util.BHD, 1_000_001))
+	require.False(t, util.RequiresApproval(util.BHD, 1_000_000))
+}
@@ -7,43 +7,38 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createEntry = `-- name: CreateEntry :one
-INSERT INTO entries (amount, account_id)
-VALUES ($1, $2)
-RETURNING id, account_id, amount, created_at
+INSERT INTO entries (amount, account_id, transfer_id)
+VALUES ($1, $2, $3)
+RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type CreateEntryParams struct {
-	Amount    int64 `json:"amount"`
-	AccountID int64 `json:"account_id"`
+	Amount     int64       `json:"amount"`
+	AccountID  int64       `json:"account_id"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRow(ctx, createEntry, arg.Amount, arg.AccountID)
+	row := q.db.QueryRow(ctx, createEntry, arg.Amount, arg.AccountID, arg.TransferID)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
-const deleteEntry = `-- name: DeleteEntry :exec
-DELETE FROM entries
-WHERE id = $1
-`
-
-func (q *Queries) DeleteEntry(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteEntry, id)
-	return err
-}
-
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 WHERE id = $1
 LIMIT 1
 `
@@ -56,24 +51,69 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const getStatementBalances = `-- name: GetStatementBalances :one
+SELECT
+    (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= $1), 0))::bigint AS opening_balance,
+    (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= $2), 0))::bigint AS closing_balance,
+    (a.balance - COALESCE(SUM(e.amount) FILTER (WHERE e.created_at >= $1), 0)
+        + COALESCE(SUM(e.amount) FILTER (
+            WHERE e.created_at >= $1
+                AND e.created_at < $2
+                AND (e.created_at, e.id) <= ($3::timestamptz, $4::bigint)
+        ), 0))::bigint AS page_opening_balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id = $5
+GROUP BY a.id
+`
+
+type GetStatementBalancesParams struct {
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	AfterCreatedAt time.Time `json:"after_created_at"`
+	AfterID        int64     `json:"after_id"`
+	AccountID      int64     `json:"account_id"`
+}
+
+type GetStatementBalancesRow struct {
+	OpeningBalance     int64 `json:"opening_balance"`
+	ClosingBalance     int64 `json:"closing_balance"`
+	PageOpeningBalance int64 `json:"page_opening_balance"`
+}
+
+func (q *Queries) GetStatementBalances(ctx context.Context, arg GetStatementBalancesParams) (GetStatementBalancesRow, error) {
+	row := q.db.QueryRow(ctx, getStatementBalances,
+		arg.StartTime,
+		arg.EndTime,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.AccountID,
+	)
+	var i GetStatementBalancesRow
+	err := row.Scan(&i.OpeningBalance, &i.ClosingBalance, &i.PageOpeningBalance)
+	return i, err
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
-ORDER BY id
-LIMIT $1
-OFFSET $2
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
+WHERE (created_at, id) > ($1::timestamptz, $2::bigint)
+ORDER BY created_at, id
+LIMIT $3
 `
 
 type ListEntriesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	AfterCreatedAt time.Time `json:"after_created_at"`
+	AfterID        int64     `json:"after_id"`
+	PageSize       int32     `json:"page_size"`
 }
 
 func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
-	rows, err := q.db.Query(ctx, listEntries, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listEntries, arg.AfterCreatedAt, arg.AfterID, arg.PageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +126,7 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
 		); err != nil {
 			return nil, err
 		}
@@ -97,27 +138,83 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 	return items, nil
 }
 
-const updateEntry = `-- name: UpdateEntry :one
-UPDATE entries
-SET account_id = $2, amount = $3
-WHERE id = $1
-RETURNING id, account_id, amount, created_at
+const listStatementEntries = `-- name: ListStatementEntries :many
+SELECT
+    e.id,
+    e.account_id,
+    e.amount,
+    e.created_at,
+    e.transfer_id,
+    t.from_account_id,
+    t.to_account_id,
+    fa.owner AS from_account_owner,
+    ta.owner AS to_account_owner
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+LEFT JOIN accounts fa ON fa.id = t.from_account_id
+LEFT JOIN accounts ta ON ta.id = t.to_account_id
+WHERE e.account_id = $1
+    AND e.created_at >= $2
+    AND e.created_at < $3
+    AND (e.created_at, e.id) > ($4::timestamptz, $5::bigint)
+ORDER BY e.created_at, e.id
+LIMIT $6
 `
 
-type UpdateEntryParams struct {
-	ID        int64 `json:"id"`
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+type ListStatementEntriesParams struct {
+	AccountID      int64     `json:"account_id"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	AfterCreatedAt time.Time `json:"after_created_at"`
+	AfterID        int64     `json:"after_id"`
+	PageSize       int32     `json:"page_size"`
 }
 
-func (q *Queries) UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error) {
-	row := q.db.QueryRow(ctx, updateEntry, arg.ID, arg.AccountID, arg.Amount)
-	var i Entry
-	err := row.Scan(
-		&i.ID,
-		&i.AccountID,
-		&i.Amount,
-		&i.CreatedAt,
+type ListStatementEntriesRow struct {
+	ID               int64       `json:"id"`
+	AccountID        int64       `json:"account_id"`
+	Amount           int64       `json:"amount"`
+	CreatedAt        time.Time   `json:"created_at"`
+	TransferID       pgtype.Int8 `json:"transfer_id"`
+	FromAccountID    pgtype.Int8 `json:"from_account_id"`
+	ToAccountID      pgtype.Int8 `json:"to_account_id"`
+	FromAccountOwner pgtype.Text `json:"from_account_owner"`
+	ToAccountOwner   pgtype.Text `json:"to_account_owner"`
+}
+
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error) {
+	rows, err := q.db.Query(ctx, listStatementEntries,
+		arg.AccountID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageSize,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesRow{}
+	for rows.Next() {
+		var i ListStatementEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.FromAccountOwner,
+			&i.ToAccountOwner,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
@@ -51,8 +51,7 @@ func TestListEntries(t *testing.T) {
 	}
 
 	arg := ListEntriesParams{
-		Limit:  5,
-		Offset: 5,
+		PageSize: 5,
 	}
 
 	entries, err := testStore.ListEntries(context.Background(), arg)
@@ -62,38 +61,73 @@ func TestListEntries(t *testing.T) {
 	for _, entry := range entries {
 		require.NotEmpty(t, entry)
 	}
-}
 
-func TestUpdateEntry(t *testing.T) {
-	account := createRandomAccount(t)
-	entry1 := createRandomEntry(t)
-
-	arg := UpdateEntryParams{
-		ID:        entry1.ID,
-		AccountID: account.ID,
-		Amount:    util.RandomMoney(),
-	}
+	// the next page starts after the last entry of this one
+	last := entries[len(entries)-1]
+	arg.AfterCreatedAt = last.CreatedAt
+	arg.AfterID = last.ID
 
-	entry2, err := testStore.UpdateEntry(context.Background(), arg)
+	nextEntries, err := testStore.ListEntries(context.Background(), arg)
 	require.NoError(t, err)
-	require.NotEmpty(t, entry2)
-
-	require.Equal(t, entry1.ID, entry2.ID)
-	require.Equal(t, arg.AccountID, entry2.AccountID)
-	require.Equal(t, arg.Amount, entry2.Amount)
+	require.Len(t, nextEntries, 5)
 
-	require.WithinDuration(t, entry1.CreatedAt, entry2.CreatedAt, time.Second)
+	for _, entry := range nextEntries {
+		require.False(t, entry.CreatedAt.Before(last.CreatedAt))
+		require.NotContains(t, entries, entry)
+	}
 }
 
-func TestDeleteEntry(t *testing.T) {
-	entry1 := createRandomEntry(t)
+func TestAccountStatement(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	startTime := time.Now().Add(-time.Second)
+
+	n := 3
+	amount := int64(10)
+	for i := 0; i < n; i++ {
+		_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		require.NoError(t, err)
+	}
+	endTime := time.Now().Add(time.Second)
 
-	err := testStore.DeleteEntry(context.Background(), entry1.ID)
+	balances, err := testStore.GetStatementBalances(context.Background(), GetStatementBalancesParams{
+		AccountID: account1.ID,
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, balances.OpeningBalance)
+	require.Equal(t, account1.Balance-int64(n)*amount, balances.ClosingBalance)
+	require.Equal(t, balances.OpeningBalance, balances.PageOpeningBalance)
+
+	entries, err := testStore.ListStatementEntries(context.Background(), ListStatementEntriesParams{
+		AccountID: account1.ID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		PageSize:  5,
+	})
 	require.NoError(t, err)
+	require.Len(t, entries, n)
 
-	entry2, err := testStore.GetEntry(context.Background(), entry1.ID)
-	require.Error(t, err)
-	require.EqualError(t, err, ErrRecordNotFound.Error())
+	for _, entry := range entries {
+		require.Equal(t, -amount, entry.Amount)
+		require.True(t, entry.TransferID.Valid)
+		require.Equal(t, account1.ID, entry.FromAccountID.Int64)
+		require.Equal(t, account2.ID, entry.ToAccountID.Int64)
+		require.Equal(t, account2.Owner, entry.ToAccountOwner.String)
+	}
 
-	require.Empty(t, entry2)
+	// the second page starts from the balance after the first entry
+	balances, err = testStore.GetStatementBalances(context.Background(), GetStatementBalancesParams{
+		AccountID: account1.ID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		AfterID:   entries[0].ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-amount, balances.PageOpeningBalance)
 }
@@ -15,6 +15,34 @@ const (
 var ErrRecordNotFound = pgx.ErrNoRows
 var ErrTxClosed = pgx.ErrTxClosed
 var ErrUniqueViolation = &pgconn.PgError{Code: UniqueViolation}
+var ErrInsufficientFunds = errors.New("insufficient funds")
+var ErrSessionRotated = errors.New("session has already been rotated")
+var ErrIdempotencyKeyConflict = errors.New("idempotency key was already used for a different request")
+var ErrExchangeRateNotFound = errors.New("no exchange rate between the account currencies")
+var ErrConvertedAmountTooSmall = errors.New("amount is too small to convert")
+var ErrHoldNotPending = errors.New("hold is no longer pending")
+var ErrHoldExpired = errors.New("hold has expired")
+var ErrHoldNotExpired = errors.New("hold has not expired yet")
+var ErrCaptureExceedsHold = errors.New("capture amount exceeds the held amount")
+var ErrTransferAlreadyReversed = errors.New("transfer has already been fully reversed")
+var ErrReversalExceedsTransfer = errors.New("reversal amount exceeds what is left to reverse")
+var ErrCannotReverseReversal = errors.New("a reversal cannot itself be reversed")
+var ErrSystemAccountNotFound = errors.New("the bank has no account for that ledger code and currency")
+var ErrScheduleTransition = errors.New("scheduled transfer cannot move to that status")
+var ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+var ErrTransferRequestNotPending = errors.New("transfer request is no longer pending")
+var ErrTransferRequestExpired = errors.New("transfer request has expired")
+var ErrTransferRequestNotExpired = errors.New("transfer request has not expired yet")
+var ErrAccountFrozen = errors.New("account is frozen")
+var ErrAccountDormant = errors.New("account is dormant and must be reactivated by a banker")
+var ErrAccountClosed = errors.New("account is closed")
+var ErrAccountStatusTransition = errors.New("account cannot move to that status")
+var ErrAccountBalanceNotZero = errors.New("account balance must be zero to close")
+var ErrInterestNotPosted = errors.New("account has interest that has not been paid out yet")
+var ErrMemberCannotTransfer = errors.New("account member cannot transfer from the account")
+var ErrMemberTransferLimit = errors.New("amount is above the member's transfer limit on the account")
+var ErrSelfApproval = errors.New("a transfer request cannot be approved by the user who made it")
+var ErrApprovalRequired = errors.New("amount is above the currency's approval threshold and needs a banker's approval")
 
 func ErrorCode(err error) string {
 	var pgErr *pgconn.PgError
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func setExchangeRate(t *testing.T, fromCurrency string, toCurrency string, rate string, spreadBps int32) ExchangeRate {
+	user := createRandomUser(t)
+
+	var numeric pgtype.Numeric
+	require.NoError(t, numeric.Scan(rate))
+
+	arg := UpsertExchangeRateParams{
+		FromCurrency: fromCurrency,
+		ToCurrency:   toCurrency,
+		Rate:         numeric,
+		SpreadBps:    spreadBps,
+		UpdatedBy:    user.Username,
+	}
+
+	exchangeRate, err := testStore.UpsertExchangeRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.FromCurrency, exchangeRate.FromCurrency)
+	require.Equal(t, arg.ToCurrency, exchangeRate.ToCurrency)
+	require.Equal(t, arg.SpreadBps, exchangeRate.SpreadBps)
+	require.Equal(t, arg.UpdatedBy, exchangeRate.UpdatedBy)
+	require.NotZero(t, exchangeRate.UpdatedAt)
+	return exchangeRate
+}
+
+func TestUpsertExchangeRate(t *testing.T) {
+	exchangeRate1 := setExchangeRate(t, util.USD, util.CAD, "1.35", 0)
+	exchangeRate2 := setExchangeRate(t, util.USD, util.CAD, "1.36", 25)
+
+	exchangeRate, err := testStore.GetExchangeRate(context.Background(), GetExchangeRateParams{
+		FromCurrency: util.USD,
+		ToCurrency:   util.CAD,
+	})
+	require.NoError(t, err)
+	require.Equal(t, exchangeRate2.Rate, exchangeRate.Rate)
+	require.NotEqual(t, exchangeRate1.Rate, exchangeRate.Rate)
+	require.Equal(t, int32(25), exchangeRate.SpreadBps)
+
+	exchangeRates, err := testStore.ListExchangeRates(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, exchangeRates)
+}
+
+func TestDeleteExchangeRate(t *testing.T) {
+	setExchangeRate(t, util.CAD, util.EUR, "0.68", 0)
+
+	arg := DeleteExchangeRateParams{
+		FromCurrency: util.CAD,
+		ToCurrency:   util.EUR,
+	}
+	rows, err := testStore.DeleteExchangeRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	_, err = testStore.GetExchangeRate(context.Background(), GetExchangeRateParams(arg))
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	rows, err = testStore.DeleteExchangeRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TransferQuote previews what a transfer would move and cost without
+// posting it
+type TransferQuote struct {
+	Amount       int64          `json:"amount"`
+	ToAmount     int64          `json:"to_amount"`
+	ExchangeRate pgtype.Numeric `json:"exchange_rate"`
+	SpreadBps    pgtype.Int4    `json:"spread_bps"`
+	Fee          int64          `json:"fee"`
+	// TotalDebit is what leaves the source account, the amount and its fee
+	TotalDebit int64 `json:"total_debit"`
+}
+
+// QuoteTransfer works out the converted amount and the fee of a transfer at
+// the current exchange rates and fee schedules
+func (store *SQLStore) QuoteTransfer(ctx context.Context, arg TransferTxParams) (TransferQuote, error) {
+	transferArg := CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		ToAmount:      arg.Amount,
+	}
+
+	if arg.CrossCurrency {
+		err := applyExchangeRate(ctx, store.Queries, &transferArg)
+		if err != nil {
+			return TransferQuote{}, err
+		}
+	}
+
+	fromAccount, err := store.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return TransferQuote{}, err
+	}
+
+	transferType := util.TransferTypeTransfer
+	if transferArg.ExchangeRate.Valid {
+		transferType = util.TransferTypeCrossCurrency
+	}
+
+	fee, err := quoteFee(ctx, store.Queries, transferType, fromAccount, arg.Amount)
+	if err != nil {
+		return TransferQuote{}, err
+	}
+
+	quote := TransferQuote{
+		Amount:       transferArg.Amount,
+		ToAmount:     transferArg.ToAmount,
+		ExchangeRate: transferArg.ExchangeRate,
+		SpreadBps:    transferArg.SpreadBps,
+		Fee:          fee,
+		TotalDebit:   transferArg.Amount + fee,
+	}
+
+	return quote, nil
+}
+
+// quoteFee prices a transfer of amount paid by payer, using the fee schedule
+// tier for its currency and its owner's role. Without a matching schedule
+// there is no fee, and the bank never charges its own accounts.
+func quoteFee(ctx context.Context, q *Queries, transferType string, payer Account, amount int64) (int64, error) {
+	if payer.Owner == SystemAccountOwner {
+		return 0, nil
+	}
+
+	owner, err := q.GetUser(ctx, payer.Owner)
+	if err != nil {
+		return 0, err
+	}
+
+	schedule, err := q.GetFeeSchedule(ctx, GetFeeScheduleParams{
+		TransferType: transferType,
+		Currency:     payer.Currency,
+		Role:         owner.Role,
+		Amount:       amount,
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	rule := util.FeeRule{
+		FlatFee:   schedule.FlatFee,
+		RateBps:   schedule.RateBps,
+		MinFee:    schedule.MinFee,
+		MaxFee:    schedule.MaxFee.Int64,
+		HasMaxFee: schedule.MaxFee.Valid,
+	}
+
+	return rule.Fee(amount)
+}
+
+// chargeFee debits the fee for transfer from payer into the bank's fee income
+// account in the payer's currency. Each fee is a transfer of its own that
+// points back at the transfer it was charged for. It returns the fees and
+// the payer's account after paying them.
+func chargeFee(ctx context.Context, q *Queries, transferType string, transfer Transfer, payer Account) ([]Transfer, Account, error) {
+	fee, err := quoteFee(ctx, q, transferType, payer, transfer.Amount)
+	if err != nil || fee <= 0 {
+		return nil, payer, err
+	}
+
+	feeIncome, err := getSystemAccount(ctx, q, LedgerFeeIncome, payer.Currency)
+	if err != nil {
+		return nil, payer, err
+	}
+
+	posted, err := postTransfer(ctx, q, CreateTransferParams{
+		FromAccountID: payer.ID,
+		ToAccountID:   feeIncome.ID,
+		Amount:        fee,
+		ToAmount:      fee,
+		FeeOf:         pgtype.Int8{Int64: transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return nil, payer, err
+	}
+
+	return []Transfer{posted.Transfer}, posted.FromAccount, nil
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// setFeeSchedule charges depositors a fee tier until the test ends, so other
+// tests don't pay it
+func setFeeSchedule(t *testing.T, arg UpsertFeeScheduleParams) FeeSchedule {
+	arg.Role = util.DepositorRole
+	arg.UpdatedBy = createRandomUser(t).Username
+
+	schedule, err := testStore.UpsertFeeSchedule(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.TransferType, schedule.TransferType)
+	require.Equal(t, arg.MinAmount, schedule.MinAmount)
+	require.Equal(t, arg.FlatFee, schedule.FlatFee)
+	require.Equal(t, arg.RateBps, schedule.RateBps)
+	require.Equal(t, arg.MaxFee, schedule.MaxFee)
+	require.NotZero(t, schedule.UpdatedAt)
+
+	t.Cleanup(func() {
+		_, err := testStore.DeleteFeeSchedule(context.Background(), DeleteFeeScheduleParams{
+			TransferType: schedule.TransferType,
+			Currency:     schedule.Currency,
+			Role:         schedule.Role,
+			MinAmount:    schedule.MinAmount,
+		})
+		require.NoError(t, err)
+	})
+
+	return schedule
+}
+
+func TestGetFeeSchedule(t *testing.T) {
+	base := setFeeSchedule(t, UpsertFeeScheduleParams{
+		TransferType: util.TransferTypeWithdrawal,
+		Currency:     util.EUR,
+		FlatFee:      200,
+	})
+	volume := setFeeSchedule(t, UpsertFeeScheduleParams{
+		TransferType: util.TransferTypeWithdrawal,
+		Currency:     util.EUR,
+		MinAmount:    100000,
+		RateBps:      10,
+		MaxFee:       pgtype.Int8{Int64: 1000, Valid: true},
+	})
+
+	testCases := []struct {
+		amount   int64
+		expected FeeSchedule
+	}{
+		{amount: 1, expected: base},
+		{amount: 99999, expected: base},
+		{amount: 100000, expected: volume},
+		{amount: 5000000, expected: volume},
+	}
+
+	for _, testCase := range testCases {
+		schedule, err := testStore.GetFeeSchedule(context.Background(), GetFeeScheduleParams{
+			TransferType: util.TransferTypeWithdrawal,
+			Currency:     util.EUR,
+			Role:         util.DepositorRole,
+			Amount:       testCase.amount,
+		})
+		require.NoError(t, err)
+		require.Equal(t, testCase.expected.MinAmount, schedule.MinAmount)
+	}
+
+	_, err := testStore.GetFeeSchedule(context.Background(), GetFeeScheduleParams{
+		TransferType: util.TransferTypeWithdrawal,
+		Currency:     util.EUR,
+		Role:         util.BankerRole,
+		Amount:       100,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// updating a tier replaces its prices
+	updated, err := testStore.UpsertFeeSchedule(context.Background(), UpsertFeeScheduleParams{
+		TransferType: base.TransferType,
+		Currency:     base.Currency,
+		Role:         base.Role,
+		MinAmount:    base.MinAmount,
+		FlatFee:      300,
+		UpdatedBy:    base.UpdatedBy,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(300), updated.FlatFee)
+
+	schedules, err := testStore.ListFeeSchedules(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(schedules), 2)
+}
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func placeRandomHold(t *testing.T, account Account, amount int64) Hold {
+	result, err := testStore.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		CreateHoldParams: CreateHoldParams{
+			AccountID:   account.ID,
+			Amount:      amount,
+			Description: util.RandomString(10),
+			CreatedBy:   account.Owner,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		},
+	})
+	require.NoError(t, err)
+
+	hold := result.Hold
+	require.NotZero(t, hold.ID)
+	require.Equal(t, account.ID, hold.AccountID)
+	require.Equal(t, amount, hold.Amount)
+	require.Equal(t, HoldStatusPending, hold.Status)
+	require.False(t, hold.TransferID.Valid)
+	return hold
+}
+
+func TestPlaceHoldTx(t *testing.T) {
+	account := createRandomAccount(t)
+
+	hold := placeRandomHold(t, account, 10)
+
+	updatedAccount, err := testStore.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updatedAccount.Balance)
+	require.Equal(t, hold.Amount, updatedAccount.HeldAmount)
+	require.Equal(t, account.Balance-hold.Amount, updatedAccount.AvailableBalance)
+
+	// a hold cannot reserve more than is available
+	_, err = testStore.PlaceHoldTx(context.Background(), PlaceHoldTxParams{
+		CreateHoldParams: CreateHoldParams{
+			AccountID: account.ID,
+			Amount:    updatedAccount.AvailableBalance + 1,
+			CreatedBy: account.Owner,
+			ExpiresAt: time.Now().Add(time.Hour),
+		},
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// and neither can a transfer
+	account2 := createRandomAccount(t)
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   account2.ID,
+		Amount:        updatedAccount.AvailableBalance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	// the hold reserves the whole balance, and capturing it must still succeed
+	hold := placeRandomHold(t, account1, account1.Balance)
+
+	result, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID:      hold.ID,
+		ToAccountID: account2.ID,
+		Amount:      account1.Balance - 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusCaptured, result.Hold.Status)
+	require.Equal(t, account1.Balance-10, result.Hold.CapturedAmount)
+	require.Equal(t, result.Transfer.ID, result.Hold.TransferID.Int64)
+	require.Equal(t, account1.Balance-10, result.Transfer.Amount)
+
+	// the uncaptured part of the hold is released
+	require.Equal(t, int64(10), result.FromAccount.Balance)
+	require.Zero(t, result.FromAccount.HeldAmount)
+	require.Equal(t, int64(10), result.FromAccount.AvailableBalance)
+	require.Equal(t, account2.Balance+account1.Balance-10, result.ToAccount.Balance)
+
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID:      hold.ID,
+		ToAccountID: account2.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldNotPending)
+}
+
+func TestCaptureHoldTxExceedsHold(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	hold := placeRandomHold(t, account1, 10)
+
+	_, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID:      hold.ID,
+		ToAccountID: account2.ID,
+		Amount:      11,
+	})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+}
+
+func TestCaptureHoldTxTransferLimit(t *testing.T) {
+	ctx := context.Background()
+	account1 := createUSDAccount(t, createRandomUser(t).Username)
+	account2 := createUSDAccount(t, createRandomUser(t).Username)
+
+	_, err := testStore.UpsertTransferLimit(ctx, UpsertTransferLimitParams{
+		AccountID: pgtype.Int8{Int64: account1.ID, Valid: true},
+		Scope:     util.LimitScopeAccount,
+		Currency:  util.USD,
+		Period:    util.LimitPeriodDaily,
+		MaxAmount: pgtype.Int8{Int64: 100, Valid: true},
+		UpdatedBy: createRandomUser(t).Username,
+	})
+	require.NoError(t, err)
+
+	hold := placeRandomHold(t, account1, 150)
+
+	// a capture is a transfer out of the account like any other
+	_, err = testStore.CaptureHoldTx(ctx, CaptureHoldTxParams{
+		HoldID:      hold.ID,
+		ToAccountID: account2.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+	// and the hold is left as it was
+	updated, err := testStore.GetHold(ctx, hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusPending, updated.Status)
+
+	result, err := testStore.CaptureHoldTx(ctx, CaptureHoldTxParams{
+		HoldID:      hold.ID,
+		ToAccountID: account2.ID,
+		Amount:      100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), result.Transfer.Amount)
+}
+
+func TestReleaseHoldTx(t *testing.T) {
+	account := createRandomAccount(t)
+	hold := placeRandomHold(t, account, 10)
+
+	_, err := testStore.ReleaseHoldTx(context.Background(), ReleaseHoldTxParams{
+		HoldID: hold.ID,
+		Status: HoldStatusExpired,
+	})
+	require.ErrorIs(t, err, ErrHoldNotExpired)
+
+	result, err := testStore.ReleaseHoldTx(context.Background(), ReleaseHoldTxParams{
+		HoldID: hold.ID,
+		Status: HoldStatusReleased,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusReleased, result.Hold.Status)
+	require.Zero(t, result.Hold.CapturedAmount)
+	require.Zero(t, result.Account.HeldAmount)
+	require.Equal(t, account.Balance, result.Account.AvailableBalance)
+
+	_, err = testStore.ReleaseHoldTx(context.Background(), ReleaseHoldTxParams{
+		HoldID: hold.ID,
+		Status: HoldStatusReleased,
+	})
+	require.ErrorIs(t, err, ErrHoldNotPending)
+
+	holds, err := testStore.ListAccountHolds(context.Background(), ListAccountHoldsParams{
+		AccountID: account.ID,
+		PageSize:  5,
+	})
+	require.NoError(t, err)
+	require.Len(t, holds, 1)
+}
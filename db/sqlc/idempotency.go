@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// IdempotencyKeyRetention is how long the result of a request is replayed
+// when its idempotency key is reused.
+const IdempotencyKeyRetention = 24 * time.Hour
+
+// claimIdempotencyKey reserves key for request inside the caller's transaction.
+// When the key was already used for the same request, the stored result is
+// decoded into response and replayed is true.
+func claimIdempotencyKey(ctx context.Context, q *Queries, username string, key string, request any, response any) (replayed bool, err error) {
+	requestHash, err := hashRequest(request)
+	if err != nil {
+		return false, err
+	}
+
+	expiresAt := time.Now().Add(IdempotencyKeyRetention)
+
+	// a concurrent insert of the same key waits here until the other transaction
+	// finishes, so a duplicate either sees the committed row or takes over the key
+	_, err = q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Username:    username,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   expiresAt,
+	})
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, ErrRecordNotFound) {
+		return false, err
+	}
+
+	existing, err := q.GetIdempotencyKeyForUpdate(ctx, GetIdempotencyKeyForUpdateParams{
+		Username: username,
+		Key:      key,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if time.Now().After(existing.ExpiresAt) {
+		_, err = q.ResetIdempotencyKey(ctx, ResetIdempotencyKeyParams{
+			RequestHash: requestHash,
+			ExpiresAt:   expiresAt,
+			Username:    username,
+			Key:         key,
+		})
+		return false, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return false, ErrIdempotencyKeyConflict
+	}
+
+	return true, json.Unmarshal(existing.Response, response)
+}
+
+func saveIdempotentResponse(ctx context.Context, q *Queries, username string, key string, response any) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	return q.UpdateIdempotencyKeyResponse(ctx, UpdateIdempotencyKeyResponseParams{
+		Response: data,
+		Username: username,
+		Key:      key,
+	})
+}
+
+func hashRequest(request any) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
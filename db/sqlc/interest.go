@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AccrueInterest records a day of interest for every account whose product
+// pays interest, on the balance the account held at the end of that day in
+// UTC. A day that was already accrued for an account is skipped, so running
+// it again is harmless. It returns how many accruals it recorded.
+func AccrueInterest(ctx context.Context, q Querier, day time.Time) (int, error) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+
+	balances, err := q.ListInterestBearingBalances(ctx, day.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+
+	accrued := 0
+	for _, balance := range balances {
+		interest, err := util.DailyInterest(balance.Balance, balance.InterestRateBps, balance.DayCount, day)
+		if err != nil {
+			return accrued, err
+		}
+
+		var amount pgtype.Numeric
+		if err := amount.Scan(interest.FloatString(10)); err != nil {
+			return accrued, err
+		}
+
+		rows, err := q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:   balance.AccountID,
+			AccrualDate: pgtype.Date{Time: day, Valid: true},
+			Balance:     balance.Balance,
+			RateBps:     balance.InterestRateBps,
+			DayCount:    balance.DayCount,
+			Amount:      amount,
+		})
+		if err != nil {
+			return accrued, err
+		}
+		accrued += int(rows)
+	}
+
+	return accrued, nil
+}
+
+type PostInterestTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Before is the first day whose interest is left for a later posting
+	Before time.Time `json:"before"`
+}
+
+type PostInterestTxResult struct {
+	// Transfer is the zero value when the accruals add up to less than the
+	// minor unit, in which case they are left for the next posting
+	Transfer Transfer          `json:"transfer"`
+	Account  Account           `json:"account"`
+	Accruals []InterestAccrual `json:"accruals"`
+}
+
+// PostInterestTx pays an account the interest it accrued before a date with a
+// transfer from the bank's interest expense account in its currency. The
+// total is rounded down to the minor unit, and the fraction left over is
+// carried into an accrual of its own for the next posting.
+func (store *SQLStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		var total *big.Rat
+		result.Accruals, total, err = unpostedInterest(ctx, q, arg.AccountID, arg.Before)
+		if err != nil {
+			return err
+		}
+		amount := new(big.Int).Quo(total.Num(), total.Denom())
+
+		ids := make([]int64, len(result.Accruals))
+		for i, accrual := range result.Accruals {
+			ids[i] = accrual.ID
+		}
+
+		if amount.Sign() <= 0 {
+			return nil
+		}
+
+		result.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		interestExpense, err := getSystemAccount(ctx, q, LedgerInterestExpense, result.Account.Currency)
+		if err != nil {
+			return err
+		}
+
+		posted, err := postTransfer(ctx, q, CreateTransferParams{
+			FromAccountID: interestExpense.ID,
+			ToAccountID:   result.Account.ID,
+			Amount:        amount.Int64(),
+			ToAmount:      amount.Int64(),
+		})
+		if err != nil {
+			return err
+		}
+		result.Transfer, result.Account = posted.Transfer, posted.ToAccount
+
+		_, err = q.SetInterestAccrualsTransfer(ctx, SetInterestAccrualsTransferParams{
+			TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+			Ids:        ids,
+		})
+		if err != nil {
+			return err
+		}
+
+		remainder := new(big.Rat).Sub(total, new(big.Rat).SetInt(amount))
+		if remainder.Sign() <= 0 {
+			return nil
+		}
+
+		var carry pgtype.Numeric
+		if err := carry.Scan(remainder.FloatString(10)); err != nil {
+			return err
+		}
+
+		// the carry is dated like the last accrual it comes from, so the
+		// next posting picks it up with the accruals after it
+		last := result.Accruals[len(result.Accruals)-1]
+		_, err = q.CreateInterestCarry(ctx, CreateInterestCarryParams{
+			AccountID:   last.AccountID,
+			AccrualDate: last.AccrualDate,
+			Balance:     last.Balance,
+			RateBps:     last.RateBps,
+			DayCount:    last.DayCount,
+			Amount:      carry,
+			CarriedFrom: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// unpostedInterest locks the accruals of an account from before a day that
+// have not been paid out, and returns them with the interest they add up to
+func unpostedInterest(ctx context.Context, q *Queries, accountID int64, before time.Time) ([]InterestAccrual, *big.Rat, error) {
+	accruals, err := q.ListUnpostedInterestAccrualsForUpdate(ctx, ListUnpostedInterestAccrualsForUpdateParams{
+		AccountID: accountID,
+		Before:    pgtype.Date{Time: before, Valid: true},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	total := new(big.Rat)
+	for _, accrual := range accruals {
+		total.Add(total, numericToRat(accrual.Amount))
+	}
+
+	return accruals, total, nil
+}
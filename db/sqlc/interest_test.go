@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createSavingsAccount(t *testing.T, balance int64) Account {
+	account, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Balance:  balance,
+		Currency: util.USD,
+		Type:     util.AccountTypeSavings,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.AccountTypeSavings, account.Type)
+	return account
+}
+
+func listUnpostedAccruals(t *testing.T, accountID int64, before time.Time) []InterestAccrual {
+	accruals, err := testStore.ListUnpostedInterestAccrualsForUpdate(context.Background(), ListUnpostedInterestAccrualsForUpdateParams{
+		AccountID: accountID,
+		Before:    pgtype.Date{Time: before, Valid: true},
+	})
+	require.NoError(t, err)
+	return accruals
+}
+
+func TestInterest(t *testing.T) {
+	ctx := context.Background()
+	account := createSavingsAccount(t, 1_000_000)
+	checking := createRandomAccount(t)
+
+	product, err := testStore.GetAccountProduct(ctx, util.AccountTypeSavings)
+	require.NoError(t, err)
+	require.Positive(t, product.InterestRateBps)
+
+	// accrue today, the only day the account has been open
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	accrued, err := AccrueInterest(ctx, testStore, today)
+	require.NoError(t, err)
+	require.Positive(t, accrued)
+
+	accruals := listUnpostedAccruals(t, account.ID, tomorrow)
+	require.Len(t, accruals, 1)
+	require.Equal(t, account.Balance, accruals[0].Balance)
+	require.Equal(t, product.InterestRateBps, accruals[0].RateBps)
+
+	expected, err := util.DailyInterest(account.Balance, product.InterestRateBps, product.DayCount, today)
+	require.NoError(t, err)
+	require.Equal(t, expected.FloatString(10), numericToRat(accruals[0].Amount).FloatString(10))
+
+	// checking accounts earn nothing
+	require.Empty(t, listUnpostedAccruals(t, checking.ID, tomorrow))
+
+	// accruing the same day again changes nothing
+	_, err = AccrueInterest(ctx, testStore, today)
+	require.NoError(t, err)
+	require.Len(t, listUnpostedAccruals(t, account.ID, tomorrow), 1)
+
+	// interest accrued today is not paid before tomorrow
+	result, err := testStore.PostInterestTx(ctx, PostInterestTxParams{AccountID: account.ID, Before: today})
+	require.NoError(t, err)
+	require.Zero(t, result.Transfer.ID)
+
+	result, err = testStore.PostInterestTx(ctx, PostInterestTxParams{AccountID: account.ID, Before: tomorrow})
+	require.NoError(t, err)
+	require.NotZero(t, result.Transfer.ID)
+	require.Len(t, result.Accruals, 1)
+
+	// the payout is rounded down to the minor unit
+	paid := new(big.Int).Quo(expected.Num(), expected.Denom()).Int64()
+	require.Equal(t, paid, result.Transfer.Amount)
+	require.Equal(t, account.Balance+paid, result.Account.Balance)
+	require.Equal(t, account.ID, result.Transfer.ToAccountID)
+
+	interestExpense, err := testStore.GetAccountByLedgerCode(ctx, GetAccountByLedgerCodeParams{
+		Owner:      SystemAccountOwner,
+		Currency:   util.USD,
+		LedgerCode: LedgerInterestExpense,
+	})
+	require.NoError(t, err)
+	require.Equal(t, interestExpense.ID, result.Transfer.FromAccountID)
+
+	// only the fraction of a cent rounded off is left, carried for next time
+	unposted := listUnpostedAccruals(t, account.ID, tomorrow)
+	require.Len(t, unposted, 1)
+	require.Equal(t, result.Transfer.ID, unposted[0].CarriedFrom.Int64)
+}
+
+func TestPostInterestTxBelowMinorUnit(t *testing.T) {
+	ctx := context.Background()
+	account := createSavingsAccount(t, 100)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	_, err := AccrueInterest(ctx, testStore, today)
+	require.NoError(t, err)
+
+	// a fraction of a cent is left to add up with later accruals
+	result, err := testStore.PostInterestTx(ctx, PostInterestTxParams{AccountID: account.ID, Before: tomorrow})
+	require.NoError(t, err)
+	require.Zero(t, result.Transfer.ID)
+	require.Len(t, listUnpostedAccruals(t, account.ID, tomorrow), 1)
+
+	updated, err := testStore.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updated.Balance)
+}
+
+func TestPostInterestTxCarriesRemainder(t *testing.T) {
+	ctx := context.Background()
+	// 1,000,000 at 1.5% a year earns 41.0958... cents a day
+	account := createSavingsAccount(t, 1_000_000)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	tomorrow := today.AddDate(0, 0, 1)
+	dayAfter := today.AddDate(0, 0, 2)
+
+	accrued := new(big.Rat)
+	paid := int64(0)
+
+	for _, day := range []time.Time{today, tomorrow} {
+		_, err := AccrueInterest(ctx, testStore, day)
+		require.NoError(t, err)
+
+		next := day.AddDate(0, 0, 1)
+		for _, accrual := range listUnpostedAccruals(t, account.ID, next) {
+			if !accrual.CarriedFrom.Valid {
+				accrued.Add(accrued, numericToRat(accrual.Amount))
+			}
+		}
+
+		result, err := testStore.PostInterestTx(ctx, PostInterestTxParams{AccountID: account.ID, Before: next})
+		require.NoError(t, err)
+		require.NotZero(t, result.Transfer.ID)
+		paid += result.Transfer.Amount
+	}
+	require.NotEqual(t, 0, accrued.Cmp(new(big.Rat).SetInt64(paid)))
+
+	// what was paid and the carry left over add up to every accrual
+	unposted := listUnpostedAccruals(t, account.ID, dayAfter)
+	require.Len(t, unposted, 1)
+	require.True(t, unposted[0].CarriedFrom.Valid)
+
+	carry := numericToRat(unposted[0].Amount)
+	require.Positive(t, carry.Sign())
+	require.Negative(t, carry.Cmp(big.NewRat(1, 1)))
+	require.Zero(t, accrued.Cmp(new(big.Rat).Add(new(big.Rat).SetInt64(paid), carry)))
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SystemAccountOwner owns the bank's internal accounts, one per currency for
+// every ledger code in the chart of accounts but customer deposits
+const SystemAccountOwner = "simple_bank"
+
+// Ledger codes in the chart of accounts. Entries are signed from the ledger's
+// point of view: a negative amount is a debit and a positive one a credit.
+const (
+	LedgerCash             = "cash"
+	LedgerSuspense         = "suspense"
+	LedgerCustomerDeposits = "customer_deposits"
+	LedgerFXPosition       = "fx_position"
+	LedgerFeeIncome        = "fee_income"
+	LedgerInterestExpense  = "interest_expense"
+)
+
+// getSystemAccount finds the bank's account for a ledger code in a currency
+func getSystemAccount(ctx context.Context, q *Queries, ledgerCode string, currency string) (Account, error) {
+	account, err := q.GetAccountByLedgerCode(ctx, GetAccountByLedgerCodeParams{
+		Owner:      SystemAccountOwner,
+		Currency:   currency,
+		LedgerCode: ledgerCode,
+	})
+	if errors.Is(err, ErrRecordNotFound) {
+		return account, ErrSystemAccountNotFound
+	}
+	return account, err
+}
+
+// LedgerReport lists where the stored balances disagree with the entries
+// behind them
+type LedgerReport struct {
+	AccountMismatches  []ListAccountBalanceMismatchesRow `json:"account_mismatches"`
+	TransferMismatches []ListTransferEntryMismatchesRow  `json:"transfer_mismatches"`
+	JournalMismatches  []ListUnbalancedJournalsRow       `json:"journal_mismatches"`
+}
+
+// CheckLedger verifies that every account balance is the sum of its entries,
+// that every transfer debits its amount from the source account and credits
+// its to_amount to the destination, and that the entries of every transfer
+// balance in each currency
+func CheckLedger(ctx context.Context, q Querier) (LedgerReport, error) {
+	var report LedgerReport
+	var err error
+
+	report.AccountMismatches, err = q.ListAccountBalanceMismatches(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	report.TransferMismatches, err = q.ListTransferEntryMismatches(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	report.JournalMismatches, err = q.ListUnbalancedJournals(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func (report LedgerReport) Balanced() bool {
+	return len(report.AccountMismatches) == 0 && len(report.TransferMismatches) == 0 && len(report.JournalMismatches) == 0
+}
+
+// Discrepancies describes each mismatch in a line of its own
+func (report LedgerReport) Discrepancies() []string {
+	var lines []string
+
+	for _, account := range report.AccountMismatches {
+		lines = append(lines, fmt.Sprintf(
+			"account %d (%s, %s) has balance %d but its entries add up to %d, off by %d",
+			account.AccountID, account.Owner, account.Currency, account.Balance, account.EntriesTotal, account.Balance-account.EntriesTotal,
+		))
+	}
+
+	for _, transfer := range report.TransferMismatches {
+		lines = append(lines, fmt.Sprintf(
+			"transfer %d from account %d to %d should debit %d and credit %d in %d entries but its %d entries debit %d and credit %d",
+			transfer.TransferID, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount, transfer.ToAmount,
+			transfer.ExpectedEntryCount, transfer.EntryCount, -transfer.FromEntriesTotal, transfer.ToEntriesTotal,
+		))
+	}
+
+	for _, journal := range report.JournalMismatches {
+		lines = append(lines, fmt.Sprintf(
+			"transfer %d entries in %s add up to %d instead of zero",
+			journal.TransferID, journal.Currency, journal.EntriesTotal,
+		))
+	}
+
+	return lines
+}
+
+// TrialBalance totals the debits and credits posted to each ledger code in a
+// single currency
+type TrialBalance struct {
+	Currency     string                `json:"currency"`
+	Lines        []ListTrialBalanceRow `json:"lines"`
+	TotalDebits  int64                 `json:"total_debits"`
+	TotalCredits int64                 `json:"total_credits"`
+}
+
+func (balance TrialBalance) Balanced() bool {
+	return balance.TotalDebits == balance.TotalCredits
+}
+
+// GetTrialBalance builds a trial balance per currency from the entries posted
+// up to asOf, or from every entry when asOf is null
+func GetTrialBalance(ctx context.Context, q Querier, asOf pgtype.Timestamptz) ([]TrialBalance, error) {
+	rows, err := q.ListTrialBalance(ctx, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := []TrialBalance{}
+	for _, row := range rows {
+		// rows come ordered by currency
+		if len(balances) == 0 || balances[len(balances)-1].Currency != row.Currency {
+			balances = append(balances, TrialBalance{Currency: row.Currency})
+		}
+
+		balance := &balances[len(balances)-1]
+		balance.Lines = append(balance.Lines, row)
+		balance.TotalDebits += row.Debits
+		balance.TotalCredits += row.Credits
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLedger(t *testing.T) {
+	account1 := createZeroBalanceAccount(t)
+	account2 := createZeroBalanceAccount(t)
+
+	report, err := CheckLedger(context.Background(), testStore)
+	require.NoError(t, err)
+	require.NotContains(t, mismatchedAccountIDs(report), account1.ID)
+	require.NotContains(t, mismatchedAccountIDs(report), account2.ID)
+
+	// a balance change without an entry
+	_, err = testStore.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account1.ID,
+		Amount: 10,
+	})
+	require.NoError(t, err)
+
+	// a transfer with only one side recorded
+	transfer, err := testStore.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        5,
+		ToAmount:      5,
+	})
+	require.NoError(t, err)
+	_, err = testStore.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID:  account1.ID,
+		Amount:     -5,
+		TransferID: pgtype.Int8{Int64: transfer.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	report, err = CheckLedger(context.Background(), testStore)
+	require.NoError(t, err)
+	require.False(t, report.Balanced())
+	require.NotEmpty(t, report.Discrepancies())
+
+	require.Contains(t, mismatchedAccountIDs(report), account1.ID)
+	require.NotContains(t, mismatchedAccountIDs(report), account2.ID)
+
+	var found bool
+	for _, mismatch := range report.TransferMismatches {
+		if mismatch.TransferID == transfer.ID {
+			found = true
+			require.Equal(t, int32(1), mismatch.EntryCount)
+			require.Equal(t, int64(-5), mismatch.FromEntriesTotal)
+			require.Zero(t, mismatch.ToEntriesTotal)
+		}
+	}
+	require.True(t, found)
+
+	found = false
+	for _, mismatch := range report.JournalMismatches {
+		if mismatch.TransferID == transfer.ID {
+			found = true
+			require.Equal(t, int64(-5), mismatch.EntriesTotal)
+		}
+	}
+	require.True(t, found)
+}
+
+func createZeroBalanceAccount(t *testing.T) Account {
+	account, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Balance:  0,
+		Currency: util.USD,
+		Type:     util.AccountTypeChecking,
+	})
+	require.NoError(t, err)
+	return account
+}
+
+func mismatchedAccountIDs(report LedgerReport) []int64 {
+	ids := make([]int64, len(report.AccountMismatches))
+	for i, account := range report.AccountMismatches {
+		ids[i] = account.AccountID
+	}
+	return ids
+}
+
+func TestGetTrialBalance(t *testing.T) {
+	account := createZeroBalanceAccount(t)
+	amount := int64(25)
+
+	before, err := GetTrialBalance(context.Background(), testStore, pgtype.Timestamptz{})
+	require.NoError(t, err)
+
+	_, err = testStore.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	})
+	require.NoError(t, err)
+
+	after, err := GetTrialBalance(context.Background(), testStore, pgtype.Timestamptz{})
+	require.NoError(t, err)
+
+	// the deposit debits cash and credits customer deposits by the same amount
+	beforeLines := trialBalanceLines(before, account.Currency)
+	afterLines := trialBalanceLines(after, account.Currency)
+	require.Equal(t, beforeLines[LedgerCash].Debits+amount, afterLines[LedgerCash].Debits)
+	require.Equal(t, beforeLines[LedgerCustomerDeposits].Credits+amount, afterLines[LedgerCustomerDeposits].Credits)
+	require.Equal(t, LedgerCash, afterLines[LedgerCash].LedgerCode)
+	require.Equal(t, "asset", afterLines[LedgerCash].Type)
+	require.Equal(t, "liability", afterLines[LedgerCustomerDeposits].Type)
+}
+
+func trialBalanceLines(balances []TrialBalance, currency string) map[string]ListTrialBalanceRow {
+	lines := make(map[string]ListTrialBalanceRow)
+	for _, balance := range balances {
+		if balance.Currency != currency {
+			continue
+		}
+		for _, line := range balance.Lines {
+			lines[line.LedgerCode] = line
+		}
+	}
+	return lines
+}
+
+func TestCheckLedgerEntryCount(t *testing.T) {
+	account1 := createZeroBalanceAccount(t)
+	account2 := createZeroBalanceAccount(t)
+
+	transfer, err := testStore.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        5,
+		ToAmount:      5,
+	})
+	require.NoError(t, err)
+
+	// both sides add up, but the credit was posted twice and taken back once
+	for _, arg := range []CreateEntryParams{
+		{AccountID: account1.ID, Amount: -5},
+		{AccountID: account2.ID, Amount: 5},
+		{AccountID: account2.ID, Amount: 5},
+		{AccountID: account2.ID, Amount: -5},
+	} {
+		arg.TransferID = pgtype.Int8{Int64: transfer.ID, Valid: true}
+		_, err = testStore.CreateEntry(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	report, err := CheckLedger(context.Background(), testStore)
+	require.NoError(t, err)
+
+	var found bool
+	for _, mismatch := range report.TransferMismatches {
+		if mismatch.TransferID == transfer.ID {
+			found = true
+			require.Equal(t, int32(4), mismatch.EntryCount)
+			require.Equal(t, int32(2), mismatch.ExpectedEntryCount)
+			require.Equal(t, int64(-5), mismatch.FromEntriesTotal)
+			require.Equal(t, int64(5), mismatch.ToEntriesTotal)
+		}
+	}
+	require.True(t, found)
+}
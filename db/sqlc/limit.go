@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TransferUsage is how much of a velocity limit the transfers in its current
+// window have used
+type TransferUsage struct {
+	Scope    string `json:"scope"`
+	Period   string `json:"period"`
+	Currency string `json:"currency"`
+	// Limit is the zero value when no limit applies
+	Limit    TransferLimit `json:"limit"`
+	Amount   int64         `json:"amount"`
+	Count    int32         `json:"count"`
+	ResetsAt time.Time     `json:"resets_at"`
+}
+
+// RemainingAmount returns how much more can be sent before the window resets,
+// ok is false when the amount is not limited
+func (usage TransferUsage) RemainingAmount() (remaining int64, ok bool) {
+	if !usage.Limit.MaxAmount.Valid {
+		return 0, false
+	}
+	return max(usage.Limit.MaxAmount.Int64-usage.Amount, 0), true
+}
+
+// RemainingCount returns how many more transfers can be made before the
+// window resets, ok is false when the count is not limited
+func (usage TransferUsage) RemainingCount() (remaining int32, ok bool) {
+	if !usage.Limit.MaxCount.Valid {
+		return 0, false
+	}
+	return max(usage.Limit.MaxCount.Int32-usage.Count, 0), true
+}
+
+func (usage TransferUsage) Exceeded() bool {
+	return (usage.Limit.MaxAmount.Valid && usage.Amount > usage.Limit.MaxAmount.Int64) ||
+		(usage.Limit.MaxCount.Valid && usage.Count > usage.Limit.MaxCount.Int32)
+}
+
+// TransferLimitError reports the velocity limit a transfer would go over,
+// with the usage as it was before the transfer
+type TransferLimitError struct {
+	AccountID int64
+	Owner     string
+	Usage     TransferUsage
+}
+
+func (err *TransferLimitError) Error() string {
+	return fmt.Sprintf("transfer exceeds the %s %s limit", err.Usage.Period, err.Usage.Scope)
+}
+
+func (err *TransferLimitError) Unwrap() error {
+	return ErrTransferLimitExceeded
+}
+
+// GetTransferUsages lists the daily and monthly limits on transfers out of an
+// account, both its own and its owner's across their accounts in the
+// currency, with how much of each is used at now. The bank's own accounts are
+// not limited.
+func GetTransferUsages(ctx context.Context, q Querier, account Account, now time.Time) ([]TransferUsage, error) {
+	if account.Owner == SystemAccountOwner {
+		return []TransferUsage{}, nil
+	}
+
+	owner, err := q.GetUser(ctx, account.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return transferUsages(ctx, q, owner.Role, account, now)
+}
+
+func transferUsages(ctx context.Context, q Querier, role string, account Account, now time.Time) ([]TransferUsage, error) {
+	usages := []TransferUsage{}
+
+	for _, scope := range []string{util.LimitScopeAccount, util.LimitScopeUser} {
+		for _, period := range []string{util.LimitPeriodDaily, util.LimitPeriodMonthly} {
+			since, until := util.LimitWindow(period, now)
+			usage := TransferUsage{
+				Scope:    scope,
+				Period:   period,
+				Currency: account.Currency,
+				ResetsAt: until,
+			}
+
+			// a limit set for the user or the account overrides the one for
+			// the role
+			limit, err := q.GetEffectiveTransferLimit(ctx, GetEffectiveTransferLimitParams{
+				Scope:     scope,
+				Currency:  account.Currency,
+				Period:    period,
+				Role:      role,
+				Username:  pgtype.Text{String: account.Owner, Valid: true},
+				AccountID: pgtype.Int8{Int64: account.ID, Valid: true},
+			})
+			if err != nil && !errors.Is(err, ErrRecordNotFound) {
+				return nil, err
+			}
+			usage.Limit = limit
+
+			if scope == util.LimitScopeAccount {
+				used, err := q.GetAccountTransferUsage(ctx, GetAccountTransferUsageParams{
+					AccountID: account.ID,
+					Since:     since,
+				})
+				if err != nil {
+					return nil, err
+				}
+				usage.Amount, usage.Count = used.Amount, used.Count
+			} else {
+				used, err := q.GetUserTransferUsage(ctx, GetUserTransferUsageParams{
+					Owner:    account.Owner,
+					Currency: account.Currency,
+					Since:    since,
+				})
+				if err != nil {
+					return nil, err
+				}
+				usage.Amount, usage.Count = used.Amount, used.Count
+			}
+
+			usages = append(usages, usage)
+		}
+	}
+
+	return usages, nil
+}
+
+// checkTransferLimits fails with a TransferLimitError when transfer, posted in
+// the open transaction, took its source account or that account's owner over
+// a velocity limit
+func checkTransferLimits(ctx context.Context, q *Queries, transfer Transfer, fromAccount Account) error {
+	if fromAccount.Owner == SystemAccountOwner {
+		return nil
+	}
+
+	// locking the owner serializes transfers out of their different accounts,
+	// so two of them cannot both fit under a user limit with room for one.
+	// The source account is already locked, and always before the owner.
+	owner, err := q.GetUserForUpdate(ctx, fromAccount.Owner)
+	if err != nil {
+		return err
+	}
+
+	usages, err := transferUsages(ctx, q, owner.Role, fromAccount, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, usage := range usages {
+		if usage.Exceeded() {
+			usage.Amount -= transfer.Amount
+			usage.Count--
+			return &TransferLimitError{
+				AccountID: fromAccount.ID,
+				Owner:     fromAccount.Owner,
+				Usage:     usage,
+			}
+		}
+	}
+
+	return nil
+}
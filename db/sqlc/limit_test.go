@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createUSDAccount(t *testing.T, owner string) Account {
+	account, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    owner,
+		Balance:  10000,
+		Currency: util.USD,
+		Type:     util.AccountTypeChecking,
+	})
+	require.NoError(t, err)
+	return account
+}
+
+func TestTransferTxAccountLimit(t *testing.T) {
+	ctx := context.Background()
+	account1 := createUSDAccount(t, createRandomUser(t).Username)
+	account2 := createUSDAccount(t, createRandomUser(t).Username)
+
+	_, err := testStore.UpsertTransferLimit(ctx, UpsertTransferLimitParams{
+		AccountID: pgtype.Int8{Int64: account1.ID, Valid: true},
+		Scope:     util.LimitScopeAccount,
+		Currency:  util.USD,
+		Period:    util.LimitPeriodDaily,
+		MaxAmount: pgtype.Int8{Int64: 100, Valid: true},
+		MaxCount:  pgtype.Int4{Int32: 2, Valid: true},
+		UpdatedBy: createRandomUser(t).Username,
+	})
+	require.NoError(t, err)
+
+	transfer := func(amount int64) error {
+		_, err := testStore.TransferTx(ctx, TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+
+	require.NoError(t, transfer(60))
+
+	// 60 + 50 goes over the amount
+	err = transfer(50)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+	var limitErr *TransferLimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, account1.ID, limitErr.AccountID)
+	require.Equal(t, util.LimitScopeAccount, limitErr.Usage.Scope)
+	require.Equal(t, util.LimitPeriodDaily, limitErr.Usage.Period)
+	require.Equal(t, int64(60), limitErr.Usage.Amount)
+	require.Equal(t, int32(1), limitErr.Usage.Count)
+
+	remaining, ok := limitErr.Usage.RemainingAmount()
+	require.True(t, ok)
+	require.Equal(t, int64(40), remaining)
+
+	require.NoError(t, transfer(40))
+
+	// the amount is used up and so is the count
+	err = transfer(1)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+	usages, err := GetTransferUsages(ctx, testStore, account1, time.Now())
+	require.NoError(t, err)
+	require.Len(t, usages, 4)
+	require.Equal(t, util.LimitScopeAccount, usages[0].Scope)
+	require.Equal(t, util.LimitPeriodDaily, usages[0].Period)
+	require.Equal(t, int64(100), usages[0].Amount)
+	require.Equal(t, int32(2), usages[0].Count)
+
+	count, ok := usages[0].RemainingCount()
+	require.True(t, ok)
+	require.Zero(t, count)
+
+	// the monthly limit is not set, so it has nothing to run out of
+	_, ok = usages[1].RemainingAmount()
+	require.False(t, ok)
+	require.Equal(t, int64(100), usages[1].Amount)
+
+	// the failed transfers left nothing behind
+	updated, err := testStore.GetAccount(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-100, updated.Balance)
+}
+
+func TestTransferTxUserLimit(t *testing.T) {
+	ctx := context.Background()
+	owner := createRandomUser(t)
+	account := createUSDAccount(t, owner.Username)
+	recipient := createUSDAccount(t, createRandomUser(t).Username)
+
+	_, err := testStore.UpsertTransferLimit(ctx, UpsertTransferLimitParams{
+		Username:  pgtype.Text{String: owner.Username, Valid: true},
+		Scope:     util.LimitScopeUser,
+		Currency:  util.USD,
+		Period:    util.LimitPeriodMonthly,
+		MaxCount:  pgtype.Int4{Int32: 1, Valid: true},
+		UpdatedBy: createRandomUser(t).Username,
+	})
+	require.NoError(t, err)
+
+	transfer := func() error {
+		_, err := testStore.TransferTx(ctx, TransferTxParams{
+			FromAccountID: account.ID,
+			ToAccountID:   recipient.ID,
+			Amount:        10,
+		})
+		return err
+	}
+
+	require.NoError(t, transfer())
+
+	err = transfer()
+	var limitErr *TransferLimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, util.LimitScopeUser, limitErr.Usage.Scope)
+	require.Equal(t, util.LimitPeriodMonthly, limitErr.Usage.Period)
+	require.Equal(t, owner.Username, limitErr.Owner)
+	require.Equal(t, int32(1), limitErr.Usage.Count)
+
+	// the recipient's own limits are untouched
+	usages, err := GetTransferUsages(ctx, testStore, recipient, time.Now())
+	require.NoError(t, err)
+	for _, usage := range usages {
+		require.Zero(t, usage.Limit.ID)
+		require.Zero(t, usage.Count)
+	}
+}
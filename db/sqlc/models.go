@@ -8,14 +8,81 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccountMember struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+	// owner, co_owner, viewer or transferor; the owner is the account's owner and every member can see the account
+	Role string `json:"role"`
+	// largest transfer a transferor may make from the account, null for every other role
+	TransferLimit pgtype.Int8 `json:"transfer_limit"`
+	AddedBy       string      `json:"added_by"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type AccountProduct struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// annual interest rate, in basis points
+	InterestRateBps int32 `json:"interest_rate_bps"`
+	// act_365 or 30_360
+	DayCount  string    `json:"day_count"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type AccountStatusChange struct {
+	ID         int64  `json:"id"`
+	AccountID  int64  `json:"account_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+	// banker or owner who made the change, null when the bank marked the account dormant
+	ChangedBy pgtype.Text `json:"changed_by"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
 type Account struct {
 	ID        int64     `json:"id"`
 	Owner     string    `json:"owner"`
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// how far below zero the balance may go
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	// sum of the pending holds on the account
+	HeldAmount       int64 `json:"held_amount"`
+	AvailableBalance int64 `json:"available_balance"`
+	// where the account sits in the chart of accounts
+	LedgerCode string `json:"ledger_code"`
+	// account product, which sets the interest the account earns
+	Type string `json:"type"`
+	// active, frozen, dormant or closed; only active accounts send money and frozen or closed ones receive none
+	Status string `json:"status"`
+	// name the owner gave the account, empty when it has none
+	Nickname string `json:"nickname"`
+}
+
+type ChartOfAccount struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// asset, liability, equity, income or expense
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Currency struct {
+	Code        string `json:"code"`
+	NumericCode string `json:"numeric_code"`
+	Name        string `json:"name"`
+	// ISO 4217 exponent, the number of decimal places
+	MinorUnits int16 `json:"minor_units"`
+	// whether new accounts and transfers may use the currency
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	// transfers of more than this need a banker's approval, 0 turns approvals off
+	ApprovalThreshold int64 `json:"approval_threshold"`
 }
 
 type Entry struct {
@@ -24,6 +91,116 @@ type Entry struct {
 	// can be negative or positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// transfer that created the entry, if any
+	TransferID pgtype.Int8 `json:"transfer_id"`
+}
+
+type ExchangeRate struct {
+	FromCurrency string `json:"from_currency"`
+	ToCurrency   string `json:"to_currency"`
+	// units of to_currency per unit of from_currency
+	Rate pgtype.Numeric `json:"rate"`
+	// margin taken off the converted amount, in basis points
+	SpreadBps int32     `json:"spread_bps"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type FeeSchedule struct {
+	// transfer, cross_currency, deposit or withdrawal
+	TransferType string `json:"transfer_type"`
+	Currency     string `json:"currency"`
+	Role         string `json:"role"`
+	// smallest amount the tier applies to, the tier with the highest min_amount not above the amount wins
+	MinAmount int64 `json:"min_amount"`
+	FlatFee   int64 `json:"flat_fee"`
+	// percentage of the amount charged on top of flat_fee, in basis points
+	RateBps int32 `json:"rate_bps"`
+	MinFee  int64 `json:"min_fee"`
+	// no cap when null
+	MaxFee    pgtype.Int8 `json:"max_fee"`
+	UpdatedBy string      `json:"updated_by"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type Hold struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+	// pending, captured, released or expired
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	CreatedBy   string `json:"created_by"`
+	// transfer the hold was captured into
+	TransferID     pgtype.Int8 `json:"transfer_id"`
+	CapturedAmount int64       `json:"captured_amount"`
+	ExpiresAt      time.Time   `json:"expires_at"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+type IdempotencyKey struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+	// sha256 of the request the key was first used with
+	RequestHash string    `json:"request_hash"`
+	Response    []byte    `json:"response"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type InterestAccrual struct {
+	ID          int64       `json:"id"`
+	AccountID   int64       `json:"account_id"`
+	AccrualDate pgtype.Date `json:"accrual_date"`
+	// end-of-day balance the interest was computed on
+	Balance  int64  `json:"balance"`
+	RateBps  int32  `json:"rate_bps"`
+	DayCount string `json:"day_count"`
+	// interest earned for the day, in fractions of the minor unit
+	Amount pgtype.Numeric `json:"amount"`
+	// transfer that paid the interest out, null until it is posted
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	CreatedAt  time.Time   `json:"created_at"`
+	// posting that left this fraction of a minor unit over for the next one, null for a day's own accrual
+	CarriedFrom pgtype.Int8 `json:"carried_from"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	CrossCurrency bool   `json:"cross_currency"`
+	// once, daily, weekly, monthly or cron
+	Rule string `json:"rule"`
+	// five field cron expression, for the cron rule
+	CronExpression string `json:"cron_expression"`
+	// first occurrence, or the only one for the once rule
+	StartAt time.Time `json:"start_at"`
+	// no occurrence is run after this time
+	EndAt pgtype.Timestamptz `json:"end_at"`
+	// null once the schedule has no occurrences left
+	NextRunAt pgtype.Timestamptz `json:"next_run_at"`
+	Status    string             `json:"status"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	// member or banker who set the schedule up; a member's schedule is paused once they leave the account
+	CreatedBy string `json:"created_by"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64 `json:"id"`
+	ScheduledTransferID int64 `json:"scheduled_transfer_id"`
+	// occurrence this run executes
+	ScheduledFor time.Time   `json:"scheduled_for"`
+	Status       string      `json:"status"`
+	TransferID   pgtype.Int8 `json:"transfer_id"`
+	// why the transfer failed or was skipped
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Session struct {
@@ -35,6 +212,51 @@ type Session struct {
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
+	// id of the session created at login, shared by every rotated session
+	FamilyID  uuid.UUID `json:"family_id"`
+	IsRotated bool      `json:"is_rotated"`
+}
+
+type TransferLimit struct {
+	ID int64 `json:"id"`
+	// set for the default limit of every user with the role
+	Role pgtype.Text `json:"role"`
+	// set when a banker overrides the user limit of one user
+	Username pgtype.Text `json:"username"`
+	// set when a banker overrides the limit of one account
+	AccountID pgtype.Int8 `json:"account_id"`
+	// account limits add up the transfers out of one account, user limits those out of all the user's accounts in the currency
+	Scope    string `json:"scope"`
+	Currency string `json:"currency"`
+	// daily or monthly, in UTC
+	Period string `json:"period"`
+	// no amount limit when null
+	MaxAmount pgtype.Int8 `json:"max_amount"`
+	// no count limit when null
+	MaxCount  pgtype.Int4 `json:"max_count"`
+	UpdatedBy string      `json:"updated_by"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type TransferRequest struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	Amount        int64 `json:"amount"`
+	CrossCurrency bool  `json:"cross_currency"`
+	// pending, approved, rejected or expired
+	Status string `json:"status"`
+	// the maker, who can never approve the request
+	RequestedBy string `json:"requested_by"`
+	// the banker who approved or rejected the request
+	DecidedBy pgtype.Text `json:"decided_by"`
+	// why the request was rejected
+	Reason string `json:"reason"`
+	// transfer made when the request was approved
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
 }
 
 type Transfer struct {
@@ -44,6 +266,19 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// amount credited to the destination account, in its currency
+	ToAmount int64 `json:"to_amount"`
+	// rate applied to a cross-currency transfer
+	ExchangeRate pgtype.Numeric `json:"exchange_rate"`
+	SpreadBps    pgtype.Int4    `json:"spread_bps"`
+	// transfer this one compensates, if it is a reversal
+	ReversalOf pgtype.Int8 `json:"reversal_of"`
+	// why the reversal was made
+	ReasonCode pgtype.Text `json:"reason_code"`
+	// part of amount that has been reversed so far
+	ReversedAmount int64 `json:"reversed_amount"`
+	// transfer this one charged a fee for, if it is a fee
+	FeeOf pgtype.Int8 `json:"fee_of"`
 }
 
 type User struct {
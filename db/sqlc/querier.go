@@ -6,35 +6,106 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddAccountHeldAmount(ctx context.Context, arg AddAccountHeldAmountParams) (Account, error)
+	AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error)
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error
+	BlockUserSessions(ctx context.Context, username string) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error)
+	CreateAccountStatusChange(ctx context.Context, arg CreateAccountStatusChangeParams) (AccountStatusChange, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (int64, error)
+	CreateInterestCarry(ctx context.Context, arg CreateInterestCarryParams) (InterestAccrual, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferRequest(ctx context.Context, arg CreateTransferRequestParams) (TransferRequest, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
-	DeleteAccount(ctx context.Context, id int64) error
-	DeleteEntry(ctx context.Context, id int64) error
-	DeleteTransfer(ctx context.Context, id int64) error
+	DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) error
+	DeleteExchangeRate(ctx context.Context, arg DeleteExchangeRateParams) (int64, error)
+	DeleteFeeSchedule(ctx context.Context, arg DeleteFeeScheduleParams) (int64, error)
+	DeleteTransferLimit(ctx context.Context, id int64) (int64, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountByLedgerCode(ctx context.Context, arg GetAccountByLedgerCodeParams) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error)
+	GetAccountProduct(ctx context.Context, code string) (AccountProduct, error)
+	GetAccountTransferUsage(ctx context.Context, arg GetAccountTransferUsageParams) (GetAccountTransferUsageRow, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
+	GetEffectiveTransferLimit(ctx context.Context, arg GetEffectiveTransferLimitParams) (TransferLimit, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetExchangeRate(ctx context.Context, arg GetExchangeRateParams) (ExchangeRate, error)
+	GetFeeSchedule(ctx context.Context, arg GetFeeScheduleParams) (FeeSchedule, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKeyForUpdate(ctx context.Context, arg GetIdempotencyKeyForUpdateParams) (IdempotencyKey, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferRun(ctx context.Context, id int64) (ScheduledTransferRun, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetStatementBalances(ctx context.Context, arg GetStatementBalancesParams) (GetStatementBalancesRow, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferRequest(ctx context.Context, id int64) (TransferRequest, error)
+	GetTransferRequestForUpdate(ctx context.Context, id int64) (TransferRequest, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	GetUserTransferUsage(ctx context.Context, arg GetUserTransferUsageParams) (GetUserTransferUsageRow, error)
+	ListAccountBalanceMismatches(ctx context.Context) ([]ListAccountBalanceMismatchesRow, error)
+	ListAccountHolds(ctx context.Context, arg ListAccountHoldsParams) ([]Hold, error)
+	ListAccountMembers(ctx context.Context, accountID int64) ([]AccountMember, error)
+	ListAccountProducts(ctx context.Context) ([]AccountProduct, error)
+	ListAccountStatusChanges(ctx context.Context, arg ListAccountStatusChangesParams) ([]AccountStatusChange, error)
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsWithUnpostedInterest(ctx context.Context, before pgtype.Date) ([]int64, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	ListDueScheduledTransfers(ctx context.Context, arg ListDueScheduledTransfersParams) ([]ScheduledTransfer, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListExchangeRates(ctx context.Context) ([]ExchangeRate, error)
+	ListFeeSchedules(ctx context.Context) ([]FeeSchedule, error)
+	ListInterestBearingBalances(ctx context.Context, dayEnd time.Time) ([]ListInterestBearingBalancesRow, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ListSessions(ctx context.Context, username string) ([]Session, error)
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	ListTransferEntryMismatches(ctx context.Context) ([]ListTransferEntryMismatchesRow, error)
+	ListTransferLimits(ctx context.Context) ([]TransferLimit, error)
+	ListTransferRequests(ctx context.Context, arg ListTransferRequestsParams) ([]TransferRequest, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
-	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
-	UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error)
-	UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error)
+	ListTrialBalance(ctx context.Context, asOf pgtype.Timestamptz) ([]ListTrialBalanceRow, error)
+	ListUnbalancedJournals(ctx context.Context) ([]ListUnbalancedJournalsRow, error)
+	ListUnpostedInterestAccrualsForUpdate(ctx context.Context, arg ListUnpostedInterestAccrualsForUpdateParams) ([]InterestAccrual, error)
+	MarkDormantAccounts(ctx context.Context, inactiveSince time.Time) ([]Account, error)
+	ResetIdempotencyKey(ctx context.Context, arg ResetIdempotencyKeyParams) (IdempotencyKey, error)
+	RotateSession(ctx context.Context, id uuid.UUID) (Session, error)
+	SetInterestAccrualsTransfer(ctx context.Context, arg SetInterestAccrualsTransferParams) (int64, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateAccountProduct(ctx context.Context, arg UpdateAccountProductParams) (AccountProduct, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (Currency, error)
+	UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) (Hold, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) error
+	UpdateScheduledTransferRun(ctx context.Context, arg UpdateScheduledTransferRunParams) (ScheduledTransferRun, error)
+	UpdateScheduledTransferStatus(ctx context.Context, arg UpdateScheduledTransferStatusParams) (ScheduledTransfer, error)
+	UpdateTransferRequestStatus(ctx context.Context, arg UpdateTransferRequestStatusParams) (TransferRequest, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateVerifyEmail(ctx context.Context, arg UpdateVerifyEmailParams) (VerifyEmail, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error)
+	UpsertFeeSchedule(ctx context.Context, arg UpsertFeeScheduleParams) (FeeSchedule, error)
+	UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimit, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomScheduledTransfer(t *testing.T, rule string, startAt time.Time) ScheduledTransfer {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	arg := CreateScheduledTransferParams{
+		Owner:         account1.Owner,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        util.RandomMoney(),
+		Rule:          rule,
+		StartAt:       startAt,
+		NextRunAt:     pgtype.Timestamptz{Time: startAt, Valid: true},
+		CreatedBy:     account1.Owner,
+	}
+
+	schedule, err := testStore.CreateScheduledTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Owner, schedule.Owner)
+	require.Equal(t, arg.CreatedBy, schedule.CreatedBy)
+	require.Equal(t, arg.Rule, schedule.Rule)
+	require.Equal(t, ScheduledTransferStatusActive, schedule.Status)
+	require.WithinDuration(t, startAt, schedule.NextRunAt.Time, time.Second)
+
+	return schedule
+}
+
+func TestScheduleDueTransfersTx(t *testing.T) {
+	startAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	daily := createRandomScheduledTransfer(t, util.ScheduleDaily, startAt)
+	once := createRandomScheduledTransfer(t, util.ScheduleOnce, startAt)
+	later := createRandomScheduledTransfer(t, util.ScheduleOnce, time.Now().Add(time.Hour))
+
+	var created []ScheduledTransferRun
+	result, err := testStore.ScheduleDueTransfersTx(context.Background(), ScheduleDueTransfersTxParams{
+		Now:       time.Now(),
+		BatchSize: 1000,
+		AfterCreate: func(run ScheduledTransferRun) error {
+			created = append(created, run)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, created, result.Runs)
+
+	runs := make(map[int64]ScheduledTransferRun)
+	for _, run := range result.Runs {
+		runs[run.ScheduledTransferID] = run
+	}
+	require.Contains(t, runs, daily.ID)
+	require.Contains(t, runs, once.ID)
+	require.NotContains(t, runs, later.ID)
+	require.Equal(t, ScheduledTransferRunStatusPending, runs[daily.ID].Status)
+	require.WithinDuration(t, startAt, runs[daily.ID].ScheduledFor, time.Second)
+
+	daily, err = testStore.GetScheduledTransfer(context.Background(), daily.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusActive, daily.Status)
+	require.WithinDuration(t, startAt.AddDate(0, 0, 1), daily.NextRunAt.Time, time.Second)
+
+	once, err = testStore.GetScheduledTransfer(context.Background(), once.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusCompleted, once.Status)
+	require.False(t, once.NextRunAt.Valid)
+}
+
+func TestSetScheduledTransferStatusTx(t *testing.T) {
+	startAt := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+	schedule := createRandomScheduledTransfer(t, util.ScheduleDaily, startAt)
+
+	_, err := testStore.SetScheduledTransferStatusTx(context.Background(), SetScheduledTransferStatusTxParams{
+		ID:     schedule.ID,
+		Status: ScheduledTransferStatusActive,
+		Now:    time.Now(),
+	})
+	require.ErrorIs(t, err, ErrScheduleTransition)
+
+	paused, err := testStore.SetScheduledTransferStatusTx(context.Background(), SetScheduledTransferStatusTxParams{
+		ID:     schedule.ID,
+		Status: ScheduledTransferStatusPaused,
+		Now:    time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusPaused, paused.Status)
+
+	// resuming skips the occurrences missed while paused
+	now := time.Now()
+	resumed, err := testStore.SetScheduledTransferStatusTx(context.Background(), SetScheduledTransferStatusTxParams{
+		ID:     schedule.ID,
+		Status: ScheduledTransferStatusActive,
+		Now:    now,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusActive, resumed.Status)
+	require.True(t, resumed.NextRunAt.Time.After(now))
+	require.WithinDuration(t, startAt.AddDate(0, 0, 3), resumed.NextRunAt.Time, 24*time.Hour)
+
+	cancelled, err := testStore.SetScheduledTransferStatusTx(context.Background(), SetScheduledTransferStatusTxParams{
+		ID:     schedule.ID,
+		Status: ScheduledTransferStatusCancelled,
+		Now:    time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusCancelled, cancelled.Status)
+	require.False(t, cancelled.NextRunAt.Valid)
+
+	_, err = testStore.SetScheduledTransferStatusTx(context.Background(), SetScheduledTransferStatusTxParams{
+		ID:     schedule.ID,
+		Status: ScheduledTransferStatusPaused,
+		Now:    time.Now(),
+	})
+	require.ErrorIs(t, err, ErrScheduleTransition)
+}
+
+func TestListScheduledTransfers(t *testing.T) {
+	ctx := context.Background()
+	schedule := createRandomScheduledTransfer(t, util.ScheduleDaily, time.Now().Add(time.Hour))
+	coOwner := createRandomUser(t)
+
+	_, err := testStore.CreateAccountMember(ctx, CreateAccountMemberParams{
+		AccountID: schedule.FromAccountID,
+		Username:  coOwner.Username,
+		Role:      util.AccountMemberCoOwner,
+		AddedBy:   schedule.Owner,
+	})
+	require.NoError(t, err)
+
+	// the schedules of a shared account are listed for each of its members
+	for _, username := range []string{schedule.Owner, coOwner.Username} {
+		schedules, err := testStore.ListScheduledTransfers(ctx, ListScheduledTransfersParams{
+			Username: username,
+			PageSize: 5,
+		})
+		require.NoError(t, err)
+		require.Len(t, schedules, 1)
+		require.Equal(t, schedule.ID, schedules[0].ID)
+	}
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, user User) Session {
+	id := uuid.New()
+	arg := CreateSessionParams{
+		ID:           id,
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    util.RandomString(10),
+		ClientIp:     "127.0.0.1",
+		ExpiresAt:    time.Now().Add(time.Hour),
+		FamilyID:     id,
+	}
+
+	session, err := testStore.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, session)
+
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.Username, session.Username)
+	require.Equal(t, arg.FamilyID, session.FamilyID)
+	require.False(t, session.IsBlocked)
+	require.False(t, session.IsRotated)
+
+	return session
+}
+
+func TestRotateSessionTx(t *testing.T) {
+	user := createRandomUser(t)
+	oldSession := createRandomSession(t, user)
+
+	arg := RotateSessionTxParams{
+		OldSessionID: oldSession.ID,
+		CreateSessionParams: CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			ExpiresAt:    oldSession.ExpiresAt,
+		},
+	}
+
+	result, err := testStore.RotateSessionTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, result.Session.ID)
+	require.Equal(t, oldSession.FamilyID, result.Session.FamilyID)
+
+	rotated, err := testStore.GetSession(context.Background(), oldSession.ID)
+	require.NoError(t, err)
+	require.True(t, rotated.IsRotated)
+
+	// rotating the same session twice is refresh token reuse
+	arg.ID = uuid.New()
+	_, err = testStore.RotateSessionTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSessionRotated)
+
+	err = testStore.BlockSessionFamily(context.Background(), oldSession.FamilyID)
+	require.NoError(t, err)
+
+	for _, id := range []uuid.UUID{oldSession.ID, result.Session.ID} {
+		session, err := testStore.GetSession(context.Background(), id)
+		require.NoError(t, err)
+		require.True(t, session.IsBlocked)
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 3; i++ {
+		createRandomSession(t, user)
+	}
+
+	sessions, err := testStore.ListSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+
+	for _, session := range sessions {
+		require.Equal(t, user.Username, session.Username)
+	}
+}
+
+func TestBlockUserSessions(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 3; i++ {
+		createRandomSession(t, user)
+	}
+
+	blocked, err := testStore.BlockUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), blocked)
+
+	sessions, err := testStore.ListSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -9,8 +10,25 @@ import (
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	QuoteTransfer(ctx context.Context, arg TransferTxParams) (TransferQuote, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountParams) (CreateAccountTxResult, error)
 	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
 	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error)
+	PlaceHoldTx(ctx context.Context, arg PlaceHoldTxParams) (PlaceHoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	ReleaseHoldTx(ctx context.Context, arg ReleaseHoldTxParams) (ReleaseHoldTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error)
+	ScheduleDueTransfersTx(ctx context.Context, arg ScheduleDueTransfersTxParams) (ScheduleDueTransfersTxResult, error)
+	SetScheduledTransferStatusTx(ctx context.Context, arg SetScheduledTransferStatusTxParams) (ScheduledTransfer, error)
+	PostInterestTx(ctx context.Context, arg PostInterestTxParams) (PostInterestTxResult, error)
+	CreateTransferRequestTx(ctx context.Context, arg CreateTransferRequestTxParams) (TransferRequest, error)
+	ApproveTransferRequestTx(ctx context.Context, arg ApproveTransferRequestTxParams) (ApproveTransferRequestTxResult, error)
+	CloseTransferRequestTx(ctx context.Context, arg CloseTransferRequestTxParams) (TransferRequest, error)
+	SetAccountStatusTx(ctx context.Context, arg SetAccountStatusTxParams) (SetAccountStatusTxResult, error)
+	MarkDormantAccountsTx(ctx context.Context, inactiveSince time.Time) ([]Account, error)
 }
 
 type SQLStore struct {
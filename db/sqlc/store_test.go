@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -55,6 +57,7 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, fromEntry)
 		require.Equal(t, account1.ID, fromEntry.AccountID)
 		require.Equal(t, -amount, fromEntry.Amount)
+		require.Equal(t, transfer.ID, fromEntry.TransferID.Int64)
 		require.NotZero(t, fromEntry.ID)
 		require.NotZero(t, fromEntry.CreatedAt)
 
@@ -65,6 +68,7 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, toEntry)
 		require.Equal(t, account2.ID, toEntry.AccountID)
 		require.Equal(t, amount, toEntry.Amount)
+		require.Equal(t, transfer.ID, toEntry.TransferID.Int64)
 		require.NotZero(t, toEntry.ID)
 		require.NotZero(t, toEntry.CreatedAt)
 
@@ -143,3 +147,460 @@ func TestTransferTxDeadlock(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	updatedAccount1, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := testStore.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+
+	// an overdraft limit lets the balance go below zero, but no further
+	overdraftLimit := int64(10)
+	_, err = testStore.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: overdraftLimit,
+	})
+	require.NoError(t, err)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + overdraftLimit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, -overdraftLimit, result.FromAccount.Balance)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestTransferTxIdempotencyKey(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	n := 5
+	amount := int64(10)
+	arg := TransferTxParams{
+		FromAccountID:  account1.ID,
+		ToAccountID:    account2.ID,
+		Amount:         amount,
+		Username:       account1.Owner,
+		IdempotencyKey: util.RandomString(16),
+	}
+
+	errs := make(chan error)
+	results := make(chan TransferTxResult)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := testStore.TransferTx(context.Background(), arg)
+
+			errs <- err
+			results <- result
+		}()
+	}
+
+	var transferID int64
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+
+		result := <-results
+		require.NotZero(t, result.Transfer.ID)
+		if transferID == 0 {
+			transferID = result.Transfer.ID
+		}
+		require.Equal(t, transferID, result.Transfer.ID)
+	}
+
+	// the money only moved once
+	updatedAccount1, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-amount, updatedAccount1.Balance)
+
+	// reusing the key for a different request is rejected
+	arg.Amount = amount + 1
+	_, err = testStore.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyConflict)
+}
+
+func TestTransferTxCrossCurrency(t *testing.T) {
+	createAccount := func(currency string) Account {
+		account, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    createRandomUser(t).Username,
+			Balance:  1000,
+			Currency: currency,
+			Type:     util.AccountTypeChecking,
+		})
+		require.NoError(t, err)
+		return account
+	}
+
+	account1 := createAccount(util.EUR)
+	account2 := createAccount(util.USD)
+
+	arg := TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		CrossCurrency: true,
+	}
+
+	_, err := testStore.DeleteExchangeRate(context.Background(), DeleteExchangeRateParams{
+		FromCurrency: util.EUR,
+		ToCurrency:   util.USD,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrExchangeRateNotFound)
+
+	// 100 * 1.085 less 1% spread is 107.415, rounded down
+	exchangeRate := setExchangeRate(t, util.EUR, util.USD, "1.085", 100)
+
+	result, err := testStore.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), result.Transfer.Amount)
+	require.Equal(t, int64(107), result.Transfer.ToAmount)
+	require.True(t, result.Transfer.ExchangeRate.Valid)
+	require.Equal(t, exchangeRate.SpreadBps, result.Transfer.SpreadBps.Int32)
+	require.Equal(t, int64(-100), result.FromEntry.Amount)
+	require.Equal(t, int64(107), result.ToEntry.Amount)
+	require.Equal(t, account1.Balance-100, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+107, result.ToAccount.Balance)
+
+	// the FX position accounts take the other side in each currency
+	journals, err := testStore.ListUnbalancedJournals(context.Background())
+	require.NoError(t, err)
+	for _, journal := range journals {
+		require.NotEqual(t, result.Transfer.ID, journal.TransferID)
+	}
+
+	// too small to be worth anything once converted
+	arg.Amount = 1
+	arg.ToAccountID, arg.FromAccountID = account1.ID, account2.ID
+	setExchangeRate(t, util.USD, util.EUR, "0.9", 0)
+	_, err = testStore.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrConvertedAmountTooSmall)
+}
+
+func TestReverseTransferTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	transferResult, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	original := transferResult.Transfer
+
+	// partial refund
+	result, err := testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.ID,
+		Amount:     4,
+		ReasonCode: util.ReasonCodeCustomerRequest,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), result.OriginalTransfer.ReversedAmount)
+
+	reversal := result.Transfer
+	require.Equal(t, account2.ID, reversal.FromAccountID)
+	require.Equal(t, account1.ID, reversal.ToAccountID)
+	require.Equal(t, int64(4), reversal.Amount)
+	require.Equal(t, int64(4), reversal.ToAmount)
+	require.Equal(t, original.ID, reversal.ReversalOf.Int64)
+	require.Equal(t, util.ReasonCodeCustomerRequest, reversal.ReasonCode.String)
+	require.Equal(t, int64(-4), result.FromEntry.Amount)
+	require.Equal(t, int64(4), result.ToEntry.Amount)
+	require.Equal(t, account1.Balance-6, result.ToAccount.Balance)
+	require.Equal(t, account2.Balance+6, result.FromAccount.Balance)
+
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.ID,
+		Amount:     7,
+		ReasonCode: util.ReasonCodeCustomerRequest,
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: reversal.ID,
+		ReasonCode: util.ReasonCodeProcessingError,
+	})
+	require.ErrorIs(t, err, ErrCannotReverseReversal)
+
+	// zero reverses the remainder
+	result, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.ID,
+		ReasonCode: util.ReasonCodeDuplicate,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(6), result.Transfer.Amount)
+	require.Equal(t, original.Amount, result.OriginalTransfer.ReversedAmount)
+	require.Equal(t, account1.Balance, result.ToAccount.Balance)
+	require.Equal(t, account2.Balance, result.FromAccount.Balance)
+
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: original.ID,
+		ReasonCode: util.ReasonCodeDuplicate,
+	})
+	require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+}
+
+func TestReverseTransferTxAccountStatus(t *testing.T) {
+	ctx := context.Background()
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	transferResult, err := testStore.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	original := transferResult.Transfer
+
+	reverse := func() error {
+		_, err := testStore.ReverseTransferTx(ctx, ReverseTransferTxParams{
+			TransferID: original.ID,
+			ReasonCode: util.ReasonCodeCustomerRequest,
+		})
+		return err
+	}
+
+	setStatus := func(account Account, status string) {
+		_, err := testStore.UpdateAccountStatus(ctx, UpdateAccountStatusParams{ID: account.ID, Status: status})
+		require.NoError(t, err)
+	}
+
+	// a closed account cannot be credited back
+	setStatus(account1, util.AccountStatusClosed)
+	require.ErrorIs(t, reverse(), ErrAccountClosed)
+
+	// nor can a frozen one be debited
+	setStatus(account1, util.AccountStatusActive)
+	setStatus(account2, util.AccountStatusFrozen)
+	require.ErrorIs(t, reverse(), ErrAccountFrozen)
+
+	updated, err := testStore.GetAccount(ctx, account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, transferResult.FromAccount.Balance, updated.Balance)
+
+	setStatus(account2, util.AccountStatusActive)
+	require.NoError(t, reverse())
+}
+
+func TestReverseTransferTxCrossCurrency(t *testing.T) {
+	createAccount := func(currency string) Account {
+		account, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    createRandomUser(t).Username,
+			Balance:  1000,
+			Currency: currency,
+			Type:     util.AccountTypeChecking,
+		})
+		require.NoError(t, err)
+		return account
+	}
+
+	account1 := createAccount(util.EUR)
+	account2 := createAccount(util.USD)
+	setExchangeRate(t, util.EUR, util.USD, "1.085", 100)
+
+	transferResult, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		CrossCurrency: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(107), transferResult.Transfer.ToAmount)
+
+	// the refunds are taken back in proportion and add up to what was credited
+	var refunded int64
+	for _, amount := range []int64{33, 33, 34} {
+		result, err := testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+			TransferID: transferResult.Transfer.ID,
+			Amount:     amount,
+			ReasonCode: util.ReasonCodeCustomerRequest,
+		})
+		require.NoError(t, err)
+		require.Equal(t, amount, result.Transfer.ToAmount)
+		refunded += result.Transfer.Amount
+	}
+	require.Equal(t, int64(107), refunded)
+
+	account1, err = testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), account1.Balance)
+
+	account2, err = testStore.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), account2.Balance)
+}
+
+func TestDepositWithdrawTx(t *testing.T) {
+	account := createRandomAccount(t)
+	amount := int64(10)
+
+	cashAccount, err := testStore.GetAccountByLedgerCode(context.Background(), GetAccountByLedgerCodeParams{
+		Owner:      SystemAccountOwner,
+		Currency:   account.Currency,
+		LedgerCode: LedgerCash,
+	})
+	require.NoError(t, err)
+
+	deposit, err := testStore.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, cashAccount.ID, deposit.Transfer.FromAccountID)
+	require.Equal(t, account.ID, deposit.Transfer.ToAccountID)
+	require.Equal(t, account.Balance+amount, deposit.Account.Balance)
+	require.Equal(t, amount, deposit.Entry.Amount)
+	require.Equal(t, cashAccount.Balance-amount, deposit.CashAccount.Balance)
+	require.Equal(t, -amount, deposit.CashEntry.Amount)
+
+	withdrawal, err := testStore.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, withdrawal.Transfer.FromAccountID)
+	require.Equal(t, cashAccount.ID, withdrawal.Transfer.ToAccountID)
+	require.Equal(t, account.Balance, withdrawal.Account.Balance)
+	require.Equal(t, -amount, withdrawal.Entry.Amount)
+	require.Equal(t, amount, withdrawal.CashEntry.Amount)
+
+	// withdrawals respect the overdraft limit like any other debit
+	_, err = testStore.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    account.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	updatedAccount, err := testStore.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, updatedAccount.Balance)
+}
+
+func TestDepositTxFeeOverDeposit(t *testing.T) {
+	account := createZeroBalanceAccount(t)
+
+	setFeeSchedule(t, UpsertFeeScheduleParams{
+		TransferType: util.TransferTypeDeposit,
+		Currency:     util.USD,
+		FlatFee:      50,
+	})
+
+	// the fee would leave the account 40 below an overdraft limit of 0
+	_, err := testStore.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    10,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	updatedAccount, err := testStore.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, updatedAccount.Balance)
+}
+
+func TestWithdrawTxTransferLimit(t *testing.T) {
+	ctx := context.Background()
+	account := createUSDAccount(t, createRandomUser(t).Username)
+
+	_, err := testStore.UpsertTransferLimit(ctx, UpsertTransferLimitParams{
+		AccountID: pgtype.Int8{Int64: account.ID, Valid: true},
+		Scope:     util.LimitScopeAccount,
+		Currency:  util.USD,
+		Period:    util.LimitPeriodDaily,
+		MaxCount:  pgtype.Int4{Int32: 1, Valid: true},
+		UpdatedBy: createRandomUser(t).Username,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.WithdrawTx(ctx, CashTxParams{AccountID: account.ID, Amount: 10})
+	require.NoError(t, err)
+
+	_, err = testStore.WithdrawTx(ctx, CashTxParams{AccountID: account.ID, Amount: 10})
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+}
+
+func TestTransferTxFees(t *testing.T) {
+	createAccount := func() Account {
+		account, err := testStore.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    createRandomUser(t).Username,
+			Balance:  10000,
+			Currency: util.USD,
+			Type:     util.AccountTypeChecking,
+		})
+		require.NoError(t, err)
+		return account
+	}
+
+	account1 := createAccount()
+	account2 := createAccount()
+
+	// 1% with a 50 minimum
+	setFeeSchedule(t, UpsertFeeScheduleParams{
+		TransferType: util.TransferTypeTransfer,
+		Currency:     util.USD,
+		RateBps:      100,
+		MinFee:       50,
+	})
+
+	quote, err := testStore.QuoteTransfer(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(50), quote.Fee)
+	require.Equal(t, int64(1050), quote.TotalDebit)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1000,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Fees, 1)
+
+	fee := result.Fees[0]
+	require.Equal(t, int64(50), fee.Amount)
+	require.Equal(t, account1.ID, fee.FromAccountID)
+	require.Equal(t, result.Transfer.ID, fee.FeeOf.Int64)
+	require.Equal(t, account1.Balance-1050, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+1000, result.ToAccount.Balance)
+
+	feeIncome, err := testStore.GetAccount(context.Background(), fee.ToAccountID)
+	require.NoError(t, err)
+	require.Equal(t, SystemAccountOwner, feeIncome.Owner)
+	require.Equal(t, LedgerFeeIncome, feeIncome.LedgerCode)
+
+	// the fee counts towards the funds a transfer needs
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        result.FromAccount.Balance,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
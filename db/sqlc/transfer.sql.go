@@ -7,25 +7,76 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addTransferReversedAmount = `-- name: AddTransferReversedAmount :one
+UPDATE transfers
+SET reversed_amount = reversed_amount + $1
+WHERE id = $2
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversal_of, reason_code, reversed_amount, fee_of
+`
+
+type AddTransferReversedAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error) {
+	row := q.db.QueryRow(ctx, addTransferReversedAmount, arg.Amount, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.SpreadBps,
+		&i.ReversalOf,
+		&i.ReasonCode,
+		&i.ReversedAmount,
+		&i.FeeOf,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
+  from_account_id, to_account_id, amount, to_amount, exchange_rate, spread_bps, reversal_of, reason_code, fee_of
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversal_of, reason_code, reversed_amount, fee_of
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64          `json:"from_account_id"`
+	ToAccountID   int64          `json:"to_account_id"`
+	Amount        int64          `json:"amount"`
+	ToAmount      int64          `json:"to_amount"`
+	ExchangeRate  pgtype.Numeric `json:"exchange_rate"`
+	SpreadBps     pgtype.Int4    `json:"spread_bps"`
+	ReversalOf    pgtype.Int8    `json:"reversal_of"`
+	ReasonCode    pgtype.Text    `json:"reason_code"`
+	FeeOf         pgtype.Int8    `json:"fee_of"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRow(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.SpreadBps,
+		arg.ReversalOf,
+		arg.ReasonCode,
+		arg.FeeOf,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -33,27 +84,50 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.SpreadBps,
+		&i.ReversalOf,
+		&i.ReasonCode,
+		&i.ReversedAmount,
+		&i.FeeOf,
 	)
 	return i, err
 }
 
-const deleteTransfer = `-- name: DeleteTransfer :exec
-DELETE FROM transfers
-WHERE id = $1
+const getTransfer = `-- name: GetTransfer :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversal_of, reason_code, reversed_amount, fee_of FROM transfers
+WHERE id = $1 LIMIT 1
 `
 
-func (q *Queries) DeleteTransfer(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteTransfer, id)
-	return err
+func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransfer, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.SpreadBps,
+		&i.ReversalOf,
+		&i.ReasonCode,
+		&i.ReversedAmount,
+		&i.FeeOf,
+	)
+	return i, err
 }
 
-const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversal_of, reason_code, reversed_amount, fee_of FROM transfers
 WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
 `
 
-func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
-	row := q.db.QueryRow(ctx, getTransfer, id)
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransferForUpdate, id)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -61,24 +135,58 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.SpreadBps,
+		&i.ReversalOf,
+		&i.ReasonCode,
+		&i.ReversedAmount,
+		&i.FeeOf,
 	)
 	return i, err
 }
 
-const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
-ORDER BY id
-LIMIT $1
-OFFSET $2
+const listAccountTransfers = `-- name: ListAccountTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversal_of, reason_code, reversed_amount, fee_of FROM transfers
+WHERE (
+        ($1::boolean AND from_account_id = $2)
+        OR ($3::boolean AND to_account_id = $2)
+    )
+    AND ($4::bigint IS NULL OR amount >= $4)
+    AND ($5::bigint IS NULL OR amount <= $5)
+    AND ($6::timestamptz IS NULL OR created_at >= $6)
+    AND ($7::timestamptz IS NULL OR created_at < $7)
+    AND (created_at, id) > ($8::timestamptz, $9::bigint)
+ORDER BY created_at, id
+LIMIT $10
 `
 
-type ListTransfersParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type ListAccountTransfersParams struct {
+	IncludeSent     bool               `json:"include_sent"`
+	AccountID       int64              `json:"account_id"`
+	IncludeReceived bool               `json:"include_received"`
+	MinAmount       pgtype.Int8        `json:"min_amount"`
+	MaxAmount       pgtype.Int8        `json:"max_amount"`
+	StartTime       pgtype.Timestamptz `json:"start_time"`
+	EndTime         pgtype.Timestamptz `json:"end_time"`
+	AfterCreatedAt  time.Time          `json:"after_created_at"`
+	AfterID         int64              `json:"after_id"`
+	PageSize        int32              `json:"page_size"`
 }
 
-func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
-	rows, err := q.db.Query(ctx, listTransfers, arg.Limit, arg.Offset)
+func (q *Queries) ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.Query(ctx, listAccountTransfers,
+		arg.IncludeSent,
+		arg.AccountID,
+		arg.IncludeReceived,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.StartTime,
+		arg.EndTime,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +200,13 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.SpreadBps,
+			&i.ReversalOf,
+			&i.ReasonCode,
+			&i.ReversedAmount,
+			&i.FeeOf,
 		); err != nil {
 			return nil, err
 		}
@@ -103,36 +218,48 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	return items, nil
 }
 
-const updateTransfer = `-- name: UpdateTransfer :one
-UPDATE transfers
-SET from_account_id = $2,
-to_account_id = $3,
-amount = $4
-WHERE id = $1
-RETURNING id, from_account_id, to_account_id, amount, created_at
+const listTransfers = `-- name: ListTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, spread_bps, reversal_of, reason_code, reversed_amount, fee_of FROM transfers
+WHERE (created_at, id) > ($1::timestamptz, $2::bigint)
+ORDER BY created_at, id
+LIMIT $3
 `
 
-type UpdateTransferParams struct {
-	ID            int64 `json:"id"`
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+type ListTransfersParams struct {
+	AfterCreatedAt time.Time `json:"after_created_at"`
+	AfterID        int64     `json:"after_id"`
+	PageSize       int32     `json:"page_size"`
 }
 
-func (q *Queries) UpdateTransfer(ctx context.Context, arg UpdateTransferParams) (Transfer, error) {
-	row := q.db.QueryRow(ctx, updateTransfer,
-		arg.ID,
-		arg.FromAccountID,
-		arg.ToAccountID,
-		arg.Amount,
-	)
-	var i Transfer
-	err := row.Scan(
-		&i.ID,
-		&i.FromAccountID,
-		&i.ToAccountID,
-		&i.Amount,
-		&i.CreatedAt,
-	)
-	return i, err
+func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.Query(ctx, listTransfers, arg.AfterCreatedAt, arg.AfterID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.SpreadBps,
+			&i.ReversalOf,
+			&i.ReasonCode,
+			&i.ReversedAmount,
+			&i.FeeOf,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
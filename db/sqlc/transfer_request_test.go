@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTransferRequest(t *testing.T, fromAccount Account, toAccount Account, amount int64) TransferRequest {
+	request, err := testStore.CreateTransferRequestTx(context.Background(), CreateTransferRequestTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        amount,
+			Username:      fromAccount.Owner,
+		},
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	require.NotZero(t, request.ID)
+	require.Equal(t, fromAccount.ID, request.FromAccountID)
+	require.Equal(t, toAccount.ID, request.ToAccountID)
+	require.Equal(t, amount, request.Amount)
+	require.Equal(t, TransferRequestStatusPending, request.Status)
+	require.Equal(t, fromAccount.Owner, request.RequestedBy)
+	require.False(t, request.DecidedBy.Valid)
+	require.False(t, request.TransferID.Valid)
+	return request
+}
+
+func TestApproveTransferRequestTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	banker := createRandomUser(t)
+
+	request := createRandomTransferRequest(t, account1, account2, 10)
+
+	// nothing moves until the request is approved
+	unchanged, err := testStore.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, unchanged.Balance)
+
+	_, err = testStore.ApproveTransferRequestTx(context.Background(), ApproveTransferRequestTxParams{
+		ID:         request.ID,
+		ApprovedBy: account1.Owner,
+	})
+	require.ErrorIs(t, err, ErrSelfApproval)
+
+	result, err := testStore.ApproveTransferRequestTx(context.Background(), ApproveTransferRequestTxParams{
+		ID:         request.ID,
+		ApprovedBy: banker.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestStatusApproved, result.TransferRequest.Status)
+	require.Equal(t, banker.Username, result.TransferRequest.DecidedBy.String)
+	require.Equal(t, result.Transfer.ID, result.TransferRequest.TransferID.Int64)
+	require.Equal(t, request.Amount, result.Transfer.Amount)
+	require.Equal(t, account2.Balance+request.Amount, result.ToAccount.Balance)
+
+	_, err = testStore.ApproveTransferRequestTx(context.Background(), ApproveTransferRequestTxParams{
+		ID:         request.ID,
+		ApprovedBy: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrTransferRequestNotPending)
+}
+
+func TestApproveTransferRequestTxInsufficientFunds(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	banker := createRandomUser(t)
+
+	request := createRandomTransferRequest(t, account1, account2, account1.Balance+account1.OverdraftLimit+1)
+
+	_, err := testStore.ApproveTransferRequestTx(context.Background(), ApproveTransferRequestTxParams{
+		ID:         request.ID,
+		ApprovedBy: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// a failed approval leaves the request pending
+	stillPending, err := testStore.GetTransferRequest(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestStatusPending, stillPending.Status)
+}
+
+func TestCloseTransferRequestTx(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	banker := createRandomUser(t)
+
+	request := createRandomTransferRequest(t, account1, account2, 10)
+
+	_, err := testStore.CloseTransferRequestTx(context.Background(), CloseTransferRequestTxParams{
+		ID:     request.ID,
+		Status: TransferRequestStatusExpired,
+	})
+	require.ErrorIs(t, err, ErrTransferRequestNotExpired)
+
+	rejected, err := testStore.CloseTransferRequestTx(context.Background(), CloseTransferRequestTxParams{
+		ID:        request.ID,
+		Status:    TransferRequestStatusRejected,
+		DecidedBy: banker.Username,
+		Reason:    "beneficiary not verified",
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestStatusRejected, rejected.Status)
+	require.Equal(t, banker.Username, rejected.DecidedBy.String)
+	require.Equal(t, "beneficiary not verified", rejected.Reason)
+	require.False(t, rejected.TransferID.Valid)
+
+	_, err = testStore.ApproveTransferRequestTx(context.Background(), ApproveTransferRequestTxParams{
+		ID:         request.ID,
+		ApprovedBy: banker.Username,
+	})
+	require.ErrorIs(t, err, ErrTransferRequestNotPending)
+}
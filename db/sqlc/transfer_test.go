@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -56,8 +57,7 @@ func TestListTransfers(t *testing.T) {
 	}
 
 	arg := ListTransfersParams{
-		Limit:  5,
-		Offset: 5,
+		PageSize: 5,
 	}
 
 	transfers, err := testStore.ListTransfers(context.Background(), arg)
@@ -69,38 +69,47 @@ func TestListTransfers(t *testing.T) {
 	}
 }
 
-func TestUpdateTransfer(t *testing.T) {
-	account := createRandomAccount(t)
-	transfer1 := createRandomTransfer(t)
-
-	arg := UpdateTransferParams{
-		ID:            transfer1.ID,
-		FromAccountID: account.ID,
-		ToAccountID:   transfer1.ToAccountID,
-		Amount:        util.RandomMoney(),
+func TestListAccountTransfers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	for _, arg := range []CreateTransferParams{
+		{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+		{FromAccountID: account2.ID, ToAccountID: account1.ID, Amount: 20},
+		{FromAccountID: account1.ID, ToAccountID: account3.ID, Amount: 30},
+		{FromAccountID: account2.ID, ToAccountID: account3.ID, Amount: 40},
+	} {
+		_, err := testStore.CreateTransfer(context.Background(), arg)
+		require.NoError(t, err)
 	}
 
-	transfer2, err := testStore.UpdateTransfer(context.Background(), arg)
+	arg := ListAccountTransfersParams{
+		IncludeSent:     true,
+		AccountID:       account1.ID,
+		IncludeReceived: true,
+		PageSize:        10,
+	}
+	transfers, err := testStore.ListAccountTransfers(context.Background(), arg)
 	require.NoError(t, err)
-	require.NotEmpty(t, transfer2)
-
-	require.Equal(t, transfer1.ID, transfer2.ID)
-	require.Equal(t, arg.FromAccountID, transfer2.FromAccountID)
-	require.Equal(t, transfer1.ToAccountID, transfer2.ToAccountID)
-	require.Equal(t, arg.Amount, transfer2.Amount)
+	require.Len(t, transfers, 3)
 
-	require.WithinDuration(t, transfer1.CreatedAt, transfer2.CreatedAt, time.Second)
-}
-
-func TestDeleteTransfer(t *testing.T) {
-	transfer1 := createRandomTransfer(t)
-
-	err := testStore.DeleteTransfer(context.Background(), transfer1.ID)
+	arg.IncludeReceived = false
+	transfers, err = testStore.ListAccountTransfers(context.Background(), arg)
 	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+	for _, transfer := range transfers {
+		require.Equal(t, account1.ID, transfer.FromAccountID)
+	}
 
-	transfer2, err := testStore.GetTransfer(context.Background(), transfer1.ID)
-	require.Error(t, err)
-	require.EqualError(t, err, ErrRecordNotFound.Error())
+	arg.MinAmount = pgtype.Int8{Int64: 15, Valid: true}
+	transfers, err = testStore.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, int64(30), transfers[0].Amount)
 
-	require.Empty(t, transfer2)
+	arg.EndTime = pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+	transfers, err = testStore.ListAccountTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, transfers)
 }
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// accountStatusTransitions lists the statuses each status can move to by
+// hand. Accounts only become dormant through MarkDormantAccountsTx, and a
+// closed account stays closed.
+var accountStatusTransitions = map[string][]string{
+	util.AccountStatusActive:  {util.AccountStatusFrozen, util.AccountStatusClosed},
+	util.AccountStatusFrozen:  {util.AccountStatusActive},
+	util.AccountStatusDormant: {util.AccountStatusActive, util.AccountStatusFrozen, util.AccountStatusClosed},
+}
+
+type SetAccountStatusTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason"`
+	// ChangedBy is the banker or owner making the change
+	ChangedBy string `json:"changed_by"`
+}
+
+type SetAccountStatusTxResult struct {
+	Account Account             `json:"account"`
+	Change  AccountStatusChange `json:"change"`
+}
+
+// SetAccountStatusTx moves an account to another status and records why. An
+// account can only be closed once nothing is left on it or owed to it.
+func (store *SQLStore) SetAccountStatusTx(ctx context.Context, arg SetAccountStatusTxParams) (SetAccountStatusTxResult, error) {
+	var result SetAccountStatusTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		// the bank's own accounts back every customer transfer
+		if account.Owner == SystemAccountOwner || !canMoveAccountStatus(account.Status, arg.Status) {
+			return ErrAccountStatusTransition
+		}
+
+		if arg.Status == util.AccountStatusClosed {
+			if account.Balance != 0 || account.HeldAmount != 0 {
+				return ErrAccountBalanceNotZero
+			}
+
+			// interest earned but not paid out yet would land on a closed
+			// account, fractions of a minor unit are never paid anyway
+			_, interest, err := unpostedInterest(ctx, q, account.ID, time.Now().AddDate(0, 0, 1))
+			if err != nil {
+				return err
+			}
+			if interest.Sign() > 0 {
+				return ErrInterestNotPosted
+			}
+		}
+
+		result.Account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:     account.ID,
+			Status: arg.Status,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Change, err = q.CreateAccountStatusChange(ctx, CreateAccountStatusChangeParams{
+			AccountID:  account.ID,
+			FromStatus: account.Status,
+			ToStatus:   arg.Status,
+			Reason:     arg.Reason,
+			ChangedBy:  pgtype.Text{String: arg.ChangedBy, Valid: true},
+		})
+
+		return err
+	})
+
+	return result, err
+}
+
+func canMoveAccountStatus(from string, to string) bool {
+	for _, status := range accountStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkDormantAccountsTx marks dormant every active customer account without
+// activity since inactiveSince. Interest the bank paid into an account does
+// not count as activity.
+func (store *SQLStore) MarkDormantAccountsTx(ctx context.Context, inactiveSince time.Time) ([]Account, error) {
+	var result []Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result, err = q.MarkDormantAccounts(ctx, inactiveSince)
+		if err != nil {
+			return err
+		}
+
+		for _, account := range result {
+			_, err = q.CreateAccountStatusChange(ctx, CreateAccountStatusChangeParams{
+				AccountID:  account.ID,
+				FromStatus: util.AccountStatusActive,
+				ToStatus:   util.AccountStatusDormant,
+				Reason:     fmt.Sprintf("no activity since %s", inactiveSince.Format(time.DateOnly)),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// checkAccountStatus fails when from may not send money or to may not
+// receive it. Dormant accounts still receive. The accounts must come from the
+// update that moved the money, so their rows are locked and their status
+// cannot change under the check.
+func checkAccountStatus(from Account, to Account) error {
+	err := checkCanSend(from)
+	if err != nil {
+		return err
+	}
+
+	switch to.Status {
+	case util.AccountStatusFrozen:
+		return ErrAccountFrozen
+	case util.AccountStatusClosed:
+		return ErrAccountClosed
+	}
+
+	return nil
+}
+
+// checkCanSend fails unless the account is active
+func checkCanSend(account Account) error {
+	switch account.Status {
+	case util.AccountStatusFrozen:
+		return ErrAccountFrozen
+	case util.AccountStatusDormant:
+		return ErrAccountDormant
+	case util.AccountStatusClosed:
+		return ErrAccountClosed
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/util"
+)
+
+const (
+	cashOperationDeposit  = "deposit"
+	cashOperationWithdraw = "withdraw"
+)
+
+type CashTxParams struct {
+	AccountID int64 `json:"account_id"`
+	Amount    int64 `json:"amount"`
+	// Username is the banker handling the cash
+	Username string `json:"-"`
+	// IdempotencyKey, when set, makes a retry of the same request return the
+	// original result instead of moving the money twice
+	IdempotencyKey string `json:"-"`
+}
+
+type CashTxResult struct {
+	Transfer    Transfer `json:"transfer"`
+	Account     Account  `json:"account"`
+	Entry       Entry    `json:"entry"`
+	CashAccount Account  `json:"cash_account"`
+	CashEntry   Entry    `json:"cash_entry"`
+	// Fees are the transfers that charged the account for this one
+	Fees []Transfer `json:"fees"`
+}
+
+// DepositTx credits an account with a transfer from the bank's cash account
+// in its currency
+func (store *SQLStore) DepositTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, cashOperationDeposit, arg)
+}
+
+// WithdrawTx debits an account with a transfer to the bank's cash account in
+// its currency, as far as the account's overdraft limit allows
+func (store *SQLStore) WithdrawTx(ctx context.Context, arg CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, cashOperationWithdraw, arg)
+}
+
+func (store *SQLStore) cashTx(ctx context.Context, operation string, arg CashTxParams) (CashTxResult, error) {
+	var result CashTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		if arg.IdempotencyKey != "" {
+			// the operation is part of the request so a deposit can't be replayed
+			// as a withdrawal of the same amount
+			request := struct {
+				Operation string `json:"operation"`
+				CashTxParams
+			}{operation, arg}
+
+			replayed, err := claimIdempotencyKey(ctx, q, arg.Username, arg.IdempotencyKey, request, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
+
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		// the bank's cash account takes the other side of every deposit and
+		// withdrawal, its debit balance is the cash it holds
+		cashAccount, err := getSystemAccount(ctx, q, LedgerCash, account.Currency)
+		if err != nil {
+			return err
+		}
+
+		transferArg := CreateTransferParams{
+			FromAccountID: cashAccount.ID,
+			ToAccountID:   account.ID,
+			Amount:        arg.Amount,
+			ToAmount:      arg.Amount,
+		}
+		if operation == cashOperationWithdraw {
+			transferArg.FromAccountID, transferArg.ToAccountID = account.ID, cashAccount.ID
+		}
+
+		posted, err := postTransfer(ctx, q, transferArg)
+		if err != nil {
+			return err
+		}
+
+		err = checkAccountStatus(posted.FromAccount, posted.ToAccount)
+		if err != nil {
+			return err
+		}
+
+		result.Transfer = posted.Transfer
+		transferType := util.TransferTypeDeposit
+		if operation == cashOperationWithdraw {
+			transferType = util.TransferTypeWithdrawal
+			result.Account, result.Entry = posted.FromAccount, posted.FromEntry
+			result.CashAccount, result.CashEntry = posted.ToAccount, posted.ToEntry
+		} else {
+			result.Account, result.Entry = posted.ToAccount, posted.ToEntry
+			result.CashAccount, result.CashEntry = posted.FromAccount, posted.FromEntry
+		}
+
+		result.Fees, result.Account, err = chargeFee(ctx, q, transferType, result.Transfer, result.Account)
+		if err != nil {
+			return err
+		}
+
+		// a deposit's fee is a debit too, and may be more than the deposit
+		if operation == cashOperationWithdraw || len(result.Fees) > 0 {
+			err = checkAvailableFunds(result.Account, 0)
+			if err != nil {
+				return err
+			}
+		}
+
+		// cash taken out counts towards the limits on money leaving the
+		// account like any transfer out of it
+		if operation == cashOperationWithdraw {
+			err = checkTransferLimits(ctx, q, result.Transfer, result.Account)
+			if err != nil {
+				return err
+			}
+		}
+
+		if arg.IdempotencyKey != "" {
+			return saveIdempotentResponse(ctx, q, arg.Username, arg.IdempotencyKey, result)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/util"
+)
+
+type CreateAccountTxResult struct {
+	Account Account       `json:"account"`
+	Owner   AccountMember `json:"owner"`
+}
+
+// CreateAccountTx creates an account and makes its owner its first member
+func (store *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountParams) (CreateAccountTxResult, error) {
+	var result CreateAccountTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Account, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Owner, err = q.CreateAccountMember(ctx, CreateAccountMemberParams{
+			AccountID: result.Account.ID,
+			Username:  arg.Owner,
+			Role:      util.AccountMemberOwner,
+			AddedBy:   arg.Owner,
+		})
+
+		return err
+	})
+
+	return result, err
+}
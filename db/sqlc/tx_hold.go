@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	HoldStatusPending  = "pending"
+	HoldStatusCaptured = "captured"
+	HoldStatusReleased = "released"
+	HoldStatusExpired  = "expired"
+)
+
+type PlaceHoldTxParams struct {
+	CreateHoldParams
+	AfterCreate func(hold Hold) error
+}
+
+type PlaceHoldTxResult struct {
+	Hold    Hold    `json:"hold"`
+	Account Account `json:"account"`
+}
+
+// PlaceHoldTx reserves funds on an account, reducing its available balance
+// but not its balance
+func (store *SQLStore) PlaceHoldTx(ctx context.Context, arg PlaceHoldTxParams) (PlaceHoldTxResult, error) {
+	var result PlaceHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Account, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     arg.AccountID,
+			Amount: arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = checkCanSend(result.Account)
+		if err != nil {
+			return err
+		}
+
+		if result.Account.AvailableBalance < -result.Account.OverdraftLimit {
+			return ErrInsufficientFunds
+		}
+
+		result.Hold, err = q.CreateHold(ctx, arg.CreateHoldParams)
+		if err != nil {
+			return err
+		}
+
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(result.Hold)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+type CaptureHoldTxParams struct {
+	HoldID      int64 `json:"hold_id"`
+	ToAccountID int64 `json:"to_account_id"`
+	// Amount to capture, up to the held amount. Zero captures all of it.
+	Amount int64 `json:"amount"`
+}
+
+type CaptureHoldTxResult struct {
+	Hold Hold `json:"hold"`
+	TransferTxResult
+}
+
+// CaptureHoldTx settles a pending hold with a transfer to ToAccountID, which
+// counts against the transfer limits like any other. Any part of the hold
+// that is not captured is released.
+func (store *SQLStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHoldForUpdate(ctx, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != HoldStatusPending {
+			return ErrHoldNotPending
+		}
+
+		if !time.Now().Before(hold.ExpiresAt) {
+			return ErrHoldExpired
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return ErrCaptureExceedsHold
+		}
+
+		result.TransferTxResult, err = transferMoney(ctx, q, TransferTxParams{
+			FromAccountID: hold.AccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        amount,
+		}, hold.Amount)
+		if err != nil {
+			return err
+		}
+
+		err = checkTransferLimits(ctx, q, result.Transfer, result.FromAccount)
+		if err != nil {
+			return err
+		}
+
+		// the source account is already locked by the transfer
+		result.FromAccount, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+			ID:             hold.ID,
+			Status:         HoldStatusCaptured,
+			CapturedAmount: amount,
+			TransferID:     pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+		})
+
+		return err
+	})
+
+	return result, err
+}
+
+type ReleaseHoldTxParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Status is HoldStatusReleased, or HoldStatusExpired when the hold ran out
+	Status string `json:"status"`
+}
+
+type ReleaseHoldTxResult struct {
+	Hold    Hold    `json:"hold"`
+	Account Account `json:"account"`
+}
+
+// ReleaseHoldTx gives the funds of a pending hold back to the account
+func (store *SQLStore) ReleaseHoldTx(ctx context.Context, arg ReleaseHoldTxParams) (ReleaseHoldTxResult, error) {
+	var result ReleaseHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHoldForUpdate(ctx, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		if hold.Status != HoldStatusPending {
+			return ErrHoldNotPending
+		}
+
+		if arg.Status == HoldStatusExpired && time.Now().Before(hold.ExpiresAt) {
+			return ErrHoldNotExpired
+		}
+
+		result.Account, err = q.AddAccountHeldAmount(ctx, AddAccountHeldAmountParams{
+			ID:     hold.AccountID,
+			Amount: -hold.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+			ID:     hold.ID,
+			Status: arg.Status,
+		})
+
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount of the original transfer to refund. Zero reverses whatever has
+	// not been reversed yet.
+	Amount     int64  `json:"amount"`
+	ReasonCode string `json:"reason_code"`
+}
+
+type ReverseTransferTxResult struct {
+	OriginalTransfer Transfer `json:"original_transfer"`
+	TransferTxResult
+}
+
+// ReverseTransferTx undoes all or part of a transfer with a compensating
+// transfer in the opposite direction, linked to the original through
+// reversal_of. The original transfer and its entries are left untouched.
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// locking the original serializes concurrent reversals of it
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if original.ReversalOf.Valid {
+			return ErrCannotReverseReversal
+		}
+
+		remaining := original.Amount - original.ReversedAmount
+		if remaining == 0 {
+			return ErrTransferAlreadyReversed
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount > remaining {
+			return ErrReversalExceedsTransfer
+		}
+
+		// take back the matching share of what the destination was credited,
+		// so that the reversals of a transfer add up to exactly its to_amount
+		toAmount := shareOf(original.ToAmount, original.ReversedAmount+amount, original.Amount) -
+			shareOf(original.ToAmount, original.ReversedAmount, original.Amount)
+		if toAmount <= 0 {
+			return ErrConvertedAmountTooSmall
+		}
+
+		result.OriginalTransfer, err = q.AddTransferReversedAmount(ctx, AddTransferReversedAmountParams{
+			ID:     original.ID,
+			Amount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = postTransfer(ctx, q, CreateTransferParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        toAmount,
+			ToAmount:      amount,
+			ReversalOf:    pgtype.Int8{Int64: original.ID, Valid: true},
+			ReasonCode:    pgtype.Text{String: arg.ReasonCode, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = checkAccountStatus(result.FromAccount, result.ToAccount)
+		if err != nil {
+			return err
+		}
+
+		return checkAvailableFunds(result.FromAccount, 0)
+	})
+
+	return result, err
+}
+
+// shareOf returns total * part / whole rounded down, without overflowing
+func shareOf(total, part, whole int64) int64 {
+	share := new(big.Int).Mul(big.NewInt(total), big.NewInt(part))
+	return share.Quo(share, big.NewInt(whole)).Int64()
+}
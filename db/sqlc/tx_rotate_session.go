@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type RotateSessionTxParams struct {
+	CreateSessionParams
+	OldSessionID uuid.UUID
+}
+
+type RotateSessionTxResult struct {
+	Session Session
+}
+
+// RotateSessionTx retires the old session and replaces it with a new one in the
+// same family. It fails with ErrSessionRotated if the old session was already
+// replaced, which means its refresh token is being reused.
+func (store *SQLStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error) {
+	var result RotateSessionTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		oldSession, err := q.RotateSession(ctx, arg.OldSessionID)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrSessionRotated
+			}
+			return err
+		}
+
+		arg.CreateSessionParams.FamilyID = oldSession.FamilyID
+		result.Session, err = q.CreateSession(ctx, arg.CreateSessionParams)
+
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	ScheduledTransferStatusActive    = "active"
+	ScheduledTransferStatusPaused    = "paused"
+	ScheduledTransferStatusCancelled = "cancelled"
+	ScheduledTransferStatusCompleted = "completed"
+)
+
+const (
+	ScheduledTransferRunStatusPending   = "pending"
+	ScheduledTransferRunStatusSucceeded = "succeeded"
+	ScheduledTransferRunStatusFailed    = "failed"
+	ScheduledTransferRunStatusSkipped   = "skipped"
+)
+
+type ScheduleDueTransfersTxParams struct {
+	Now       time.Time `json:"now"`
+	BatchSize int32     `json:"batch_size"`
+	// AfterCreate is called with every run created, before the transaction
+	// commits
+	AfterCreate func(run ScheduledTransferRun) error
+}
+
+type ScheduleDueTransfersTxResult struct {
+	// Schedules is the number of due schedules that were advanced
+	Schedules int                    `json:"schedules"`
+	Runs      []ScheduledTransferRun `json:"runs"`
+}
+
+// ScheduleDueTransfersTx creates a run for every active schedule whose next
+// occurrence is due and moves the schedule on to its following occurrence.
+// Occurrences missed while nothing was scheduling are not run back to back,
+// only the latest one is.
+func (store *SQLStore) ScheduleDueTransfersTx(ctx context.Context, arg ScheduleDueTransfersTxParams) (ScheduleDueTransfersTxResult, error) {
+	var result ScheduleDueTransfersTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		schedules, err := q.ListDueScheduledTransfers(ctx, ListDueScheduledTransfersParams{
+			Now:       arg.Now,
+			BatchSize: arg.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, schedule := range schedules {
+			run, err := q.CreateScheduledTransferRun(ctx, CreateScheduledTransferRunParams{
+				ScheduledTransferID: schedule.ID,
+				ScheduledFor:        schedule.NextRunAt.Time,
+			})
+			switch {
+			case err == nil:
+				result.Runs = append(result.Runs, run)
+				if arg.AfterCreate != nil {
+					if err := arg.AfterCreate(run); err != nil {
+						return err
+					}
+				}
+			// the occurrence already has a run
+			case !errors.Is(err, ErrRecordNotFound):
+				return err
+			}
+
+			status, nextRunAt, err := NextScheduledRun(schedule, arg.Now)
+			if err != nil {
+				return err
+			}
+
+			_, err = q.UpdateScheduledTransferStatus(ctx, UpdateScheduledTransferStatusParams{
+				ID:        schedule.ID,
+				Status:    status,
+				NextRunAt: nextRunAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Schedules = len(schedules)
+		return nil
+	})
+
+	return result, err
+}
+
+type SetScheduledTransferStatusTxParams struct {
+	ID     int64     `json:"id"`
+	Status string    `json:"status"`
+	Now    time.Time `json:"now"`
+}
+
+// SetScheduledTransferStatusTx pauses, resumes or cancels a schedule. A
+// resumed schedule picks up at its next occurrence after Now, skipping those
+// that fell while it was paused.
+func (store *SQLStore) SetScheduledTransferStatusTx(ctx context.Context, arg SetScheduledTransferStatusTxParams) (ScheduledTransfer, error) {
+	var result ScheduledTransfer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		schedule, err := q.GetScheduledTransferForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		update := UpdateScheduledTransferStatusParams{
+			ID:        schedule.ID,
+			Status:    arg.Status,
+			NextRunAt: schedule.NextRunAt,
+		}
+
+		switch arg.Status {
+		case ScheduledTransferStatusPaused:
+			if schedule.Status != ScheduledTransferStatusActive {
+				return ErrScheduleTransition
+			}
+		case ScheduledTransferStatusActive:
+			if schedule.Status != ScheduledTransferStatusPaused {
+				return ErrScheduleTransition
+			}
+			update.Status, update.NextRunAt, err = NextScheduledRun(schedule, arg.Now)
+			if err != nil {
+				return err
+			}
+		case ScheduledTransferStatusCancelled:
+			if schedule.Status != ScheduledTransferStatusActive && schedule.Status != ScheduledTransferStatusPaused {
+				return ErrScheduleTransition
+			}
+			update.NextRunAt = pgtype.Timestamptz{}
+		default:
+			return ErrScheduleTransition
+		}
+
+		result, err = q.UpdateScheduledTransferStatus(ctx, update)
+		return err
+	})
+
+	return result, err
+}
+
+// NextScheduledRun returns the status and next run time of a schedule once
+// everything up to after has been run. The schedule completes when it has no
+// occurrence left before its end.
+func NextScheduledRun(schedule ScheduledTransfer, after time.Time) (string, pgtype.Timestamptz, error) {
+	next, ok, err := util.NextOccurrence(schedule.Rule, schedule.CronExpression, schedule.StartAt, after)
+	if err != nil {
+		return "", pgtype.Timestamptz{}, err
+	}
+
+	if !ok || (schedule.EndAt.Valid && next.After(schedule.EndAt.Time)) {
+		return ScheduledTransferStatusCompleted, pgtype.Timestamptz{}, nil
+	}
+
+	return ScheduledTransferStatusActive, pgtype.Timestamptz{Time: next, Valid: true}, nil
+}
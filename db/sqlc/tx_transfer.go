@@ -1,11 +1,26 @@
 package db
 
-import "context"
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// CrossCurrency lets the accounts hold different currencies, in which case
+	// the destination is credited Amount converted at the current exchange rate
+	CrossCurrency bool `json:"cross_currency"`
+	// Username is the user requesting the transfer
+	Username string `json:"-"`
+	// IdempotencyKey, when set, makes a retry of the same request return the
+	// original result instead of moving the money twice
+	IdempotencyKey string `json:"-"`
 }
 
 type TransferTxResult struct {
@@ -14,6 +29,8 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// Fees are the transfers that charged the source account for this one
+	Fees []Transfer `json:"fees"`
 }
 
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
@@ -22,54 +39,231 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
 
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
+		if arg.IdempotencyKey != "" {
+			replayed, err := claimIdempotencyKey(ctx, q, arg.Username, arg.IdempotencyKey, arg, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
 
+		result, err = makeTransfer(ctx, q, arg)
 		if err != nil {
 			return err
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
+		if arg.IdempotencyKey != "" {
+			return saveIdempotentResponse(ctx, q, arg.Username, arg.IdempotencyKey, result)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// makeTransfer moves the money of a customer transfer within an open
+// transaction and checks it against the transfer limits
+func makeTransfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	result, err := transferMoney(ctx, q, arg, 0)
+	if err != nil {
+		return result, err
+	}
+
+	return result, checkTransferLimits(ctx, q, result.Transfer, result.FromAccount)
+}
 
+// transferMoney records a transfer, moves its money and charges its fees
+// within an open transaction. capturedHold is the part of the source account's held amount
+// that the transfer settles, so those funds count as available to it.
+func transferMoney(ctx context.Context, q *Queries, arg TransferTxParams, capturedHold int64) (TransferTxResult, error) {
+	transferArg := CreateTransferParams{
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		ToAmount:      arg.Amount,
+	}
+
+	if arg.CrossCurrency {
+		err := applyExchangeRate(ctx, q, &transferArg)
 		if err != nil {
-			return err
+			return TransferTxResult{}, err
 		}
+	}
+
+	result, err := postTransfer(ctx, q, transferArg)
+	if err != nil {
+		return result, err
+	}
+
+	err = checkAccountStatus(result.FromAccount, result.ToAccount)
+	if err != nil {
+		return result, err
+	}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+	transferType := util.TransferTypeTransfer
+	if result.Transfer.ExchangeRate.Valid {
+		transferType = util.TransferTypeCrossCurrency
+	}
+
+	result.Fees, result.FromAccount, err = chargeFee(ctx, q, transferType, result.Transfer, result.FromAccount)
+	if err != nil {
+		return result, err
+	}
+
+	return result, checkAvailableFunds(result.FromAccount, capturedHold)
+}
+
+// postTransfer creates the transfer and its entries, debiting Amount from the
+// source account and crediting ToAmount to the destination. It leaves it to
+// the caller to check that the source account could afford it.
+func postTransfer(ctx context.Context, q *Queries, arg CreateTransferParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
+
+	result.Transfer, err = q.CreateTransfer(ctx, arg)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.FromAccountID,
+		Amount:     -arg.Amount,
+		TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+	})
+
+	if err != nil {
+		return result, err
+	}
 
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.ToAccountID,
+		Amount:     arg.ToAmount,
+		TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	// update account balances
+	result.FromAccount, result.ToAccount, err = addMoneyInOrder(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.ToAmount)
+	if err != nil {
+		return result, err
+	}
+
+	if result.FromAccount.Currency != result.ToAccount.Currency {
+		err = postExchange(ctx, q, result.Transfer, result.FromAccount.Currency, result.ToAccount.Currency)
 		if err != nil {
-			return err
+			return result, err
 		}
+	}
 
-		// update account balances
-		if arg.FromAccountID > arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
+	return result, nil
+}
 
-			if err != nil {
-				return err
-			}
+// postExchange books the currency exchange of a cross-currency transfer
+// against the bank's FX position accounts, which take in the debited currency
+// and pay out the credited one, so the transfer balances in each currency
+func postExchange(ctx context.Context, q *Queries, transfer Transfer, fromCurrency string, toCurrency string) error {
+	fromPosition, err := getSystemAccount(ctx, q, LedgerFXPosition, fromCurrency)
+	if err != nil {
+		return err
+	}
 
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	toPosition, err := getSystemAccount(ctx, q, LedgerFXPosition, toCurrency)
+	if err != nil {
+		return err
+	}
 
-			if err != nil {
-				return err
-			}
-		}
+	_, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  fromPosition.ID,
+		Amount:     transfer.Amount,
+		TransferID: pgtype.Int8{Int64: transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  toPosition.ID,
+		Amount:     -transfer.ToAmount,
+		TransferID: pgtype.Int8{Int64: transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, _, err = addMoneyInOrder(ctx, q, fromPosition.ID, transfer.Amount, toPosition.ID, -transfer.ToAmount)
+	return err
+}
+
+// checkAvailableFunds fails when a debit left account below its overdraft
+// limit. The account must have been updated in the current transaction, so
+// its row is locked and the check cannot race.
+func checkAvailableFunds(account Account, capturedHold int64) error {
+	if account.AvailableBalance+capturedHold < -account.OverdraftLimit {
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+// applyExchangeRate sets the credited amount, rate and spread of a transfer
+// between accounts of different currencies
+func applyExchangeRate(ctx context.Context, q *Queries, arg *CreateTransferParams) error {
+	fromAccount, err := q.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return err
+	}
 
+	toAccount, err := q.GetAccount(ctx, arg.ToAccountID)
+	if err != nil {
+		return err
+	}
+
+	if fromAccount.Currency == toAccount.Currency {
 		return nil
+	}
+
+	rate, err := q.GetExchangeRate(ctx, GetExchangeRateParams{
+		FromCurrency: fromAccount.Currency,
+		ToCurrency:   toAccount.Currency,
 	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return ErrExchangeRateNotFound
+		}
+		return err
+	}
 
-	return result, err
+	toAmount, err := util.ConvertAmount(arg.Amount, fromAccount.Currency, toAccount.Currency, numericToRat(rate.Rate), rate.SpreadBps)
+	if err != nil {
+		return err
+	}
+
+	if toAmount <= 0 {
+		return ErrConvertedAmountTooSmall
+	}
+
+	arg.ToAmount = toAmount
+	arg.ExchangeRate = rate.Rate
+	arg.SpreadBps = pgtype.Int4{Int32: rate.SpreadBps, Valid: true}
+
+	return nil
+}
+
+func numericToRat(n pgtype.Numeric) *big.Rat {
+	exp := int64(n.Exp)
+	if exp < 0 {
+		exp = -exp
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+
+	rat := new(big.Rat).SetInt(n.Int)
+	if n.Exp < 0 {
+		return rat.Quo(rat, scale)
+	}
+	return rat.Mul(rat, scale)
 }
 
 func addMoney(ctx context.Context, q *Queries, account1ID int64, amount1 int64, account2ID int64, amount2 int64) (account1 Account, account2 Account, err error) {
@@ -86,3 +280,15 @@ func addMoney(ctx context.Context, q *Queries, account1ID int64, amount1 int64,
 	})
 	return
 }
+
+// addMoneyInOrder updates the account with the higher ID first, so
+// transactions touching the same two accounts lock them in the same order
+// and cannot deadlock
+func addMoneyInOrder(ctx context.Context, q *Queries, account1ID int64, amount1 int64, account2ID int64, amount2 int64) (account1 Account, account2 Account, err error) {
+	if account1ID > account2ID {
+		return addMoney(ctx, q, account1ID, amount1, account2ID, amount2)
+	}
+
+	account2, account1, err = addMoney(ctx, q, account2ID, amount2, account1ID, amount1)
+	return
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	TransferRequestStatusPending  = "pending"
+	TransferRequestStatusApproved = "approved"
+	TransferRequestStatusRejected = "rejected"
+	TransferRequestStatusExpired  = "expired"
+)
+
+type CreateTransferRequestTxParams struct {
+	TransferTxParams
+	// ExpiresAt is when the request lapses unless a banker has decided on it
+	ExpiresAt   time.Time                           `json:"-"`
+	AfterCreate func(request TransferRequest) error `json:"-"`
+}
+
+// CreateTransferRequestTx holds a transfer back until a banker approves it.
+// Nothing moves until then, so the accounts are left as they are.
+func (store *SQLStore) CreateTransferRequestTx(ctx context.Context, arg CreateTransferRequestTxParams) (TransferRequest, error) {
+	var result TransferRequest
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		if arg.IdempotencyKey != "" {
+			// hashed apart from TransferTx, so a key can never replay a
+			// transfer as a request or the other way round
+			request := map[string]any{"transfer_request": arg.TransferTxParams}
+			replayed, err := claimIdempotencyKey(ctx, q, arg.Username, arg.IdempotencyKey, request, &result)
+			if err != nil || replayed {
+				return err
+			}
+		}
+
+		result, err = q.CreateTransferRequest(ctx, CreateTransferRequestParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.Amount,
+			CrossCurrency: arg.CrossCurrency,
+			RequestedBy:   arg.Username,
+			ExpiresAt:     arg.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.IdempotencyKey != "" {
+			err = saveIdempotentResponse(ctx, q, arg.Username, arg.IdempotencyKey, result)
+			if err != nil {
+				return err
+			}
+		}
+
+		if arg.AfterCreate != nil {
+			return arg.AfterCreate(result)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+type ApproveTransferRequestTxParams struct {
+	ID         int64  `json:"id"`
+	ApprovedBy string `json:"approved_by"`
+}
+
+type ApproveTransferRequestTxResult struct {
+	TransferRequest TransferRequest `json:"transfer_request"`
+	TransferTxResult
+}
+
+// ApproveTransferRequestTx makes the transfer of a pending request. The
+// transfer is checked against the balance and limits of the moment it is
+// approved, and the request stays pending when it fails.
+func (store *SQLStore) ApproveTransferRequestTx(ctx context.Context, arg ApproveTransferRequestTxParams) (ApproveTransferRequestTxResult, error) {
+	var result ApproveTransferRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		request, err := q.GetTransferRequestForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if request.Status != TransferRequestStatusPending {
+			return ErrTransferRequestNotPending
+		}
+
+		if !time.Now().Before(request.ExpiresAt) {
+			return ErrTransferRequestExpired
+		}
+
+		if request.RequestedBy == arg.ApprovedBy {
+			return ErrSelfApproval
+		}
+
+		result.TransferTxResult, err = makeTransfer(ctx, q, TransferTxParams{
+			FromAccountID: request.FromAccountID,
+			ToAccountID:   request.ToAccountID,
+			Amount:        request.Amount,
+			CrossCurrency: request.CrossCurrency,
+			Username:      request.RequestedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.TransferRequest, err = q.UpdateTransferRequestStatus(ctx, UpdateTransferRequestStatusParams{
+			ID:         request.ID,
+			Status:     TransferRequestStatusApproved,
+			DecidedBy:  pgtype.Text{String: arg.ApprovedBy, Valid: true},
+			TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+		})
+
+		return err
+	})
+
+	return result, err
+}
+
+type CloseTransferRequestTxParams struct {
+	ID int64 `json:"id"`
+	// Status is TransferRequestStatusRejected, or TransferRequestStatusExpired
+	// when the request ran out
+	Status string `json:"status"`
+	// DecidedBy is the banker rejecting the request, and empty when it expires
+	DecidedBy string `json:"decided_by"`
+	Reason    string `json:"reason"`
+}
+
+// CloseTransferRequestTx ends a pending request without making its transfer
+func (store *SQLStore) CloseTransferRequestTx(ctx context.Context, arg CloseTransferRequestTxParams) (TransferRequest, error) {
+	var result TransferRequest
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		request, err := q.GetTransferRequestForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if request.Status != TransferRequestStatusPending {
+			return ErrTransferRequestNotPending
+		}
+
+		if arg.Status == TransferRequestStatusExpired && time.Now().Before(request.ExpiresAt) {
+			return ErrTransferRequestNotExpired
+		}
+
+		result, err = q.UpdateTransferRequestStatus(ctx, UpdateTransferRequestStatusParams{
+			ID:        request.ID,
+			Status:    arg.Status,
+			DecidedBy: pgtype.Text{String: arg.DecidedBy, Valid: arg.DecidedBy != ""},
+			Reason:    arg.Reason,
+		})
+
+		return err
+	})
+
+	return result, err
+}
@@ -28,14 +28,22 @@ CREATE TABLE "accounts" (
   "owner" varchar NOT NULL,
   "balance" bigint NOT NULL,
   "currency" varchar NOT NULL,
-  "created_at" timestamptz NOT NULL DEFAULT (now())
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "overdraft_limit" bigint NOT NULL DEFAULT 0,
+  "held_amount" bigint NOT NULL DEFAULT 0,
+  "available_balance" bigint NOT NULL GENERATED ALWAYS AS ("balance" - "held_amount") STORED,
+  "ledger_code" varchar NOT NULL DEFAULT 'customer_deposits',
+  "type" varchar NOT NULL DEFAULT 'checking',
+  "status" varchar NOT NULL DEFAULT 'active',
+  "nickname" varchar NOT NULL DEFAULT ''
 );
 
 CREATE TABLE "entries" (
   "id" bigserial PRIMARY KEY,
   "account_id" bigint NOT NULL,
   "amount" bigint NOT NULL,
-  "created_at" timestamptz NOT NULL DEFAULT (now())
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "transfer_id" bigint
 );
 
 CREATE TABLE "transfers" (
@@ -43,31 +51,431 @@ CREATE TABLE "transfers" (
   "from_account_id" bigint NOT NULL,
   "to_account_id" bigint NOT NULL,
   "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "to_amount" bigint NOT NULL,
+  "exchange_rate" numeric(20,10),
+  "spread_bps" integer,
+  "reversal_of" bigint,
+  "reason_code" varchar,
+  "reversed_amount" bigint NOT NULL DEFAULT 0,
+  "fee_of" bigint
+);
+
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "request_hash" varchar NOT NULL,
+  "response" jsonb,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "expires_at" timestamptz NOT NULL,
+  PRIMARY KEY ("username", "key")
+);
+
+CREATE TABLE "exchange_rates" (
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" numeric(20,10) NOT NULL,
+  "spread_bps" integer NOT NULL DEFAULT 0,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("from_currency", "to_currency")
+);
+
+CREATE TABLE "currencies" (
+  "code" varchar(3) PRIMARY KEY,
+  "numeric_code" varchar(3) UNIQUE NOT NULL,
+  "name" varchar NOT NULL,
+  "minor_units" smallint NOT NULL,
+  "enabled" bool NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "approval_threshold" bigint NOT NULL DEFAULT 0
+);
+
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "description" varchar NOT NULL DEFAULT '',
+  "created_by" varchar NOT NULL,
+  "transfer_id" bigint,
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "cross_currency" bool NOT NULL DEFAULT false,
+  "rule" varchar NOT NULL,
+  "cron_expression" varchar NOT NULL DEFAULT '',
+  "start_at" timestamptz NOT NULL,
+  "end_at" timestamptz,
+  "next_run_at" timestamptz,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_by" varchar NOT NULL
+);
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" bigserial PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "chart_of_accounts" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "fee_schedules" (
+  "transfer_type" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "min_amount" bigint NOT NULL DEFAULT 0,
+  "flat_fee" bigint NOT NULL DEFAULT 0,
+  "rate_bps" integer NOT NULL DEFAULT 0,
+  "min_fee" bigint NOT NULL DEFAULT 0,
+  "max_fee" bigint,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("transfer_type", "currency", "role", "min_amount")
+);
+
+CREATE TABLE "account_products" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "interest_rate_bps" integer NOT NULL DEFAULT 0,
+  "day_count" varchar NOT NULL DEFAULT 'act_365',
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "interest_accruals" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "rate_bps" integer NOT NULL,
+  "day_count" varchar NOT NULL,
+  "amount" numeric(24,10) NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "carried_from" bigint
+);
+
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "role" varchar,
+  "username" varchar,
+  "account_id" bigint,
+  "scope" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "period" varchar NOT NULL,
+  "max_amount" bigint,
+  "max_count" integer,
+  "updated_by" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "transfer_requests" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "cross_currency" boolean NOT NULL DEFAULT false,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "requested_by" varchar NOT NULL,
+  "decided_by" varchar,
+  "reason" varchar NOT NULL DEFAULT '',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "account_status_changes" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "from_status" varchar NOT NULL,
+  "to_status" varchar NOT NULL,
+  "reason" varchar NOT NULL,
+  "changed_by" varchar,
   "created_at" timestamptz NOT NULL DEFAULT (now())
 );
 
+CREATE TABLE "account_members" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "transfer_limit" bigint,
+  "added_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
 CREATE INDEX ON "accounts" ("owner");
 
-CREATE UNIQUE INDEX ON "accounts" ("owner", "currency");
+CREATE UNIQUE INDEX "owner_currency_type_ledger_code_key" ON "accounts" ("owner", "currency", "type", "ledger_code") WHERE "status" <> 'closed';
+
+CREATE INDEX ON "accounts" ("owner", "created_at", "id");
 
 CREATE INDEX ON "entries" ("account_id");
 
+CREATE INDEX ON "entries" ("transfer_id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
+
+CREATE INDEX ON "entries" ("created_at", "id");
+
 CREATE INDEX ON "transfers" ("from_account_id");
 
 CREATE INDEX ON "transfers" ("to_account_id");
 
 CREATE INDEX ON "transfers" ("from_account_id", "to_account_id");
 
-COMMENT ON COLUMN "entries"."amount" IS 'can be negative or positive';
+CREATE INDEX ON "transfers" ("created_at", "id");
+
+CREATE INDEX ON "transfers" ("reversal_of");
+
+CREATE INDEX ON "transfers" ("fee_of");
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+CREATE INDEX ON "holds" ("account_id", "created_at", "id");
+
+CREATE UNIQUE INDEX "account_accrual_date_key" ON "interest_accruals" ("account_id", "accrual_date") WHERE "carried_from" IS NULL;
+
+CREATE INDEX ON "interest_accruals" ("transfer_id");
+
+CREATE UNIQUE INDEX "transfer_limits_subject_key" ON "transfer_limits" (COALESCE("role", ''), COALESCE("username", ''), COALESCE("account_id", 0), "scope", "currency", "period");
+
+CREATE INDEX ON "transfer_requests" ("status", "created_at", "id");
+
+CREATE INDEX ON "account_status_changes" ("account_id", "created_at", "id");
+
+CREATE INDEX ON "account_members" ("username", "account_id");
+
+CREATE INDEX ON "scheduled_transfers" ("owner", "created_at", "id");
+
+CREATE INDEX ON "scheduled_transfers" ("next_run_at");
+
+CREATE INDEX ON "scheduled_transfers" ("from_account_id", "created_at", "id");
+
+CREATE UNIQUE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "scheduled_for");
+
+CREATE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "created_at", "id");
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far below zero the balance may go';
+
+COMMENT ON COLUMN "idempotency_keys"."request_hash" IS 'sha256 of the request the key was first used with';
+
+COMMENT ON COLUMN "accounts"."held_amount" IS 'sum of the pending holds on the account';
+
+COMMENT ON COLUMN "accounts"."available_balance" IS 'generated as balance - held_amount';
+
+COMMENT ON COLUMN "accounts"."ledger_code" IS 'where the account sits in the chart of accounts';
+
+COMMENT ON COLUMN "accounts"."type" IS 'account product, which sets the interest the account earns';
+
+COMMENT ON COLUMN "accounts"."status" IS 'active, frozen, dormant or closed; only active accounts send money and frozen or closed ones receive none';
+
+COMMENT ON COLUMN "accounts"."nickname" IS 'name the owner gave the account, empty when it has none';
+
+COMMENT ON COLUMN "entries"."amount" IS 'negative for a debit, positive for a credit';
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer that created the entry, if any';
 
 COMMENT ON COLUMN "transfers"."amount" IS 'must be positive';
 
+COMMENT ON COLUMN "transfers"."to_amount" IS 'amount credited to the destination account, in its currency';
+
+COMMENT ON COLUMN "transfers"."exchange_rate" IS 'rate applied to a cross-currency transfer';
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'transfer this one compensates, if it is a reversal';
+
+COMMENT ON COLUMN "transfers"."reason_code" IS 'why the reversal was made';
+
+COMMENT ON COLUMN "transfers"."reversed_amount" IS 'part of amount that has been reversed so far';
+
+COMMENT ON COLUMN "transfers"."fee_of" IS 'transfer this one charged a fee for, if it is a fee';
+
+COMMENT ON COLUMN "exchange_rates"."rate" IS 'units of to_currency per unit of from_currency';
+
+COMMENT ON COLUMN "exchange_rates"."spread_bps" IS 'margin taken off the converted amount, in basis points';
+
+COMMENT ON COLUMN "currencies"."minor_units" IS 'ISO 4217 exponent, the number of decimal places';
+
+COMMENT ON COLUMN "currencies"."enabled" IS 'whether new accounts and transfers may use the currency';
+
+COMMENT ON COLUMN "currencies"."approval_threshold" IS 'transfers of more than this need a banker''s approval, 0 turns approvals off';
+
+COMMENT ON COLUMN "holds"."status" IS 'pending, captured, released or expired';
+
+COMMENT ON COLUMN "holds"."transfer_id" IS 'transfer the hold was captured into';
+
+COMMENT ON COLUMN "scheduled_transfers"."rule" IS 'once, daily, weekly, monthly or cron';
+
+COMMENT ON COLUMN "scheduled_transfers"."cron_expression" IS 'five field cron expression, for the cron rule';
+
+COMMENT ON COLUMN "scheduled_transfers"."start_at" IS 'first occurrence, or the only one for the once rule';
+
+COMMENT ON COLUMN "scheduled_transfers"."end_at" IS 'no occurrence is run after this time';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_run_at" IS 'null once the schedule has no occurrences left';
+
+COMMENT ON COLUMN "scheduled_transfers"."created_by" IS 'member or banker who set the schedule up; a member''s schedule is paused once they leave the account';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."scheduled_for" IS 'occurrence this run executes';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."error" IS 'why the transfer failed or was skipped';
+
+COMMENT ON COLUMN "chart_of_accounts"."type" IS 'asset, liability, equity, income or expense';
+
+COMMENT ON COLUMN "fee_schedules"."transfer_type" IS 'transfer, cross_currency, deposit or withdrawal';
+
+COMMENT ON COLUMN "fee_schedules"."min_amount" IS 'smallest amount the tier applies to, the tier with the highest min_amount not above the amount wins';
+
+COMMENT ON COLUMN "fee_schedules"."rate_bps" IS 'percentage of the amount charged on top of flat_fee, in basis points';
+
+COMMENT ON COLUMN "fee_schedules"."max_fee" IS 'no cap when null';
+
+COMMENT ON COLUMN "account_products"."interest_rate_bps" IS 'annual interest rate, in basis points';
+
+COMMENT ON COLUMN "account_products"."day_count" IS 'act_365 or 30_360';
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'end-of-day balance the interest was computed on';
+
+COMMENT ON COLUMN "interest_accruals"."amount" IS 'interest earned for the day, in fractions of the minor unit';
+
+COMMENT ON COLUMN "interest_accruals"."transfer_id" IS 'transfer that paid the interest out, null until it is posted';
+
+COMMENT ON COLUMN "interest_accruals"."carried_from" IS 'posting that left this fraction of a minor unit over for the next one, null for a day''s own accrual';
+
+COMMENT ON COLUMN "transfer_limits"."role" IS 'set for the default limit of every user with the role';
+
+COMMENT ON COLUMN "transfer_limits"."username" IS 'set when a banker overrides the user limit of one user';
+
+COMMENT ON COLUMN "transfer_limits"."account_id" IS 'set when a banker overrides the limit of one account';
+
+COMMENT ON COLUMN "transfer_limits"."scope" IS 'account limits add up the transfers out of one account, user limits those out of all the user''s accounts in the currency';
+
+COMMENT ON COLUMN "transfer_limits"."period" IS 'daily or monthly, in UTC';
+
+COMMENT ON COLUMN "transfer_limits"."max_amount" IS 'no amount limit when null';
+
+COMMENT ON COLUMN "transfer_limits"."max_count" IS 'no count limit when null';
+
+COMMENT ON COLUMN "transfer_requests"."status" IS 'pending, approved, rejected or expired';
+
+COMMENT ON COLUMN "transfer_requests"."requested_by" IS 'the maker, who can never approve the request';
+
+COMMENT ON COLUMN "transfer_requests"."decided_by" IS 'the banker who approved or rejected the request';
+
+COMMENT ON COLUMN "transfer_requests"."reason" IS 'why the request was rejected';
+
+COMMENT ON COLUMN "transfer_requests"."transfer_id" IS 'transfer made when the request was approved';
+
+COMMENT ON COLUMN "account_status_changes"."changed_by" IS 'banker or owner who made the change, null when the bank marked the account dormant';
+
+COMMENT ON COLUMN "account_members"."role" IS 'owner, co_owner, viewer or transferor; the owner is the account''s owner and every member can see the account';
+
+COMMENT ON COLUMN "account_members"."transfer_limit" IS 'largest transfer a transferor may make from the account, null for every other role';
+
 ALTER TABLE "verify_emails" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
 
 ALTER TABLE "accounts" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
 
 ALTER TABLE "entries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
 
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
 ALTER TABLE "transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
 
 ALTER TABLE "transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("from_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("to_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("ledger_code") REFERENCES "chart_of_accounts" ("code");
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("fee_of") REFERENCES "transfers" ("id");
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "fee_schedules" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("type") REFERENCES "account_products" ("code");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("carried_from") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_status_changes" ADD FOREIGN KEY ("changed_by") REFERENCES "users" ("username");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("added_by") REFERENCES "users" ("username");
@@ -0,0 +1,191 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+type camtDocument struct {
+	XMLName   xml.Name `xml:"Document"`
+	Namespace string   `xml:"xmlns,attr"`
+	Statement struct {
+		GroupHeader struct {
+			MsgID   string `xml:"MsgId"`
+			CreDtTm string `xml:"CreDtTm"`
+		} `xml:"GrpHdr"`
+		Stmt camtStatement `xml:"Stmt"`
+	} `xml:"BkToCstmrStmt"`
+}
+
+type camtStatement struct {
+	ID      string `xml:"Id"`
+	CreDtTm string `xml:"CreDtTm"`
+	FrToDt  struct {
+		FrDtTm string `xml:"FrDtTm"`
+		ToDtTm string `xml:"ToDtTm"`
+	} `xml:"FrToDt"`
+	Account struct {
+		ID struct {
+			Other struct {
+				ID string `xml:"Id"`
+			} `xml:"Othr"`
+		} `xml:"Id"`
+		Currency string    `xml:"Ccy"`
+		Owner    camtParty `xml:"Ownr"`
+	} `xml:"Acct"`
+	Balances []camtBalance `xml:"Bal"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtParty struct {
+	Name string `xml:"Nm"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtDate struct {
+	DtTm string `xml:"DtTm"`
+}
+
+type camtBalance struct {
+	Type struct {
+		CdOrPrtry struct {
+			Code string `xml:"Cd"`
+		} `xml:"CdOrPrtry"`
+	} `xml:"Tp"`
+	Amount    camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Date      camtDate   `xml:"Dt"`
+}
+
+type camtEntry struct {
+	Reference   string     `xml:"NtryRef"`
+	Amount      camtAmount `xml:"Amt"`
+	CdtDbtInd   string     `xml:"CdtDbtInd"`
+	Status      string     `xml:"Sts"`
+	BookingDate camtDate   `xml:"BookgDt"`
+	ValueDate   camtDate   `xml:"ValDt"`
+	BankTxCode  struct {
+		Proprietary struct {
+			Code string `xml:"Cd"`
+		} `xml:"Prtry"`
+	} `xml:"BkTxCd"`
+	Details *camtEntryDetails `xml:"NtryDtls,omitempty"`
+	Info    string            `xml:"AddtlNtryInf"`
+}
+
+type camtEntryDetails struct {
+	Transaction struct {
+		Refs struct {
+			TxID string `xml:"TxId"`
+		} `xml:"Refs"`
+		RelatedParties struct {
+			Debtor   *camtParty `xml:"Dbtr,omitempty"`
+			Creditor *camtParty `xml:"Cdtr,omitempty"`
+		} `xml:"RltdPties"`
+	} `xml:"TxDtls"`
+}
+
+// writeCAMT053 writes an ISO 20022 bank to customer statement (camt.053.001.02)
+// with OPBD and CLBD balances. Amounts are unsigned, the direction is in CdtDbtInd.
+func writeCAMT053(w io.Writer, statement Statement) error {
+	doc := camtDocument{Namespace: camt053Namespace}
+
+	statementID := fmt.Sprintf("%d-%s", statement.AccountID, statement.EndTime.UTC().Format("20060102150405"))
+	doc.Statement.GroupHeader.MsgID = statementID
+	doc.Statement.GroupHeader.CreDtTm = camtTime(statement.CreatedAt)
+
+	stmt := &doc.Statement.Stmt
+	stmt.ID = statementID
+	stmt.CreDtTm = camtTime(statement.CreatedAt)
+	stmt.FrToDt.FrDtTm = camtTime(statement.StartTime)
+	stmt.FrToDt.ToDtTm = camtTime(statement.EndTime)
+	stmt.Account.ID.Other.ID = strconv.FormatInt(statement.AccountID, 10)
+	stmt.Account.Currency = statement.Currency
+	stmt.Account.Owner = camtParty{Name: statement.Owner}
+	stmt.Balances = []camtBalance{
+		newCamtBalance("OPBD", statement.OpeningBalance, statement.StartTime, statement),
+		newCamtBalance("CLBD", statement.ClosingBalance, statement.EndTime, statement),
+	}
+
+	for _, line := range statement.Lines {
+		entry := camtEntry{
+			Reference:   strconv.FormatInt(line.EntryID, 10),
+			Amount:      newCamtAmount(line.Amount, statement),
+			CdtDbtInd:   creditDebitIndicator(line.Amount),
+			Status:      "BOOK",
+			BookingDate: camtDate{DtTm: camtTime(line.CreatedAt)},
+			ValueDate:   camtDate{DtTm: camtTime(line.CreatedAt)},
+			Info:        line.description(),
+		}
+		entry.BankTxCode.Proprietary.Code = "ENTRY"
+
+		if line.TransferID != 0 {
+			entry.BankTxCode.Proprietary.Code = "TRANSFER"
+
+			details := &camtEntryDetails{}
+			details.Transaction.Refs.TxID = strconv.FormatInt(line.TransferID, 10)
+
+			counterparty := &camtParty{Name: line.CounterpartyOwner}
+			if line.Amount < 0 {
+				details.Transaction.RelatedParties.Creditor = counterparty
+			} else {
+				details.Transaction.RelatedParties.Debtor = counterparty
+			}
+			entry.Details = details
+		}
+
+		stmt.Entries = append(stmt.Entries, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newCamtBalance(code string, amount int64, date time.Time, statement Statement) camtBalance {
+	var balance camtBalance
+	balance.Type.CdOrPrtry.Code = code
+	balance.Amount = newCamtAmount(amount, statement)
+	balance.CdtDbtInd = creditDebitIndicator(amount)
+	balance.Date = camtDate{DtTm: camtTime(date)}
+	return balance
+}
+
+func newCamtAmount(amount int64, statement Statement) camtAmount {
+	if amount < 0 {
+		amount = -amount
+	}
+	return camtAmount{
+		Currency: statement.Currency,
+		Value:    formatAmount(amount, statement.MinorUnits),
+	}
+}
+
+func creditDebitIndicator(amount int64) string {
+	if amount < 0 {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+func camtTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05")
+}
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{
+	"date",
+	"entry_id",
+	"description",
+	"transfer_id",
+	"counterparty_account_id",
+	"counterparty_owner",
+	"amount",
+	"balance",
+	"currency",
+}
+
+func writeCSV(w io.Writer, statement Statement) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, line := range statement.Lines {
+		record := []string{
+			line.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatInt(line.EntryID, 10),
+			line.description(),
+			optionalID(line.TransferID),
+			optionalID(line.CounterpartyAccountID),
+			line.CounterpartyOwner,
+			formatAmount(line.Amount, statement.MinorUnits),
+			formatAmount(line.Balance, statement.MinorUnits),
+			statement.Currency,
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func optionalID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
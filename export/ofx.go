@@ -0,0 +1,133 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+)
+
+const (
+	ofxHeader = `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`
+	ofxBankID = "SIMPLEBANK"
+)
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Response struct {
+			Status   ofxStatus `xml:"STATUS"`
+			DTServer string    `xml:"DTSERVER"`
+			Language string    `xml:"LANGUAGE"`
+		} `xml:"SONRS"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Bank struct {
+		Transaction struct {
+			TrnUID    string          `xml:"TRNUID"`
+			Status    ofxStatus       `xml:"STATUS"`
+			Statement ofxStatementRes `xml:"STMTRS"`
+		} `xml:"STMTTRNRS"`
+	} `xml:"BANKMSGSRSV1"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxStatementRes struct {
+	Currency    string `xml:"CURDEF"`
+	BankAccount struct {
+		BankID   string `xml:"BANKID"`
+		AcctID   string `xml:"ACCTID"`
+		AcctType string `xml:"ACCTTYPE"`
+	} `xml:"BANKACCTFROM"`
+	TransactionList struct {
+		DTStart      string           `xml:"DTSTART"`
+		DTEnd        string           `xml:"DTEND"`
+		Transactions []ofxTransaction `xml:"STMTTRN"`
+	} `xml:"BANKTRANLIST"`
+	LedgerBalance ofxBalance `xml:"LEDGERBAL"`
+}
+
+type ofxTransaction struct {
+	Type     string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	Amount   string `xml:"TRNAMT"`
+	FitID    string `xml:"FITID"`
+	Name     string `xml:"NAME,omitempty"`
+	Memo     string `xml:"MEMO"`
+}
+
+type ofxBalance struct {
+	Amount string `xml:"BALAMT"`
+	DTAsOf string `xml:"DTASOF"`
+}
+
+// ofxAccountType returns the OFX ACCTTYPE of an account product
+func ofxAccountType(accountType string) string {
+	if accountType == util.AccountTypeSavings {
+		return "SAVINGS"
+	}
+	return "CHECKING"
+}
+
+// writeOFX writes an OFX 2.2 bank statement response. OFX has no opening
+// balance element, so only the closing balance is reported as LEDGERBAL.
+func writeOFX(w io.Writer, statement Statement) error {
+	var doc ofxDocument
+
+	ok := ofxStatus{Code: 0, Severity: "INFO"}
+	doc.SignOn.Response.Status = ok
+	doc.SignOn.Response.DTServer = ofxTime(statement.CreatedAt)
+	doc.SignOn.Response.Language = "ENG"
+	doc.Bank.Transaction.TrnUID = "0"
+	doc.Bank.Transaction.Status = ok
+
+	res := &doc.Bank.Transaction.Statement
+	res.Currency = statement.Currency
+	res.BankAccount.BankID = ofxBankID
+	res.BankAccount.AcctID = strconv.FormatInt(statement.AccountID, 10)
+	res.BankAccount.AcctType = ofxAccountType(statement.AccountType)
+	res.TransactionList.DTStart = ofxTime(statement.StartTime)
+	res.TransactionList.DTEnd = ofxTime(statement.EndTime)
+	res.LedgerBalance = ofxBalance{
+		Amount: formatAmount(statement.ClosingBalance, statement.MinorUnits),
+		DTAsOf: ofxTime(statement.EndTime),
+	}
+
+	for _, line := range statement.Lines {
+		trnType := "CREDIT"
+		if line.Amount < 0 {
+			trnType = "DEBIT"
+		}
+
+		res.TransactionList.Transactions = append(res.TransactionList.Transactions, ofxTransaction{
+			Type:     trnType,
+			DTPosted: ofxTime(line.CreatedAt),
+			Amount:   formatAmount(line.Amount, statement.MinorUnits),
+			FitID:    strconv.FormatInt(line.EntryID, 10),
+			Name:     line.CounterpartyOwner,
+			Memo:     line.description(),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header+ofxHeader+"\n"); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:UTC]"
+}
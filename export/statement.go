@@ -0,0 +1,125 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatOFX     Format = "ofx"
+	FormatCAMT053 Format = "camt053"
+)
+
+// Statement is the activity of one account over a period
+type Statement struct {
+	AccountID      int64
+	AccountType    string
+	Owner          string
+	Currency       string
+	MinorUnits     int
+	StartTime      time.Time
+	EndTime        time.Time
+	OpeningBalance int64
+	ClosingBalance int64
+	Lines          []Line
+	CreatedAt      time.Time
+}
+
+type Line struct {
+	EntryID   int64
+	Amount    int64
+	Balance   int64
+	CreatedAt time.Time
+	// the fields below are zero for entries that did not come from a transfer
+	TransferID            int64
+	CounterpartyAccountID int64
+	CounterpartyOwner     string
+}
+
+func (line Line) description() string {
+	if line.TransferID == 0 {
+		return fmt.Sprintf("Entry %d", line.EntryID)
+	}
+	if line.Amount < 0 {
+		return fmt.Sprintf("Transfer %d to account %d", line.TransferID, line.CounterpartyAccountID)
+	}
+	return fmt.Sprintf("Transfer %d from account %d", line.TransferID, line.CounterpartyAccountID)
+}
+
+func (format Format) ContentType() string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatOFX:
+		return "application/x-ofx"
+	default:
+		return "application/xml"
+	}
+}
+
+func (format Format) FileExtension() string {
+	switch format {
+	case FormatCSV:
+		return "csv"
+	case FormatOFX:
+		return "ofx"
+	default:
+		return "xml"
+	}
+}
+
+// FileName is the name to offer when the statement is downloaded
+func FileName(format Format, statement Statement) string {
+	return fmt.Sprintf("statement-%d-%s-%s.%s",
+		statement.AccountID,
+		statement.StartTime.UTC().Format("20060102"),
+		statement.EndTime.UTC().Format("20060102"),
+		format.FileExtension(),
+	)
+}
+
+func Write(w io.Writer, format Format, statement Statement) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, statement)
+	case FormatOFX:
+		return writeOFX(w, statement)
+	case FormatCAMT053:
+		return writeCAMT053(w, statement)
+	}
+	return fmt.Errorf("unsupported statement format: %s", format)
+}
+
+// formatAmount writes an amount stored in minor units as a decimal, e.g. -1234
+// with 2 minor units is -12.34
+func formatAmount(amount int64, minorUnits int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+
+	digits := strconv.FormatUint(absAmount(amount), 10)
+	if minorUnits <= 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= minorUnits {
+		digits = strings.Repeat("0", minorUnits-len(digits)+1) + digits
+	}
+
+	point := len(digits) - minorUnits
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+func absAmount(amount int64) uint64 {
+	if amount < 0 {
+		return uint64(-(amount + 1)) + 1
+	}
+	return uint64(amount)
+}
@@ -0,0 +1,137 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAmount(t *testing.T) {
+	testCases := []struct {
+		amount     int64
+		minorUnits int
+		expected   string
+	}{
+		{1234, 2, "12.34"},
+		{-1234, 2, "-12.34"},
+		{5, 2, "0.05"},
+		{-5, 2, "-0.05"},
+		{0, 2, "0.00"},
+		{1234, 0, "1234"},
+		{1234, 3, "1.234"},
+		{12, 3, "0.012"},
+	}
+
+	for _, testCase := range testCases {
+		require.Equal(t, testCase.expected, formatAmount(testCase.amount, testCase.minorUnits))
+	}
+}
+
+func randomStatement() Statement {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return Statement{
+		AccountID:      7,
+		AccountType:    util.AccountTypeChecking,
+		Owner:          "alice",
+		Currency:       "USD",
+		MinorUnits:     2,
+		StartTime:      start,
+		EndTime:        start.AddDate(0, 1, 0),
+		OpeningBalance: 10000,
+		ClosingBalance: 9500,
+		CreatedAt:      start.AddDate(0, 1, 1),
+		Lines: []Line{
+			{
+				EntryID:               1,
+				Amount:                -1000,
+				Balance:               9000,
+				CreatedAt:             start.Add(time.Hour),
+				TransferID:            3,
+				CounterpartyAccountID: 8,
+				CounterpartyOwner:     "bob",
+			},
+			{
+				EntryID:   2,
+				Amount:    500,
+				Balance:   9500,
+				CreatedAt: start.Add(2 * time.Hour),
+			},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCSV, randomStatement()))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, csvHeader, records[0])
+	require.Equal(t, []string{"2024-01-01T01:00:00Z", "1", "Transfer 3 to account 8", "3", "8", "bob", "-10.00", "90.00", "USD"}, records[1])
+	require.Equal(t, []string{"2024-01-01T02:00:00Z", "2", "Entry 2", "", "", "", "5.00", "95.00", "USD"}, records[2])
+}
+
+func TestWriteOFX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatOFX, randomStatement()))
+	require.Contains(t, buf.String(), `OFXHEADER="200"`)
+
+	var doc ofxDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	res := doc.Bank.Transaction.Statement
+	require.Equal(t, "USD", res.Currency)
+	require.Equal(t, "7", res.BankAccount.AcctID)
+	require.Equal(t, "CHECKING", res.BankAccount.AcctType)
+	require.Equal(t, "95.00", res.LedgerBalance.Amount)
+	require.Len(t, res.TransactionList.Transactions, 2)
+	require.Equal(t, "DEBIT", res.TransactionList.Transactions[0].Type)
+	require.Equal(t, "-10.00", res.TransactionList.Transactions[0].Amount)
+	require.Equal(t, "20240101010000.000[0:UTC]", res.TransactionList.Transactions[0].DTPosted)
+}
+
+func TestWriteOFXSavings(t *testing.T) {
+	statement := randomStatement()
+	statement.AccountType = util.AccountTypeSavings
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatOFX, statement))
+
+	var doc ofxDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, "SAVINGS", doc.Bank.Transaction.Statement.BankAccount.AcctType)
+}
+
+func TestWriteCAMT053(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCAMT053, randomStatement()))
+
+	var doc camtDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	stmt := doc.Statement.Stmt
+	require.Len(t, stmt.Balances, 2)
+	require.Equal(t, "OPBD", stmt.Balances[0].Type.CdOrPrtry.Code)
+	require.Equal(t, "100.00", stmt.Balances[0].Amount.Value)
+	require.Equal(t, "CLBD", stmt.Balances[1].Type.CdOrPrtry.Code)
+	require.Equal(t, "95.00", stmt.Balances[1].Amount.Value)
+
+	require.Len(t, stmt.Entries, 2)
+	require.Equal(t, "10.00", stmt.Entries[0].Amount.Value)
+	require.Equal(t, "DBIT", stmt.Entries[0].CdtDbtInd)
+	require.Equal(t, "bob", stmt.Entries[0].Details.Transaction.RelatedParties.Creditor.Name)
+	require.Equal(t, "CRDT", stmt.Entries[1].CdtDbtInd)
+	require.Nil(t, stmt.Entries[1].Details)
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	require.Error(t, Write(&buf, Format("pdf"), randomStatement()))
+}
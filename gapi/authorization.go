@@ -2,11 +2,16 @@ package gapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	db "github.com/billy-le/simple-bank/db/sqlc"
 	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -58,3 +63,51 @@ func hasPermission(userRole string, accessibleRoles []string) bool {
 	}
 	return false
 }
+
+// Member roles allowed to see an account, to send money from it and to
+// manage it
+var (
+	accountViewers = []string{util.AccountMemberOwner, util.AccountMemberCoOwner, util.AccountMemberViewer, util.AccountMemberTransferor}
+	accountSenders = []string{util.AccountMemberOwner, util.AccountMemberCoOwner, util.AccountMemberTransferor}
+	accountHolders = []string{util.AccountMemberOwner, util.AccountMemberCoOwner}
+	accountOwners  = []string{util.AccountMemberOwner}
+)
+
+// authorizeAccount fails unless the user is a member of the account with one
+// of memberRoles, and returns the membership. Bankers act on every account as
+// its owner.
+func (server *Server) authorizeAccount(ctx context.Context, payload *token.Payload, account db.Account, memberRoles []string) (db.AccountMember, error) {
+	if payload.Role == util.BankerRole {
+		return db.AccountMember{AccountID: account.ID, Username: payload.Username, Role: util.AccountMemberOwner}, nil
+	}
+
+	member, err := server.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  payload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return member, status.Errorf(codes.PermissionDenied, "account [%d] doesn't belong to the authenticated user", account.ID)
+		}
+		return member, status.Errorf(codes.Internal, "failed to get account member: %s", err)
+	}
+
+	if !hasPermission(member.Role, memberRoles) {
+		return member, status.Errorf(codes.PermissionDenied, "a %s of account [%d] cannot do this", member.Role, account.ID)
+	}
+
+	return member, nil
+}
+
+// authorizeScheduledTransfer authorizes the user on the source account of a
+// schedule, so every member with one of memberRoles can manage it whoever set
+// it up
+func (server *Server) authorizeScheduledTransfer(ctx context.Context, payload *token.Payload, schedule db.ScheduledTransfer, memberRoles []string) error {
+	account, err := server.store.GetAccount(ctx, schedule.FromAccountID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.authorizeAccount(ctx, payload, account, memberRoles)
+	return err
+}
@@ -3,6 +3,7 @@ package gapi
 import (
 	db "github.com/billy-le/simple-bank/db/sqlc"
 	"github.com/billy-le/simple-bank/pb"
+	"github.com/jackc/pgx/v5/pgtype"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -15,3 +16,290 @@ func convertUser(user db.User) *pb.User {
 		CreatedAt:         timestamppb.New(user.CreatedAt),
 	}
 }
+
+func convertAccount(account db.Account) *pb.Account {
+	return &pb.Account{
+		Id:               account.ID,
+		Owner:            account.Owner,
+		Balance:          account.Balance,
+		Currency:         account.Currency,
+		CreatedAt:        timestamppb.New(account.CreatedAt),
+		OverdraftLimit:   account.OverdraftLimit,
+		HeldAmount:       account.HeldAmount,
+		AvailableBalance: account.AvailableBalance,
+		Type:             account.Type,
+		Status:           account.Status,
+		Nickname:         account.Nickname,
+	}
+}
+
+func convertAccountMember(member db.AccountMember) *pb.AccountMember {
+	return &pb.AccountMember{
+		AccountId:     member.AccountID,
+		Username:      member.Username,
+		Role:          member.Role,
+		TransferLimit: member.TransferLimit.Int64,
+		AddedBy:       member.AddedBy,
+		CreatedAt:     timestamppb.New(member.CreatedAt),
+	}
+}
+
+func convertAccountStatusChange(change db.AccountStatusChange) *pb.AccountStatusChange {
+	return &pb.AccountStatusChange{
+		Id:         change.ID,
+		AccountId:  change.AccountID,
+		FromStatus: change.FromStatus,
+		ToStatus:   change.ToStatus,
+		Reason:     change.Reason,
+		ChangedBy:  change.ChangedBy.String,
+		CreatedAt:  timestamppb.New(change.CreatedAt),
+	}
+}
+
+func convertCurrency(currency db.Currency) *pb.Currency {
+	return &pb.Currency{
+		Code:              currency.Code,
+		NumericCode:       currency.NumericCode,
+		Name:              currency.Name,
+		MinorUnits:        int32(currency.MinorUnits),
+		Enabled:           currency.Enabled,
+		ApprovalThreshold: currency.ApprovalThreshold,
+	}
+}
+
+func convertEntry(entry db.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:         entry.ID,
+		AccountId:  entry.AccountID,
+		Amount:     entry.Amount,
+		CreatedAt:  timestamppb.New(entry.CreatedAt),
+		TransferId: entry.TransferID.Int64,
+	}
+}
+
+func convertTransfer(transfer db.Transfer) *pb.Transfer {
+	return &pb.Transfer{
+		Id:             transfer.ID,
+		FromAccountId:  transfer.FromAccountID,
+		ToAccountId:    transfer.ToAccountID,
+		Amount:         transfer.Amount,
+		CreatedAt:      timestamppb.New(transfer.CreatedAt),
+		ToAmount:       transfer.ToAmount,
+		ExchangeRate:   convertNumeric(transfer.ExchangeRate),
+		SpreadBps:      transfer.SpreadBps.Int32,
+		ReversalOf:     transfer.ReversalOf.Int64,
+		ReasonCode:     transfer.ReasonCode.String,
+		ReversedAmount: transfer.ReversedAmount,
+		FeeOf:          transfer.FeeOf.Int64,
+	}
+}
+
+func convertTransfers(transfers []db.Transfer) []*pb.Transfer {
+	rsp := make([]*pb.Transfer, len(transfers))
+	for i, transfer := range transfers {
+		rsp[i] = convertTransfer(transfer)
+	}
+	return rsp
+}
+
+func convertExchangeRate(exchangeRate db.ExchangeRate) *pb.ExchangeRate {
+	return &pb.ExchangeRate{
+		FromCurrency: exchangeRate.FromCurrency,
+		ToCurrency:   exchangeRate.ToCurrency,
+		Rate:         convertNumeric(exchangeRate.Rate),
+		SpreadBps:    exchangeRate.SpreadBps,
+		UpdatedBy:    exchangeRate.UpdatedBy,
+		UpdatedAt:    timestamppb.New(exchangeRate.UpdatedAt),
+	}
+}
+
+// convertNumeric returns the decimal text of a numeric, or "" when it is null
+func convertFeeSchedule(schedule db.FeeSchedule) *pb.FeeSchedule {
+	rsp := &pb.FeeSchedule{
+		TransferType: schedule.TransferType,
+		Currency:     schedule.Currency,
+		Role:         schedule.Role,
+		MinAmount:    schedule.MinAmount,
+		FlatFee:      schedule.FlatFee,
+		RateBps:      schedule.RateBps,
+		MinFee:       schedule.MinFee,
+		UpdatedBy:    schedule.UpdatedBy,
+		UpdatedAt:    timestamppb.New(schedule.UpdatedAt),
+	}
+	if schedule.MaxFee.Valid {
+		rsp.MaxFee = &schedule.MaxFee.Int64
+	}
+	return rsp
+}
+
+func convertAccountProduct(product db.AccountProduct) *pb.AccountProduct {
+	return &pb.AccountProduct{
+		Code:            product.Code,
+		Name:            product.Name,
+		InterestRateBps: product.InterestRateBps,
+		DayCount:        product.DayCount,
+		UpdatedAt:       timestamppb.New(product.UpdatedAt),
+	}
+}
+
+func convertTransferLimit(limit db.TransferLimit) *pb.TransferLimit {
+	rsp := &pb.TransferLimit{
+		Id:        limit.ID,
+		Scope:     limit.Scope,
+		Currency:  limit.Currency,
+		Period:    limit.Period,
+		UpdatedBy: limit.UpdatedBy,
+		UpdatedAt: timestamppb.New(limit.UpdatedAt),
+	}
+	if limit.Role.Valid {
+		rsp.Role = &limit.Role.String
+	}
+	if limit.Username.Valid {
+		rsp.Username = &limit.Username.String
+	}
+	if limit.AccountID.Valid {
+		rsp.AccountId = &limit.AccountID.Int64
+	}
+	if limit.MaxAmount.Valid {
+		rsp.MaxAmount = &limit.MaxAmount.Int64
+	}
+	if limit.MaxCount.Valid {
+		rsp.MaxCount = &limit.MaxCount.Int32
+	}
+	return rsp
+}
+
+func convertTransferUsage(usage db.TransferUsage) *pb.TransferUsage {
+	rsp := &pb.TransferUsage{
+		Scope:      usage.Scope,
+		Period:     usage.Period,
+		Currency:   usage.Currency,
+		UsedAmount: usage.Amount,
+		UsedCount:  usage.Count,
+		ResetsAt:   timestamppb.New(usage.ResetsAt),
+	}
+	if usage.Limit.MaxAmount.Valid {
+		rsp.MaxAmount = &usage.Limit.MaxAmount.Int64
+	}
+	if usage.Limit.MaxCount.Valid {
+		rsp.MaxCount = &usage.Limit.MaxCount.Int32
+	}
+	if remaining, ok := usage.RemainingAmount(); ok {
+		rsp.RemainingAmount = &remaining
+	}
+	if remaining, ok := usage.RemainingCount(); ok {
+		rsp.RemainingCount = &remaining
+	}
+	return rsp
+}
+
+func convertNumeric(n pgtype.Numeric) string {
+	value, err := n.Value()
+	if err != nil || value == nil {
+		return ""
+	}
+	return value.(string)
+}
+
+func convertHold(hold db.Hold) *pb.Hold {
+	return &pb.Hold{
+		Id:             hold.ID,
+		AccountId:      hold.AccountID,
+		Amount:         hold.Amount,
+		Status:         hold.Status,
+		Description:    hold.Description,
+		CreatedBy:      hold.CreatedBy,
+		TransferId:     hold.TransferID.Int64,
+		CapturedAmount: hold.CapturedAmount,
+		ExpiresAt:      timestamppb.New(hold.ExpiresAt),
+		CreatedAt:      timestamppb.New(hold.CreatedAt),
+	}
+}
+
+func convertTransferRequest(request db.TransferRequest) *pb.TransferRequest {
+	return &pb.TransferRequest{
+		Id:            request.ID,
+		FromAccountId: request.FromAccountID,
+		ToAccountId:   request.ToAccountID,
+		Amount:        request.Amount,
+		CrossCurrency: request.CrossCurrency,
+		Status:        request.Status,
+		RequestedBy:   request.RequestedBy,
+		DecidedBy:     request.DecidedBy.String,
+		Reason:        request.Reason,
+		TransferId:    request.TransferID.Int64,
+		ExpiresAt:     timestamppb.New(request.ExpiresAt),
+		CreatedAt:     timestamppb.New(request.CreatedAt),
+	}
+}
+
+func convertSession(session db.Session) *pb.Session {
+	return &pb.Session{
+		Id:        session.ID.String(),
+		Username:  session.Username,
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		CreatedAt: timestamppb.New(session.CreatedAt),
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+	}
+}
+
+func convertScheduledTransfer(schedule db.ScheduledTransfer) *pb.ScheduledTransfer {
+	return &pb.ScheduledTransfer{
+		Id:             schedule.ID,
+		Owner:          schedule.Owner,
+		FromAccountId:  schedule.FromAccountID,
+		ToAccountId:    schedule.ToAccountID,
+		Amount:         schedule.Amount,
+		CrossCurrency:  schedule.CrossCurrency,
+		Rule:           schedule.Rule,
+		CronExpression: schedule.CronExpression,
+		StartTime:      timestamppb.New(schedule.StartAt),
+		EndTime:        convertTimestamptz(schedule.EndAt),
+		NextRunTime:    convertTimestamptz(schedule.NextRunAt),
+		Status:         schedule.Status,
+		CreatedAt:      timestamppb.New(schedule.CreatedAt),
+		CreatedBy:      schedule.CreatedBy,
+	}
+}
+
+func convertScheduledTransferRun(run db.ScheduledTransferRun) *pb.ScheduledTransferRun {
+	return &pb.ScheduledTransferRun{
+		Id:                  run.ID,
+		ScheduledTransferId: run.ScheduledTransferID,
+		ScheduledFor:        timestamppb.New(run.ScheduledFor),
+		Status:              run.Status,
+		TransferId:          run.TransferID.Int64,
+		Error:               run.Error,
+		CreatedAt:           timestamppb.New(run.CreatedAt),
+		UpdatedAt:           timestamppb.New(run.UpdatedAt),
+	}
+}
+
+func convertTrialBalance(balance db.TrialBalance) *pb.TrialBalance {
+	rsp := &pb.TrialBalance{
+		Currency:     balance.Currency,
+		Lines:        make([]*pb.TrialBalanceLine, len(balance.Lines)),
+		TotalDebits:  balance.TotalDebits,
+		TotalCredits: balance.TotalCredits,
+		Balanced:     balance.Balanced(),
+	}
+	for i, line := range balance.Lines {
+		rsp.Lines[i] = &pb.TrialBalanceLine{
+			LedgerCode: line.LedgerCode,
+			Name:       line.Name,
+			Type:       line.Type,
+			Debits:     line.Debits,
+			Credits:    line.Credits,
+		}
+	}
+	return rsp
+}
+
+// convertTimestamptz returns nil for a null timestamp
+func convertTimestamptz(t pgtype.Timestamptz) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
@@ -1,6 +1,11 @@
 package gapi
 
 import (
+	"fmt"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/util"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -25,3 +30,28 @@ func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 	return statusDetails.Err()
 
 }
+
+// transferLimitError reports the limit a transfer went over with what is
+// left of it, both as a quota failure and as the usage itself
+func transferLimitError(limitErr *db.TransferLimitError) error {
+	usage := limitErr.Usage
+	statusExhausted := status.New(codes.ResourceExhausted, limitErr.Error())
+
+	subject := fmt.Sprintf("user:%s", limitErr.Owner)
+	if usage.Scope == util.LimitScopeAccount {
+		subject = fmt.Sprintf("account:%d", limitErr.AccountID)
+	}
+	quotaFailure := &errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     subject,
+			Description: fmt.Sprintf("%s %s limit in %s resets at %s", usage.Period, usage.Scope, usage.Currency, usage.ResetsAt.Format(time.RFC3339)),
+		}},
+	}
+
+	statusDetails, err := statusExhausted.WithDetails(quotaFailure, convertTransferUsage(usage))
+	if err != nil {
+		return statusExhausted.Err()
+	}
+
+	return statusDetails.Err()
+}
@@ -0,0 +1,60 @@
+package gapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/billy-le/simple-bank/export"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const ExportStatementPath = "/v1/accounts/{account_id}/statement/export"
+
+// ExportStatementHandler serves ExportStatement as a file download on the gateway,
+// since the in-process gateway cannot serve server streaming calls.
+func (server *Server) ExportStatementHandler(mux *runtime.ServeMux) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateIncomingContext(r.Context(), mux, r, "/pb.SimpleBank/ExportStatement", runtime.WithHTTPPathPattern(ExportStatementPath))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		req := &pb.ExportStatementRequest{}
+		if err := r.ParseForm(); err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Errorf(codes.InvalidArgument, "%v", err))
+			return
+		}
+		if err := runtime.PopulateQueryParameters(req, r.Form, utilities.NewDoubleArray(nil)); err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Errorf(codes.InvalidArgument, "%v", err))
+			return
+		}
+
+		req.AccountId, err = runtime.Int64(pathParams["account_id"])
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, status.Errorf(codes.InvalidArgument, "type mismatch, parameter: account_id, error: %v", err))
+			return
+		}
+
+		format, statement, err := server.prepareStatementExport(ctx, req)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName(format, statement)))
+
+		if err := export.Write(w, format, statement); err != nil {
+			// the status has already been sent, so the best we can do is log it
+			log.Error().Err(err).Int64("account_id", statement.AccountID).Msg("failed to write statement export")
+		}
+	}
+}
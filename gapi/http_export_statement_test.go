@@ -0,0 +1,165 @@
+package gapi
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHttpExportStatement(t *testing.T) {
+	user, _ := createRandomUser(t)
+	otherUser, _ := createRandomUser(t)
+
+	account := createRandomAccount(user.Username)
+	account.Currency = "USD"
+	otherAccount := createRandomAccount(otherUser.Username)
+
+	entries := []db.ListStatementEntriesRow{
+		{
+			ID:               1,
+			AccountID:        account.ID,
+			Amount:           -1050,
+			CreatedAt:        time.Now(),
+			TransferID:       pgtype.Int8{Int64: 9, Valid: true},
+			FromAccountID:    pgtype.Int8{Int64: account.ID, Valid: true},
+			ToAccountID:      pgtype.Int8{Int64: otherAccount.ID, Valid: true},
+			FromAccountOwner: pgtype.Text{String: account.Owner, Valid: true},
+			ToAccountOwner:   pgtype.Text{String: otherAccount.Owner, Valid: true},
+		},
+	}
+
+	query := "?start_time=2024-01-01T00:00:00Z&end_time=2024-02-01T00:00:00Z&format=STATEMENT_FORMAT_CSV"
+
+	testCases := []struct {
+		name          string
+		url           string
+		buildStubs    func(store *mockdb.MockStore)
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Ok",
+			url:  fmt.Sprintf("/v1/accounts/%d/statement/export%s", account.ID, query),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
+				store.EXPECT().GetStatementBalances(gomock.Any(), gomock.Any()).Times(1).Return(db.GetStatementBalancesRow{OpeningBalance: 2000, ClosingBalance: 950}, nil)
+				store.EXPECT().ListStatementEntries(gomock.Any(), gomock.Any()).Times(1).Return(entries, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addBearerToken(t, request, tokenMaker, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), "attachment")
+
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, 2)
+				require.Equal(t, "-10.50", records[1][6])
+				require.Equal(t, "9.50", records[1][7])
+			},
+		},
+		{
+			name: "ClosingBalanceFromEntries",
+			url:  fmt.Sprintf("/v1/accounts/%d/statement/export?start_time=2024-01-01T00:00:00Z&end_time=2024-02-01T00:00:00Z&format=STATEMENT_FORMAT_OFX", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
+				// an entry posted after the balances were read but before the
+				// entries were
+				store.EXPECT().GetStatementBalances(gomock.Any(), gomock.Any()).Times(1).Return(db.GetStatementBalancesRow{OpeningBalance: 2000, ClosingBalance: 2000}, nil)
+				store.EXPECT().ListStatementEntries(gomock.Any(), gomock.Any()).Times(1).Return(entries, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addBearerToken(t, request, tokenMaker, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "<BALAMT>9.50</BALAMT>")
+			},
+		},
+		{
+			name: "PermissionDenied",
+			url:  fmt.Sprintf("/v1/accounts/%d/statement/export%s", otherAccount.ID, query),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: otherAccount.ID, Username: user.Username})).Times(1).Return(accountMembership(otherAccount, user.Username))
+				store.EXPECT().ListStatementEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addBearerToken(t, request, tokenMaker, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			url:  fmt.Sprintf("/v1/accounts/%d/statement/export%s", account.ID, query),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingFormat",
+			url:  fmt.Sprintf("/v1/accounts/%d/statement/export?start_time=2024-01-01T00:00:00Z&end_time=2024-02-01T00:00:00Z", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addBearerToken(t, request, tokenMaker, user)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			mux := runtime.NewServeMux()
+			err := mux.HandlePath(http.MethodGet, ExportStatementPath, server.ExportStatementHandler(mux))
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodGet, testCase.url, nil)
+			require.NoError(t, err)
+			testCase.setupAuth(t, request, server.tokenMaker)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func addBearerToken(t *testing.T, request *http.Request, tokenMaker token.Maker, user db.User) {
+	accessToken, _, err := tokenMaker.CreateToken(user.Username, user.Role, time.Minute)
+	require.NoError(t, err)
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+}
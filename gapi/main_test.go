@@ -24,8 +24,9 @@ func TestMain(m *testing.M) {
 
 func newTestServer(t *testing.T, store db.Store, taskDistributor worker.TaskDistributor) *Server {
 	config := util.Config{
-		TokenSymmetricKey:   util.RandomString(32),
-		AccessTokenDuration: time.Minute,
+		TokenSymmetricKey:       util.RandomString(32),
+		AccessTokenDuration:     time.Minute,
+		TransferRequestDuration: time.Hour,
 	}
 
 	server, err := NewServer(config, store, taskDistributor)
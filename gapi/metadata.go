@@ -2,7 +2,9 @@ package gapi
 
 import (
 	"context"
+	"strings"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
@@ -11,11 +13,22 @@ const (
 	grpcGatewayUserAgentHeader = "grpcgateway-user-agent"
 	xForwardedForHeader        = "x-forwarded-for"
 	userAgentHeader            = "user-agent"
+	idempotencyKeyHeader       = "idempotency-key"
 )
 
 type Metadata struct {
-	ClientIP  string
-	UserAgent string
+	ClientIP       string
+	UserAgent      string
+	IdempotencyKey string
+}
+
+// IncomingHeaderMatcher forwards the Idempotency-Key HTTP header to gRPC metadata
+// in addition to the headers the gateway forwards by default.
+func IncomingHeaderMatcher(key string) (string, bool) {
+	if strings.ToLower(key) == idempotencyKeyHeader {
+		return idempotencyKeyHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
 }
 
 func (server *Server) extractMetaData(ctx context.Context) *Metadata {
@@ -33,6 +46,10 @@ func (server *Server) extractMetaData(ctx context.Context) *Metadata {
 		if ipAddress := md.Get(xForwardedForHeader); len(ipAddress) > 0 {
 			mtdt.ClientIP = ipAddress[0]
 		}
+
+		if keys := md.Get(idempotencyKeyHeader); len(keys) > 0 {
+			mtdt.IdempotencyKey = keys[0]
+		}
 	}
 
 	if p, ok := peer.FromContext(ctx); ok {
@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ApproveTransferRequest(ctx context.Context, req *pb.ApproveTransferRequestRequest) (*pb.ApproveTransferRequestResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateApproveTransferRequestRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	txResult, err := server.store.ApproveTransferRequestTx(ctx, db.ApproveTransferRequestTxParams{
+		ID:         req.GetId(),
+		ApprovedBy: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "transfer request not found: %s", err)
+		}
+		if errors.Is(err, db.ErrSelfApproval) {
+			return nil, status.Errorf(codes.PermissionDenied, "%s", err)
+		}
+		if errors.Is(err, db.ErrTransferRequestNotPending) ||
+			errors.Is(err, db.ErrTransferRequestExpired) ||
+			errors.Is(err, db.ErrInsufficientFunds) ||
+			errors.Is(err, db.ErrExchangeRateNotFound) ||
+			errors.Is(err, db.ErrConvertedAmountTooSmall) ||
+			errors.Is(err, db.ErrAccountFrozen) ||
+			errors.Is(err, db.ErrAccountDormant) ||
+			errors.Is(err, db.ErrAccountClosed) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		var limitErr *db.TransferLimitError
+		if errors.As(err, &limitErr) {
+			return nil, transferLimitError(limitErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to approve transfer request: %s", err)
+	}
+
+	rsp := &pb.ApproveTransferRequestResponse{
+		TransferRequest: convertTransferRequest(txResult.TransferRequest),
+		Transfer:        convertTransfer(txResult.Transfer),
+		FromAccount:     convertAccount(txResult.FromAccount),
+		ToAccount:       convertAccount(txResult.ToAccount),
+		FromEntry:       convertEntry(txResult.FromEntry),
+		ToEntry:         convertEntry(txResult.ToEntry),
+		Fees:            convertTransfers(txResult.Fees),
+	}
+
+	return rsp, nil
+}
+
+func validateApproveTransferRequestRequest(req *pb.ApproveTransferRequestRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+
+	return violations
+}
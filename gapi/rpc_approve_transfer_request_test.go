@@ -0,0 +1,170 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcApproveTransferRequest(t *testing.T) {
+	maker, _ := createRandomUser(t)
+	checker, _ := createRandomUser(t)
+	depositor, _ := createRandomUser(t)
+
+	account1 := createRandomAccount(depositor.Username)
+	account2 := createRandomAccount(maker.Username)
+	amount := int64(1_000_000)
+
+	request := db.TransferRequest{
+		ID:            1,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+		Status:        db.TransferRequestStatusApproved,
+		RequestedBy:   maker.Username,
+		DecidedBy:     pgtype.Text{String: checker.Username, Valid: true},
+		TransferID:    pgtype.Int8{Int64: 1, Valid: true},
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.ApproveTransferRequestRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.ApproveTransferRequestResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.ApproveTransferRequestRequest{
+				Id: request.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ApproveTransferRequestTxParams{
+					ID:         request.ID,
+					ApprovedBy: checker.Username,
+				}
+				result := db.ApproveTransferRequestTxResult{
+					TransferRequest: request,
+					TransferTxResult: db.TransferTxResult{
+						Transfer:    db.Transfer{ID: 1, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: amount},
+						FromAccount: account1,
+						ToAccount:   account2,
+					},
+				}
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, checker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ApproveTransferRequestResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.TransferRequestStatusApproved, res.GetTransferRequest().GetStatus())
+				require.Equal(t, checker.Username, res.GetTransferRequest().GetDecidedBy())
+				require.Equal(t, res.GetTransfer().GetId(), res.GetTransferRequest().GetTransferId())
+				require.Equal(t, amount, res.GetTransfer().GetAmount())
+			},
+		},
+		{
+			name: "SelfApproval",
+			req: &pb.ApproveTransferRequestRequest{
+				Id: request.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ApproveTransferRequestTxResult{}, db.ErrSelfApproval)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, maker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ApproveTransferRequestResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req: &pb.ApproveTransferRequestRequest{
+				Id: request.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, depositor.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ApproveTransferRequestResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "Expired",
+			req: &pb.ApproveTransferRequestRequest{
+				Id: request.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ApproveTransferRequestTxResult{}, db.ErrTransferRequestExpired)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, checker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ApproveTransferRequestResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "NotFound",
+			req: &pb.ApproveTransferRequestRequest{
+				Id: request.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApproveTransferRequestTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ApproveTransferRequestTxResult{}, db.ErrRecordNotFound)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, checker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ApproveTransferRequestResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.ApproveTransferRequest(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
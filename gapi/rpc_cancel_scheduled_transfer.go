@@ -0,0 +1,21 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+)
+
+func (server *Server) CancelScheduledTransfer(ctx context.Context, req *pb.CancelScheduledTransferRequest) (*pb.CancelScheduledTransferResponse, error) {
+	schedule, err := server.setScheduledTransferStatus(ctx, req.GetId(), db.ScheduledTransferStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.CancelScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(schedule),
+	}
+
+	return rsp, nil
+}
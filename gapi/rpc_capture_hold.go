@@ -0,0 +1,111 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CaptureHold(ctx context.Context, req *pb.CaptureHoldRequest) (*pb.CaptureHoldResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCaptureHoldRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	hold, err := server.store.GetHold(ctx, req.GetHoldId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "hold not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get hold: %s", err)
+	}
+
+	if hold.AccountID == req.GetToAccountId() {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot capture a hold into the account it is on")
+	}
+
+	fromAccount, err := server.store.GetAccount(ctx, hold.AccountID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.validAccount(ctx, req.GetToAccountId(), fromAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// a capture settles the hold on the spot, so one that would need a
+	// second banker's approval as a transfer cannot be made this way
+	amount := req.GetAmount()
+	if amount == 0 {
+		amount = hold.Amount
+	}
+	if util.RequiresApproval(fromAccount.Currency, amount) {
+		return nil, status.Errorf(codes.FailedPrecondition, "capture is above the %s approval threshold; release the hold and request a transfer instead", fromAccount.Currency)
+	}
+
+	txResult, err := server.store.CaptureHoldTx(ctx, db.CaptureHoldTxParams{
+		HoldID:      hold.ID,
+		ToAccountID: req.GetToAccountId(),
+		Amount:      req.GetAmount(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrHoldNotPending) ||
+			errors.Is(err, db.ErrHoldExpired) ||
+			errors.Is(err, db.ErrCaptureExceedsHold) ||
+			errors.Is(err, db.ErrInsufficientFunds) ||
+			errors.Is(err, db.ErrAccountFrozen) ||
+			errors.Is(err, db.ErrAccountDormant) ||
+			errors.Is(err, db.ErrAccountClosed) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		var limitErr *db.TransferLimitError
+		if errors.As(err, &limitErr) {
+			return nil, transferLimitError(limitErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to capture hold: %s", err)
+	}
+
+	rsp := &pb.CaptureHoldResponse{
+		Hold:        convertHold(txResult.Hold),
+		Transfer:    convertTransfer(txResult.Transfer),
+		FromAccount: convertAccount(txResult.FromAccount),
+		ToAccount:   convertAccount(txResult.ToAccount),
+		FromEntry:   convertEntry(txResult.FromEntry),
+		ToEntry:     convertEntry(txResult.ToEntry),
+		Fees:        convertTransfers(txResult.Fees),
+	}
+
+	return rsp, nil
+}
+
+func validateCaptureHoldRequest(req *pb.CaptureHoldRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetHoldId()); err != nil {
+		violations = append(violations, fieldViolation("hold_id", err))
+	}
+
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+
+	// zero captures the whole hold
+	if req.GetAmount() != 0 {
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolation("amount", err))
+		}
+	}
+
+	return violations
+}
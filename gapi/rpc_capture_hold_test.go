@@ -0,0 +1,233 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcCaptureHold(t *testing.T) {
+	banker, _ := createRandomUser(t)
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+
+	account1 := createRandomAccount(user1.Username)
+	account2 := createRandomAccount(user2.Username)
+	account2.ID = account1.ID + 1
+	account1.Currency = util.USD
+	account2.Currency = util.USD
+
+	hold := db.Hold{
+		ID:        1,
+		AccountID: account1.ID,
+		Amount:    100,
+		Status:    db.HoldStatusPending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.CaptureHoldRequest
+		buildStubs     func(store *mockdb.MockStore)
+		checkResponses func(t *testing.T, res *pb.CaptureHoldResponse, err error)
+	}{
+		{
+			name: "PartialCapture",
+			req: &pb.CaptureHoldRequest{
+				HoldId:      hold.ID,
+				ToAccountId: account2.ID,
+				Amount:      60,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				arg := db.CaptureHoldTxParams{
+					HoldID:      hold.ID,
+					ToAccountID: account2.ID,
+					Amount:      60,
+				}
+				captured := hold
+				captured.Status = db.HoldStatusCaptured
+				captured.CapturedAmount = 60
+				captured.TransferID = pgtype.Int8{Int64: 1, Valid: true}
+				result := db.CaptureHoldTxResult{
+					Hold: captured,
+					TransferTxResult: db.TransferTxResult{
+						Transfer: db.Transfer{ID: 1, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 60, ToAmount: 60},
+					},
+				}
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponses: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.HoldStatusCaptured, res.GetHold().GetStatus())
+				require.Equal(t, int64(60), res.GetHold().GetCapturedAmount())
+				require.Equal(t, int64(60), res.GetTransfer().GetAmount())
+			},
+		},
+		{
+			name: "HoldNotFound",
+			req: &pb.CaptureHoldRequest{
+				HoldId:      hold.ID,
+				ToAccountId: account2.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(db.Hold{}, db.ErrRecordNotFound)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "CaptureIntoSameAccount",
+			req: &pb.CaptureHoldRequest{
+				HoldId:      hold.ID,
+				ToAccountId: account1.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "HoldNotPending",
+			req: &pb.CaptureHoldRequest{
+				HoldId:      hold.ID,
+				ToAccountId: account2.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, db.ErrHoldNotPending)
+			},
+			checkResponses: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "TransferLimitExceeded",
+			req: &pb.CaptureHoldRequest{
+				HoldId:      hold.ID,
+				ToAccountId: account2.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				limitErr := &db.TransferLimitError{
+					AccountID: account1.ID,
+					Owner:     user1.Username,
+					Usage: db.TransferUsage{
+						Scope:    util.LimitScopeAccount,
+						Period:   util.LimitPeriodDaily,
+						Currency: util.USD,
+						Limit: db.TransferLimit{
+							MaxAmount: pgtype.Int8{Int64: hold.Amount - 1, Valid: true},
+						},
+					},
+				}
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, limitErr)
+			},
+			checkResponses: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.ResourceExhausted, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := newContextWithBearerToken(t, server.tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			res, err := server.CaptureHold(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
+
+func TestRpcCaptureHoldAboveApprovalThreshold(t *testing.T) {
+	launched := util.Currencies()
+	defer util.SetCurrencies(launched)
+
+	threshold := int64(1_000)
+	util.SetCurrencies([]util.Currency{
+		{Code: util.USD, NumericCode: "840", MinorUnits: 2, Enabled: true, ApprovalThreshold: threshold},
+	})
+
+	banker, _ := createRandomUser(t)
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+
+	account1 := createRandomAccount(user1.Username)
+	account2 := createRandomAccount(user2.Username)
+	account2.ID = account1.ID + 1
+	account1.Currency = util.USD
+	account2.Currency = util.USD
+
+	hold := db.Hold{
+		ID:        1,
+		AccountID: account1.ID,
+		Amount:    threshold + 1,
+		Status:    db.HoldStatusPending,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+
+	store := mockdb.NewMockStore(storeCtrl)
+	store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+	store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store, nil)
+
+	// capturing the whole hold would need a second banker's approval
+	ctx := newContextWithBearerToken(t, server.tokenMaker, banker.Username, util.BankerRole, time.Minute)
+	_, err := server.CaptureHold(ctx, &pb.CaptureHoldRequest{
+		HoldId:      hold.ID,
+		ToAccountId: account2.ID,
+	})
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.FailedPrecondition, st.Code())
+}
@@ -0,0 +1,81 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CloseAccount(ctx context.Context, req *pb.CloseAccountRequest) (*pb.CloseAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCloseAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, account, accountOwners)
+	if err != nil {
+		return nil, err
+	}
+
+	// the account is kept with its history, only its status changes
+	reason := req.GetReason()
+	if reason == "" {
+		reason = "closed on request"
+	}
+
+	txResult, err := server.store.SetAccountStatusTx(ctx, db.SetAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    util.AccountStatusClosed,
+		Reason:    reason,
+		ChangedBy: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrAccountStatusTransition) ||
+			errors.Is(err, db.ErrAccountBalanceNotZero) ||
+			errors.Is(err, db.ErrInterestNotPosted) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to close account: %s", err)
+	}
+
+	rsp := &pb.CloseAccountResponse{
+		IsClosed: true,
+		Account:  convertAccount(txResult.Account),
+	}
+
+	return rsp, nil
+}
+
+func validateCloseAccountRequest(req *pb.CloseAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+
+	if req.GetReason() != "" {
+		if err := val.ValidateString(req.GetReason(), 1, 200); err != nil {
+			violations = append(violations, fieldViolation("reason", err))
+		}
+	}
+
+	return violations
+}
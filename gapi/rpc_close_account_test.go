@@ -0,0 +1,191 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcCloseAccount(t *testing.T) {
+	user, _ := createRandomUser(t)
+	otherUser, _ := createRandomUser(t)
+
+	account := createRandomAccount(user.Username)
+	account.Balance = 0
+
+	closedAccount := account
+	closedAccount.Status = util.AccountStatusClosed
+
+	fundedAccount := createRandomAccount(user.Username)
+
+	testCases := []struct {
+		name           string
+		req            *pb.CloseAccountRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.CloseAccountResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.CloseAccountRequest{
+				Id: account.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
+
+				arg := db.SetAccountStatusTxParams{
+					AccountID: account.ID,
+					Status:    util.AccountStatusClosed,
+					Reason:    "closed on request",
+					ChangedBy: user.Username,
+				}
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.SetAccountStatusTxResult{Account: closedAccount}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.True(t, res.GetIsClosed())
+				require.Equal(t, util.AccountStatusClosed, res.GetAccount().GetStatus())
+			},
+		},
+		{
+			name: "NotFound",
+			req: &pb.CloseAccountRequest{
+				Id: account.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.NotFound)
+			},
+		},
+		{
+			name: "PermissionDenied",
+			req: &pb.CloseAccountRequest{
+				Id: account.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: otherUser.Username})).Times(1).Return(accountMembership(account, otherUser.Username))
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, otherUser.Username, otherUser.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.PermissionDenied)
+			},
+		},
+		{
+			name: "BankerCanClose",
+			req: &pb.CloseAccountRequest{
+				Id:     account.ID,
+				Reason: "customer passed away",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.SetAccountStatusTxParams{
+					AccountID: account.ID,
+					Status:    util.AccountStatusClosed,
+					Reason:    "customer passed away",
+					ChangedBy: otherUser.Username,
+				}
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.SetAccountStatusTxResult{Account: closedAccount}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, otherUser.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsClosed())
+			},
+		},
+		{
+			name: "NonZeroBalance",
+			req: &pb.CloseAccountRequest{
+				Id: fundedAccount.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fundedAccount.ID)).Times(1).Return(fundedAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: fundedAccount.ID, Username: user.Username})).Times(1).Return(accountMembership(fundedAccount, user.Username))
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.SetAccountStatusTxResult{}, db.ErrAccountBalanceNotZero)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.FailedPrecondition)
+			},
+		},
+		{
+			name: "InvalidID",
+			req: &pb.CloseAccountRequest{
+				Id: 0,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CloseAccountResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.InvalidArgument)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.CloseAccount(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	owner := authPayload.Username
+	if req.Owner != nil {
+		owner = req.GetOwner()
+	}
+
+	if authPayload.Role != util.BankerRole && authPayload.Username != owner {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot create account for other user")
+	}
+
+	violations := validateCreateAccountRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	accountType := util.AccountTypeChecking
+	if req.Type != nil {
+		accountType = req.GetType()
+	}
+
+	txResult, err := server.store.CreateAccountTx(ctx, db.CreateAccountParams{
+		Owner:    owner,
+		Balance:  0,
+		Currency: req.GetCurrency(),
+		Type:     accountType,
+		Nickname: req.GetNickname(),
+	})
+	if err != nil {
+		switch db.ErrorCode(err) {
+		case db.ForeignKeyViolation:
+			return nil, status.Errorf(codes.NotFound, "owner not found: %s", err)
+		case db.UniqueViolation:
+			return nil, status.Errorf(codes.AlreadyExists, "%s already has a %s account in %s: %s", owner, accountType, req.GetCurrency(), err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create account: %s", err)
+	}
+
+	rsp := &pb.CreateAccountResponse{
+		Account: convertAccount(txResult.Account),
+	}
+
+	return rsp, nil
+}
+
+func validateCreateAccountRequest(req *pb.CreateAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Owner != nil {
+		if err := val.ValidateUsername(req.GetOwner()); err != nil {
+			violations = append(violations, fieldViolation("owner", err))
+		}
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if req.Type != nil {
+		if err := val.ValidateAccountType(req.GetType()); err != nil {
+			violations = append(violations, fieldViolation("type", err))
+		}
+	}
+
+	if req.Nickname != nil {
+		if err := val.ValidateAccountNickname(req.GetNickname()); err != nil {
+			violations = append(violations, fieldViolation("nickname", err))
+		}
+	}
+
+	return violations
+}
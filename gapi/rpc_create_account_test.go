@@ -0,0 +1,302 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcCreateAccount(t *testing.T) {
+	user, _ := createRandomUser(t)
+	otherUser, _ := createRandomUser(t)
+	account := createRandomAccount(user.Username)
+	savingsType := util.AccountTypeSavings
+	invalidType := "brokerage"
+	nickname := "rainy day"
+	longNickname := util.RandomString(51)
+
+	testCases := []struct {
+		name           string
+		req            *pb.CreateAccountRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.CreateAccountResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.CreateAccountRequest{
+				Currency: account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateAccountParams{
+					Owner:    user.Username,
+					Balance:  0,
+					Currency: account.Currency,
+					Type:     util.AccountTypeChecking,
+				}
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.CreateAccountTxResult{Account: account}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				createdAccount := res.GetAccount()
+				require.Equal(t, account.ID, createdAccount.Id)
+				require.Equal(t, account.Owner, createdAccount.Owner)
+				require.Equal(t, account.Currency, createdAccount.Currency)
+			},
+		},
+		{
+			name: "BankerForOtherUser",
+			req: &pb.CreateAccountRequest{
+				Owner:    &user.Username,
+				Currency: account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateAccountParams{
+					Owner:    user.Username,
+					Balance:  0,
+					Currency: account.Currency,
+					Type:     util.AccountTypeChecking,
+				}
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.CreateAccountTxResult{Account: account}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, otherUser.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, user.Username, res.GetAccount().Owner)
+			},
+		},
+		{
+			name: "Savings",
+			req: &pb.CreateAccountRequest{
+				Currency: account.Currency,
+				Type:     &savingsType,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateAccountParams{
+					Owner:    user.Username,
+					Balance:  0,
+					Currency: account.Currency,
+					Type:     util.AccountTypeSavings,
+				}
+				savings := account
+				savings.Type = util.AccountTypeSavings
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.CreateAccountTxResult{Account: savings}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, util.AccountTypeSavings, res.GetAccount().Type)
+			},
+		},
+		{
+			name: "SavingsWithNickname",
+			req: &pb.CreateAccountRequest{
+				Currency: account.Currency,
+				Type:     &savingsType,
+				Nickname: &nickname,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateAccountParams{
+					Owner:    user.Username,
+					Balance:  0,
+					Currency: account.Currency,
+					Type:     util.AccountTypeSavings,
+					Nickname: nickname,
+				}
+				savings := account
+				savings.Type = util.AccountTypeSavings
+				savings.Nickname = nickname
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.CreateAccountTxResult{Account: savings}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, util.AccountTypeSavings, res.GetAccount().Type)
+				require.Equal(t, nickname, res.GetAccount().Nickname)
+			},
+		},
+		{
+			name: "InvalidNickname",
+			req: &pb.CreateAccountRequest{
+				Currency: account.Currency,
+				Nickname: &longNickname,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidType",
+			req: &pb.CreateAccountRequest{
+				Currency: account.Currency,
+				Type:     &invalidType,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.InvalidArgument)
+			},
+		},
+		{
+			name: "PermissionDenied",
+			req: &pb.CreateAccountRequest{
+				Owner:    &user.Username,
+				Currency: account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, otherUser.Username, otherUser.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.PermissionDenied)
+			},
+		},
+		{
+			name: "InvalidCurrency",
+			req: &pb.CreateAccountRequest{
+				Currency: "AUD",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.InvalidArgument)
+			},
+		},
+		{
+			name: "AlreadyExists",
+			req: &pb.CreateAccountRequest{
+				Currency: account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CreateAccountTxResult{}, db.ErrUniqueViolation)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.AlreadyExists)
+			},
+		},
+		{
+			name: "ExpiredToken",
+			req: &pb.CreateAccountRequest{
+				Currency: account.Currency,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, -time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.Unauthenticated)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.CreateAccount(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
+
+// accountMembership looks up username on the account, where only its owner
+// is a member
+func accountMembership(account db.Account, username string) (db.AccountMember, error) {
+	if username != account.Owner {
+		return db.AccountMember{}, db.ErrRecordNotFound
+	}
+
+	member := db.AccountMember{
+		AccountID: account.ID,
+		Username:  account.Owner,
+		Role:      util.AccountMemberOwner,
+		AddedBy:   account.Owner,
+	}
+	return member, nil
+}
+
+func createRandomAccount(owner string) db.Account {
+	return db.Account{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    owner,
+		Balance:  util.RandomMoney(),
+		Currency: util.RandomCurrency(),
+		Type:     util.AccountTypeChecking,
+	}
+}
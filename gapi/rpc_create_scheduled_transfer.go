@@ -0,0 +1,149 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateScheduledTransfer(ctx context.Context, req *pb.CreateScheduledTransferRequest) (*pb.CreateScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateCreateScheduledTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, fromAccount, accountHolders)
+	if err != nil {
+		return nil, err
+	}
+
+	// scheduled runs are made unattended, with no banker to approve them
+	if util.RequiresApproval(fromAccount.Currency, req.GetAmount()) {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("amount", fmt.Errorf("is above the %s approval threshold and cannot be scheduled", fromAccount.Currency)),
+		})
+	}
+
+	toCurrency := req.GetToCurrency()
+	if toCurrency == "" {
+		toCurrency = req.GetCurrency()
+	}
+
+	_, err = server.validAccount(ctx, req.GetToAccountId(), toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.CreateScheduledTransferParams{
+		// transfers run on behalf of the account owner, even when a co-owner
+		// or a banker set them up
+		Owner:          fromAccount.Owner,
+		FromAccountID:  req.GetFromAccountId(),
+		ToAccountID:    req.GetToAccountId(),
+		Amount:         req.GetAmount(),
+		CrossCurrency:  toCurrency != req.GetCurrency(),
+		Rule:           req.GetRule(),
+		CronExpression: req.GetCronExpression(),
+		StartAt:        req.GetStartTime().AsTime(),
+		CreatedBy:      authPayload.Username,
+	}
+	if req.EndTime != nil {
+		arg.EndAt = pgtype.Timestamptz{Time: req.GetEndTime().AsTime(), Valid: true}
+	}
+
+	_, arg.NextRunAt, err = db.NextScheduledRun(db.ScheduledTransfer{
+		Rule:           arg.Rule,
+		CronExpression: arg.CronExpression,
+		StartAt:        arg.StartAt,
+		EndAt:          arg.EndAt,
+	}, arg.StartAt.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to work out the first run: %s", err)
+	}
+
+	if !arg.NextRunAt.Valid {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("end_time", fmt.Errorf("leaves no occurrence to run")),
+		})
+	}
+
+	schedule, err := server.store.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create scheduled transfer: %s", err)
+	}
+
+	rsp := &pb.CreateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(schedule),
+	}
+
+	return rsp, nil
+}
+
+func validateCreateScheduledTransferRequest(req *pb.CreateScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("must be different from from_account_id")))
+	}
+
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if req.GetToCurrency() != "" {
+		if err := val.ValidateCurrency(req.GetToCurrency()); err != nil {
+			violations = append(violations, fieldViolation("to_currency", err))
+		}
+	}
+
+	if err := val.ValidateScheduleRule(req.GetRule()); err != nil {
+		violations = append(violations, fieldViolation("rule", err))
+	}
+
+	if req.GetRule() == util.ScheduleCron {
+		if err := val.ValidateCronExpression(req.GetCronExpression()); err != nil {
+			violations = append(violations, fieldViolation("cron_expression", err))
+		}
+	} else if req.GetCronExpression() != "" {
+		violations = append(violations, fieldViolation("cron_expression", fmt.Errorf("is only allowed with the cron rule")))
+	}
+
+	if req.StartTime == nil || !req.GetStartTime().AsTime().After(time.Now()) {
+		violations = append(violations, fieldViolation("start_time", fmt.Errorf("must be in the future")))
+	}
+
+	if req.EndTime != nil && !req.GetEndTime().AsTime().After(req.GetStartTime().AsTime()) {
+		violations = append(violations, fieldViolation("end_time", fmt.Errorf("must be after start_time")))
+	}
+
+	return violations
+}
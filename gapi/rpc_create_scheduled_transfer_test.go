@@ -0,0 +1,258 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRpcCreateScheduledTransfer(t *testing.T) {
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+
+	account1 := createRandomAccount(user1.Username)
+	account2 := createRandomAccount(user2.Username)
+	account2.ID = account1.ID + 1
+	account1.Currency = util.USD
+	account2.Currency = util.USD
+
+	startAt := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+
+	testCases := []struct {
+		name           string
+		req            *pb.CreateScheduledTransferRequest
+		user           db.User
+		buildStubs     func(store *mockdb.MockStore)
+		checkResponses func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error)
+	}{
+		{
+			name: "Monthly",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        100,
+				Currency:      util.USD,
+				Rule:          util.ScheduleMonthly,
+				StartTime:     timestamppb.New(startAt),
+				EndTime:       timestamppb.New(startAt.AddDate(1, 0, 0)),
+			},
+			user: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				arg := db.CreateScheduledTransferParams{
+					Owner:         user1.Username,
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        100,
+					Rule:          util.ScheduleMonthly,
+					StartAt:       startAt,
+					EndAt:         pgtype.Timestamptz{Time: startAt.AddDate(1, 0, 0), Valid: true},
+					NextRunAt:     pgtype.Timestamptz{Time: startAt, Valid: true},
+					CreatedBy:     user1.Username,
+				}
+				schedule := db.ScheduledTransfer{
+					ID:            1,
+					Owner:         arg.Owner,
+					FromAccountID: arg.FromAccountID,
+					ToAccountID:   arg.ToAccountID,
+					Amount:        arg.Amount,
+					Rule:          arg.Rule,
+					StartAt:       arg.StartAt,
+					EndAt:         arg.EndAt,
+					NextRunAt:     arg.NextRunAt,
+					Status:        db.ScheduledTransferStatusActive,
+					CreatedBy:     arg.CreatedBy,
+				}
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).Times(1).Return(schedule, nil)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+				schedule := res.GetScheduledTransfer()
+				require.Equal(t, util.ScheduleMonthly, schedule.GetRule())
+				require.Equal(t, db.ScheduledTransferStatusActive, schedule.GetStatus())
+				require.True(t, startAt.Equal(schedule.GetNextRunTime().AsTime()))
+			},
+		},
+		{
+			name: "CoOwner",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        100,
+				Currency:      util.USD,
+				Rule:          util.ScheduleOnce,
+				StartTime:     timestamppb.New(startAt),
+			},
+			user: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				coOwner := db.AccountMember{AccountID: account1.ID, Username: user2.Username, Role: util.AccountMemberCoOwner}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user2.Username})).Times(1).Return(coOwner, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				arg := db.CreateScheduledTransferParams{
+					Owner:         user1.Username,
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        100,
+					Rule:          util.ScheduleOnce,
+					StartAt:       startAt,
+					NextRunAt:     pgtype.Timestamptz{Time: startAt, Valid: true},
+					CreatedBy:     user2.Username,
+				}
+				schedule := db.ScheduledTransfer{
+					ID:            2,
+					Owner:         arg.Owner,
+					FromAccountID: arg.FromAccountID,
+					ToAccountID:   arg.ToAccountID,
+					Amount:        arg.Amount,
+					Rule:          arg.Rule,
+					StartAt:       arg.StartAt,
+					NextRunAt:     arg.NextRunAt,
+					Status:        db.ScheduledTransferStatusActive,
+					CreatedBy:     arg.CreatedBy,
+				}
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).Times(1).Return(schedule, nil)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user1.Username, res.GetScheduledTransfer().GetOwner())
+				require.Equal(t, user2.Username, res.GetScheduledTransfer().GetCreatedBy())
+			},
+		},
+		{
+			name: "CronWithoutExpression",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        100,
+				Currency:      util.USD,
+				Rule:          util.ScheduleCron,
+				StartTime:     timestamppb.New(startAt),
+			},
+			user: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "StartInThePast",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        100,
+				Currency:      util.USD,
+				Rule:          util.ScheduleOnce,
+				StartTime:     timestamppb.New(time.Now().Add(-time.Hour)),
+			},
+			user: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "PermissionDenied",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        100,
+				Currency:      util.USD,
+				Rule:          util.ScheduleWeekly,
+				StartTime:     timestamppb.New(startAt),
+			},
+			user: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user2.Username})).Times(1).Return(accountMembership(account1, user2.Username))
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := newContextWithBearerToken(t, server.tokenMaker, testCase.user.Username, testCase.user.Role, time.Minute)
+			res, err := server.CreateScheduledTransfer(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
+
+func TestRpcCreateScheduledTransferAboveApprovalThreshold(t *testing.T) {
+	launched := util.Currencies()
+	defer util.SetCurrencies(launched)
+
+	threshold := int64(1_000)
+	util.SetCurrencies([]util.Currency{
+		{Code: util.USD, NumericCode: "840", MinorUnits: 2, Enabled: true, ApprovalThreshold: threshold},
+	})
+
+	user, _ := createRandomUser(t)
+	account := createRandomAccount(user.Username)
+	account.Currency = util.USD
+
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+
+	store := mockdb.NewMockStore(storeCtrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
+	store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store, nil)
+
+	ctx := newContextWithBearerToken(t, server.tokenMaker, user.Username, user.Role, time.Minute)
+	_, err := server.CreateScheduledTransfer(ctx, &pb.CreateScheduledTransferRequest{
+		FromAccountId: account.ID,
+		ToAccountId:   account.ID + 1,
+		Amount:        threshold + 1,
+		Currency:      util.USD,
+		Rule:          util.ScheduleOnce,
+		StartTime:     timestamppb.New(time.Now().Add(time.Minute)),
+	})
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+}
@@ -0,0 +1,195 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/billy-le/simple-bank/worker"
+	"github.com/hibiken/asynq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	mtdt := server.extractMetaData(ctx)
+
+	violations := validateTransferRequest(req)
+	if mtdt.IdempotencyKey != "" {
+		if err := val.ValidateIdempotencyKey(mtdt.IdempotencyKey); err != nil {
+			violations = append(violations, fieldViolation(idempotencyKeyHeader, err))
+		}
+	}
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := server.authorizeAccount(ctx, authPayload, fromAccount, accountSenders)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.CheckMemberTransfer(member, req.GetAmount())
+	if err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "%s", err)
+	}
+
+	toCurrency := req.GetToCurrency()
+	if toCurrency == "" {
+		toCurrency = req.GetCurrency()
+	}
+
+	_, err = server.validAccount(ctx, req.GetToAccountId(), toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID:  req.GetFromAccountId(),
+		ToAccountID:    req.GetToAccountId(),
+		Amount:         req.GetAmount(),
+		CrossCurrency:  toCurrency != req.GetCurrency(),
+		Username:       authPayload.Username,
+		IdempotencyKey: mtdt.IdempotencyKey,
+	}
+
+	if util.RequiresApproval(fromAccount.Currency, arg.Amount) {
+		return server.requestTransfer(ctx, arg)
+	}
+
+	txResult, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) ||
+			errors.Is(err, db.ErrExchangeRateNotFound) ||
+			errors.Is(err, db.ErrConvertedAmountTooSmall) ||
+			errors.Is(err, db.ErrAccountFrozen) ||
+			errors.Is(err, db.ErrAccountDormant) ||
+			errors.Is(err, db.ErrAccountClosed) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s", err)
+		}
+		var limitErr *db.TransferLimitError
+		if errors.As(err, &limitErr) {
+			return nil, transferLimitError(limitErr)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create transfer: %s", err)
+	}
+
+	rsp := &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(txResult.Transfer),
+		FromAccount: convertAccount(txResult.FromAccount),
+		ToAccount:   convertAccount(txResult.ToAccount),
+		FromEntry:   convertEntry(txResult.FromEntry),
+		ToEntry:     convertEntry(txResult.ToEntry),
+		Fees:        convertTransfers(txResult.Fees),
+	}
+
+	return rsp, nil
+}
+
+// requestTransfer holds a transfer back until a banker other than the user
+// approves it, and schedules the request to expire
+func (server *Server) requestTransfer(ctx context.Context, arg db.TransferTxParams) (*pb.CreateTransferResponse, error) {
+	request, err := server.store.CreateTransferRequestTx(ctx, db.CreateTransferRequestTxParams{
+		TransferTxParams: arg,
+		ExpiresAt:        time.Now().Add(server.config.TransferRequestDuration),
+		AfterCreate: func(request db.TransferRequest) error {
+			taskPayload := &worker.PayloadExpireTransferRequest{
+				TransferRequestID: request.ID,
+			}
+
+			opts := []asynq.Option{
+				asynq.MaxRetry(10),
+				asynq.ProcessAt(request.ExpiresAt),
+				asynq.Queue(worker.QueueCritical),
+			}
+
+			return server.taskDistributor.DistributeTaskExpireTransferRequest(ctx, taskPayload, opts...)
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create transfer request: %s", err)
+	}
+
+	rsp := &pb.CreateTransferResponse{
+		TransferRequest: convertTransferRequest(request),
+	}
+
+	return rsp, nil
+}
+
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found: %s", accountID, err)
+		}
+		return account, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	if account.Currency != currency {
+		return account, status.Errorf(codes.InvalidArgument, "account [%d] currency mismatch: [%s] vs [%s]", accountID, account.Currency, currency)
+	}
+
+	return account, nil
+}
+
+// transferRequest is what creating and quoting a transfer both ask for
+type transferRequest interface {
+	GetFromAccountId() int64
+	GetToAccountId() int64
+	GetAmount() int64
+	GetCurrency() string
+	GetToCurrency() string
+}
+
+func validateTransferRequest(req transferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := val.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("must be different from from_account_id")))
+	}
+
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if req.GetToCurrency() != "" {
+		if err := val.ValidateCurrency(req.GetToCurrency()); err != nil {
+			violations = append(violations, fieldViolation("to_currency", err))
+		}
+	}
+
+	return violations
+}
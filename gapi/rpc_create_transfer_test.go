@@ -0,0 +1,568 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/worker"
+	mockwk "github.com/billy-le/simple-bank/worker/mock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcCreateTransfer(t *testing.T) {
+	amount := int64(10)
+
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+	user3, _ := createRandomUser(t)
+
+	account1 := createRandomAccount(user1.Username)
+	account2 := createRandomAccount(user2.Username)
+	account3 := createRandomAccount(user3.Username)
+	account2.ID = account1.ID + 1
+	account3.ID = account1.ID + 2
+
+	account1.Currency = util.USD
+	account2.Currency = util.USD
+	account3.Currency = util.EUR
+
+	idempotencyKey := util.RandomString(16)
+
+	testCases := []struct {
+		name           string
+		req            *pb.CreateTransferRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.CreateTransferResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        amount,
+					Username:      user1.Username,
+				}
+				result := db.TransferTxResult{
+					Transfer:    db.Transfer{ID: 1, FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: amount},
+					FromAccount: account1,
+					ToAccount:   account2,
+					FromEntry:   db.Entry{ID: 1, AccountID: account1.ID, Amount: -amount},
+					ToEntry:     db.Entry{ID: 2, AccountID: account2.ID, Amount: amount},
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, amount, res.GetTransfer().Amount)
+				require.Equal(t, account1.ID, res.GetFromAccount().Id)
+				require.Equal(t, account2.ID, res.GetToAccount().Id)
+				require.Equal(t, -amount, res.GetFromEntry().Amount)
+				require.Equal(t, amount, res.GetToEntry().Amount)
+			},
+		},
+		{
+			name: "BankerOverride",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user3.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user2.Username})).Times(1).Return(accountMembership(account1, user2.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user2.Username, user2.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.PermissionDenied)
+			},
+		},
+		{
+			name: "FromAccountNotFound",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.NotFound)
+			},
+		},
+		{
+			name: "ToAccountCurrencyMismatch",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account3.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.InvalidArgument)
+			},
+		},
+		{
+			name: "CrossCurrency",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account3.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+				ToCurrency:    util.EUR,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account3.ID,
+					Amount:        amount,
+					CrossCurrency: true,
+					Username:      user1.Username,
+				}
+				var rate pgtype.Numeric
+				require.NoError(t, rate.Scan("0.92"))
+				result := db.TransferTxResult{
+					Transfer: db.Transfer{
+						ID:            1,
+						FromAccountID: account1.ID,
+						ToAccountID:   account3.ID,
+						Amount:        amount,
+						ToAmount:      9,
+						ExchangeRate:  rate,
+						SpreadBps:     pgtype.Int4{Int32: 0, Valid: true},
+					},
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, amount, res.GetTransfer().GetAmount())
+				require.Equal(t, int64(9), res.GetTransfer().GetToAmount())
+				require.Equal(t, "0.92", res.GetTransfer().GetExchangeRate())
+			},
+		},
+		{
+			name: "ExchangeRateNotFound",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account3.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+				ToCurrency:    util.EUR,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account3.ID)).Times(1).Return(account3, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrExchangeRateNotFound)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.FailedPrecondition)
+			},
+		},
+		{
+			name: "NegativeAmount",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        -amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.InvalidArgument)
+			},
+		},
+		{
+			name: "SameAccount",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account1.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.InvalidArgument)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.FailedPrecondition)
+			},
+		},
+		{
+			name: "LimitExceeded",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				limitErr := &db.TransferLimitError{
+					AccountID: account1.ID,
+					Owner:     user1.Username,
+					Usage: db.TransferUsage{
+						Scope:    util.LimitScopeAccount,
+						Period:   util.LimitPeriodDaily,
+						Currency: util.USD,
+						Limit: db.TransferLimit{
+							MaxAmount: pgtype.Int8{Int64: amount + 5, Valid: true},
+						},
+						Amount:   10,
+						Count:    1,
+						ResetsAt: time.Now().Add(time.Hour),
+					},
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, limitErr)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.ResourceExhausted, st.Code())
+
+				var usage *pb.TransferUsage
+				var quotaFailure *errdetails.QuotaFailure
+				for _, detail := range st.Details() {
+					switch detail := detail.(type) {
+					case *pb.TransferUsage:
+						usage = detail
+					case *errdetails.QuotaFailure:
+						quotaFailure = detail
+					}
+				}
+				require.NotNil(t, usage)
+				require.Equal(t, amount-5, usage.GetRemainingAmount())
+				require.Nil(t, usage.RemainingCount)
+				require.NotNil(t, quotaFailure)
+				require.Equal(t, fmt.Sprintf("account:%d", account1.ID), quotaFailure.GetViolations()[0].GetSubject())
+			},
+		},
+		{
+			name: "IdempotencyKey",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID:  account1.ID,
+					ToAccountID:    account2.ID,
+					Amount:         amount,
+					Username:       user1.Username,
+					IdempotencyKey: idempotencyKey,
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx := newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+				return newContextWithIdempotencyKey(ctx, idempotencyKey)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+			},
+		},
+		{
+			name: "IdempotencyKeyConflict",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrIdempotencyKeyConflict)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx := newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+				return newContextWithIdempotencyKey(ctx, idempotencyKey)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.AlreadyExists)
+			},
+		},
+		{
+			name: "TransferTxError",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrTxClosed)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user1.Username, user1.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.Error(t, err)
+				require.Nil(t, res)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, st.Code(), codes.Internal)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.CreateTransfer(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
+
+func TestRpcCreateTransferNeedsApproval(t *testing.T) {
+	launched := util.Currencies()
+	defer util.SetCurrencies(launched)
+
+	threshold := int64(1_000)
+	util.SetCurrencies([]util.Currency{
+		{Code: util.USD, NumericCode: "840", MinorUnits: 2, Enabled: true, ApprovalThreshold: threshold},
+	})
+
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+
+	account1 := createRandomAccount(user1.Username)
+	account2 := createRandomAccount(user2.Username)
+	account2.ID = account1.ID + 1
+	account1.Currency = util.USD
+	account2.Currency = util.USD
+
+	request := db.TransferRequest{
+		ID:            1,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        threshold + 1,
+		Status:        db.TransferRequestStatusPending,
+		RequestedBy:   user1.Username,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+
+	storeCtrl := gomock.NewController(t)
+	taskCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+	defer taskCtrl.Finish()
+
+	store := mockdb.NewMockStore(storeCtrl)
+	taskDistributor := mockwk.NewMockTaskDistributor(taskCtrl)
+
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account1.ID, Username: user1.Username})).Times(1).Return(accountMembership(account1, user1.Username))
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		CreateTransferRequestTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateTransferRequestTxParams) (db.TransferRequest, error) {
+			require.Equal(t, account1.ID, arg.FromAccountID)
+			require.Equal(t, account2.ID, arg.ToAccountID)
+			require.Equal(t, threshold+1, arg.Amount)
+			require.Equal(t, user1.Username, arg.Username)
+			require.WithinDuration(t, request.ExpiresAt, arg.ExpiresAt, time.Second)
+
+			err := arg.AfterCreate(request)
+			return request, err
+		})
+
+	taskPayload := &worker.PayloadExpireTransferRequest{
+		TransferRequestID: request.ID,
+	}
+	taskDistributor.EXPECT().DistributeTaskExpireTransferRequest(gomock.Any(), taskPayload, gomock.Any()).Times(1).Return(nil)
+
+	server := newTestServer(t, store, taskDistributor)
+
+	ctx := newContextWithBearerToken(t, server.tokenMaker, user1.Username, user1.Role, time.Minute)
+	res, err := server.CreateTransfer(ctx, &pb.CreateTransferRequest{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        threshold + 1,
+		Currency:      util.USD,
+	})
+	require.NoError(t, err)
+	require.Nil(t, res.GetTransfer())
+	require.Equal(t, request.ID, res.GetTransferRequest().GetId())
+	require.Equal(t, db.TransferRequestStatusPending, res.GetTransferRequest().GetStatus())
+}
+
+func newContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = metadata.Join(md, metadata.Pairs(idempotencyKeyHeader, key))
+	return metadata.NewIncomingContext(ctx, md)
+}
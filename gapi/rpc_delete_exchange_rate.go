@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) DeleteExchangeRate(ctx context.Context, req *pb.DeleteExchangeRateRequest) (*pb.DeleteExchangeRateResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateDeleteExchangeRateRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	rows, err := server.store.DeleteExchangeRate(ctx, db.DeleteExchangeRateParams{
+		FromCurrency: req.GetFromCurrency(),
+		ToCurrency:   req.GetToCurrency(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete exchange rate: %s", err)
+	}
+
+	if rows == 0 {
+		return nil, status.Errorf(codes.NotFound, "no exchange rate from %s to %s", req.GetFromCurrency(), req.GetToCurrency())
+	}
+
+	rsp := &pb.DeleteExchangeRateResponse{
+		IsDeleted: true,
+	}
+
+	return rsp, nil
+}
+
+func validateDeleteExchangeRateRequest(req *pb.DeleteExchangeRateRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateCurrency(req.GetFromCurrency()); err != nil {
+		violations = append(violations, fieldViolation("from_currency", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetToCurrency()); err != nil {
+		violations = append(violations, fieldViolation("to_currency", err))
+	}
+
+	return violations
+}
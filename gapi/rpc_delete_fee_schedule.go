@@ -0,0 +1,65 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) DeleteFeeSchedule(ctx context.Context, req *pb.DeleteFeeScheduleRequest) (*pb.DeleteFeeScheduleResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateDeleteFeeScheduleRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	rows, err := server.store.DeleteFeeSchedule(ctx, db.DeleteFeeScheduleParams{
+		TransferType: req.GetTransferType(),
+		Currency:     req.GetCurrency(),
+		Role:         req.GetRole(),
+		MinAmount:    req.GetMinAmount(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete fee schedule: %s", err)
+	}
+
+	if rows == 0 {
+		return nil, status.Errorf(codes.NotFound, "no %s fee schedule for %s %s from %d", req.GetTransferType(), req.GetRole(), req.GetCurrency(), req.GetMinAmount())
+	}
+
+	rsp := &pb.DeleteFeeScheduleResponse{
+		IsDeleted: true,
+	}
+
+	return rsp, nil
+}
+
+func validateDeleteFeeScheduleRequest(req *pb.DeleteFeeScheduleRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateTransferType(req.GetTransferType()); err != nil {
+		violations = append(violations, fieldViolation("transfer_type", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if err := val.ValidateRole(req.GetRole()); err != nil {
+		violations = append(violations, fieldViolation("role", err))
+	}
+
+	if err := val.ValidateFeeAmount(req.GetMinAmount()); err != nil {
+		violations = append(violations, fieldViolation("min_amount", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,47 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) DeleteTransferLimit(ctx context.Context, req *pb.DeleteTransferLimitRequest) (*pb.DeleteTransferLimitResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateDeleteTransferLimitRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	rows, err := server.store.DeleteTransferLimit(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete transfer limit: %s", err)
+	}
+
+	if rows == 0 {
+		return nil, status.Errorf(codes.NotFound, "transfer limit [%d] not found", req.GetId())
+	}
+
+	rsp := &pb.DeleteTransferLimitResponse{
+		IsDeleted: true,
+	}
+
+	return rsp, nil
+}
+
+func validateDeleteTransferLimitRequest(req *pb.DeleteTransferLimitRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+
+	return violations
+}
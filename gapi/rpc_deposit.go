@@ -0,0 +1,110 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) Deposit(ctx context.Context, req *pb.DepositRequest) (*pb.DepositResponse, error) {
+	txResult, err := server.moveCash(ctx, req.GetAccountId(), req.GetAmount(), req.GetCurrency(), server.store.DepositTx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.DepositResponse{
+		Transfer: convertTransfer(txResult.Transfer),
+		Account:  convertAccount(txResult.Account),
+		Entry:    convertEntry(txResult.Entry),
+		Fees:     convertTransfers(txResult.Fees),
+	}
+
+	return rsp, nil
+}
+
+// moveCash authorizes, validates and runs a deposit or withdrawal, which
+// differ only in the store transaction that moves the money
+func (server *Server) moveCash(
+	ctx context.Context,
+	accountID int64,
+	amount int64,
+	currency string,
+	cashTx func(ctx context.Context, arg db.CashTxParams) (db.CashTxResult, error),
+) (db.CashTxResult, error) {
+	var result db.CashTxResult
+
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return result, unauthenticatedError(err)
+	}
+
+	mtdt := server.extractMetaData(ctx)
+
+	violations := validateCashRequest(accountID, amount, currency)
+	if mtdt.IdempotencyKey != "" {
+		if err := val.ValidateIdempotencyKey(mtdt.IdempotencyKey); err != nil {
+			violations = append(violations, fieldViolation(idempotencyKeyHeader, err))
+		}
+	}
+	if violations != nil {
+		return result, invalidArgumentError(violations)
+	}
+
+	account, err := server.validAccount(ctx, accountID, currency)
+	if err != nil {
+		return result, err
+	}
+
+	if account.Owner == db.SystemAccountOwner {
+		return result, status.Errorf(codes.InvalidArgument, "cannot move cash in or out of one of the bank's own accounts")
+	}
+
+	result, err = cashTx(ctx, db.CashTxParams{
+		AccountID:      account.ID,
+		Amount:         amount,
+		Username:       authPayload.Username,
+		IdempotencyKey: mtdt.IdempotencyKey,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) ||
+			errors.Is(err, db.ErrSystemAccountNotFound) ||
+			errors.Is(err, db.ErrAccountFrozen) ||
+			errors.Is(err, db.ErrAccountDormant) ||
+			errors.Is(err, db.ErrAccountClosed) {
+			return result, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			return result, status.Errorf(codes.AlreadyExists, "%s", err)
+		}
+		var limitErr *db.TransferLimitError
+		if errors.As(err, &limitErr) {
+			return result, transferLimitError(limitErr)
+		}
+		return result, status.Errorf(codes.Internal, "failed to move cash: %s", err)
+	}
+
+	return result, nil
+}
+
+func validateCashRequest(accountID int64, amount int64, currency string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(accountID); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := val.ValidateAmount(amount); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateCurrency(currency); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,199 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcDeposit(t *testing.T) {
+	amount := int64(100)
+
+	user, _ := createRandomUser(t)
+	banker, _ := createRandomUser(t)
+	banker.Role = util.BankerRole
+
+	account := createRandomAccount(user.Username)
+	account.Currency = util.USD
+
+	cashAccount := createRandomAccount(db.SystemAccountOwner)
+	cashAccount.Currency = util.USD
+
+	testCases := []struct {
+		name           string
+		req            *pb.DepositRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.DepositResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.DepositRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.CashTxParams{
+					AccountID: account.ID,
+					Amount:    amount,
+					Username:  banker.Username,
+				}
+				result := db.CashTxResult{
+					Transfer:    db.Transfer{ID: 1, FromAccountID: cashAccount.ID, ToAccountID: account.ID, Amount: amount},
+					Account:     account,
+					Entry:       db.Entry{ID: 2, AccountID: account.ID, Amount: amount},
+					CashAccount: cashAccount,
+					CashEntry:   db.Entry{ID: 1, AccountID: cashAccount.ID, Amount: -amount},
+				}
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, amount, res.GetTransfer().Amount)
+				require.Equal(t, account.ID, res.GetAccount().Id)
+				require.Equal(t, amount, res.GetEntry().Amount)
+			},
+		},
+		{
+			name: "DepositorNotBanker",
+			req: &pb.DepositRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "InvalidAmount",
+			req: &pb.DepositRequest{
+				AccountId: account.ID,
+				Amount:    -amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			req: &pb.DepositRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  util.EUR,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "CashAccountTarget",
+			req: &pb.DepositRequest{
+				AccountId: cashAccount.ID,
+				Amount:    amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(cashAccount.ID)).Times(1).Return(cashAccount, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "IdempotencyKeyConflict",
+			req: &pb.DepositRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, db.ErrIdempotencyKeyConflict)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				ctx := newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+				return newContextWithIdempotencyKey(ctx, util.RandomString(16))
+			},
+			checkResponses: func(t *testing.T, res *pb.DepositResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.AlreadyExists, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.Deposit(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package gapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/export"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// statementExportBatchSize is how many entries are read from the db at a time
+	statementExportBatchSize = 500
+	// statementExportChunkSize is the most bytes sent in one stream message
+	statementExportChunkSize = 32 * 1024
+)
+
+var statementFormats = map[pb.StatementFormat]export.Format{
+	pb.StatementFormat_STATEMENT_FORMAT_CSV:     export.FormatCSV,
+	pb.StatementFormat_STATEMENT_FORMAT_OFX:     export.FormatOFX,
+	pb.StatementFormat_STATEMENT_FORMAT_CAMT053: export.FormatCAMT053,
+}
+
+func (server *Server) ExportStatement(req *pb.ExportStatementRequest, stream pb.SimpleBank_ExportStatementServer) error {
+	format, statement, err := server.prepareStatementExport(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, statement); err != nil {
+		return status.Errorf(codes.Internal, "failed to export statement: %s", err)
+	}
+
+	rsp := &pb.ExportStatementResponse{
+		ContentType: format.ContentType(),
+		FileName:    export.FileName(format, statement),
+	}
+	for {
+		rsp.Data = buf.Next(statementExportChunkSize)
+		if err := stream.Send(rsp); err != nil {
+			return err
+		}
+
+		if buf.Len() == 0 {
+			return nil
+		}
+		rsp = &pb.ExportStatementResponse{}
+	}
+}
+
+// prepareStatementExport authorizes and validates an export request and loads
+// the whole statement, for both the gRPC stream and the gateway download.
+func (server *Server) prepareStatementExport(ctx context.Context, req *pb.ExportStatementRequest) (export.Format, export.Statement, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return "", export.Statement{}, unauthenticatedError(err)
+	}
+
+	violations := validateExportStatementRequest(req)
+	if violations != nil {
+		return "", export.Statement{}, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return "", export.Statement{}, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return "", export.Statement{}, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, account, accountViewers)
+	if err != nil {
+		return "", export.Statement{}, err
+	}
+
+	statement, err := server.loadStatement(ctx, account, req.GetStartTime().AsTime(), req.GetEndTime().AsTime())
+	if err != nil {
+		return "", export.Statement{}, err
+	}
+
+	return statementFormats[req.GetFormat()], statement, nil
+}
+
+func (server *Server) loadStatement(ctx context.Context, account db.Account, startTime time.Time, endTime time.Time) (export.Statement, error) {
+	balances, err := server.store.GetStatementBalances(ctx, db.GetStatementBalancesParams{
+		AccountID: account.ID,
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		return export.Statement{}, status.Errorf(codes.Internal, "failed to get statement balances: %s", err)
+	}
+
+	statement := export.Statement{
+		AccountID:      account.ID,
+		AccountType:    account.Type,
+		Owner:          account.Owner,
+		Currency:       account.Currency,
+		MinorUnits:     util.MinorUnits(account.Currency),
+		StartTime:      startTime,
+		EndTime:        endTime,
+		OpeningBalance: balances.OpeningBalance,
+		CreatedAt:      time.Now(),
+	}
+
+	// the closing balance is worked out from the entries read, so an entry
+	// posted while they are paged through can't leave the running balance
+	// short of it
+	balance := balances.OpeningBalance
+	var cursor util.PageCursor
+	for {
+		entries, err := server.store.ListStatementEntries(ctx, db.ListStatementEntriesParams{
+			AccountID:      account.ID,
+			StartTime:      startTime,
+			EndTime:        endTime,
+			AfterCreatedAt: cursor.CreatedAt,
+			AfterID:        cursor.ID,
+			PageSize:       statementExportBatchSize,
+		})
+		if err != nil {
+			return export.Statement{}, status.Errorf(codes.Internal, "failed to list statement entries: %s", err)
+		}
+
+		for _, entry := range entries {
+			balance += entry.Amount
+
+			line := export.Line{
+				EntryID:   entry.ID,
+				Amount:    entry.Amount,
+				Balance:   balance,
+				CreatedAt: entry.CreatedAt,
+			}
+			if entry.TransferID.Valid {
+				line.TransferID = entry.TransferID.Int64
+				line.CounterpartyAccountID, line.CounterpartyOwner = statementCounterparty(entry)
+			}
+
+			statement.Lines = append(statement.Lines, line)
+			cursor = util.PageCursor{CreatedAt: entry.CreatedAt, ID: entry.ID}
+		}
+
+		if len(entries) < statementExportBatchSize {
+			statement.ClosingBalance = balance
+			return statement, nil
+		}
+	}
+}
+
+func validateExportStatementRequest(req *pb.ExportStatementRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if req.GetStartTime() == nil {
+		violations = append(violations, fieldViolation("start_time", fmt.Errorf("is required")))
+	}
+
+	if req.GetEndTime() == nil {
+		violations = append(violations, fieldViolation("end_time", fmt.Errorf("is required")))
+	} else if req.GetStartTime() != nil && !req.GetEndTime().AsTime().After(req.GetStartTime().AsTime()) {
+		violations = append(violations, fieldViolation("end_time", fmt.Errorf("must be after start_time")))
+	}
+
+	if _, ok := statementFormats[req.GetFormat()]; !ok {
+		violations = append(violations, fieldViolation("format", fmt.Errorf("is not a supported statement format")))
+	}
+
+	return violations
+}
@@ -0,0 +1,142 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) GetAccountStatement(ctx context.Context, req *pb.GetAccountStatementRequest) (*pb.GetAccountStatementResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetAccountStatementRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	cursor, err := server.pageTokenMaker.Decode(req.GetPageToken())
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, account, accountViewers)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := req.GetStartTime().AsTime()
+	endTime := req.GetEndTime().AsTime()
+
+	balances, err := server.store.GetStatementBalances(ctx, db.GetStatementBalancesParams{
+		AccountID:      account.ID,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterID:        cursor.ID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get statement balances: %s", err)
+	}
+
+	// fetch one extra row to know whether there is a next page
+	entries, err := server.store.ListStatementEntries(ctx, db.ListStatementEntriesParams{
+		AccountID:      account.ID,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterID:        cursor.ID,
+		PageSize:       req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list statement entries: %s", err)
+	}
+
+	rsp := &pb.GetAccountStatementResponse{
+		Account:        convertAccount(account),
+		OpeningBalance: balances.OpeningBalance,
+		ClosingBalance: balances.ClosingBalance,
+	}
+
+	if len(entries) > int(req.GetPageSize()) {
+		entries = entries[:req.GetPageSize()]
+		last := entries[len(entries)-1]
+		rsp.NextPageToken = server.pageTokenMaker.Encode(util.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	balance := balances.PageOpeningBalance
+	rsp.Lines = make([]*pb.StatementLine, len(entries))
+	for i, entry := range entries {
+		balance += entry.Amount
+		rsp.Lines[i] = convertStatementLine(entry, balance)
+	}
+
+	return rsp, nil
+}
+
+func convertStatementLine(entry db.ListStatementEntriesRow, balance int64) *pb.StatementLine {
+	line := &pb.StatementLine{
+		EntryId:   entry.ID,
+		Amount:    entry.Amount,
+		Balance:   balance,
+		CreatedAt: timestamppb.New(entry.CreatedAt),
+	}
+
+	if entry.TransferID.Valid {
+		counterpartyAccountID, counterpartyOwner := statementCounterparty(entry)
+		line.TransferId = &entry.TransferID.Int64
+		line.CounterpartyAccountId = &counterpartyAccountID
+		line.CounterpartyOwner = &counterpartyOwner
+	}
+
+	return line
+}
+
+// statementCounterparty returns the other account of the transfer an entry belongs to
+func statementCounterparty(entry db.ListStatementEntriesRow) (int64, string) {
+	if entry.FromAccountID.Int64 == entry.AccountID {
+		return entry.ToAccountID.Int64, entry.ToAccountOwner.String
+	}
+	return entry.FromAccountID.Int64, entry.FromAccountOwner.String
+}
+
+func validateGetAccountStatementRequest(req *pb.GetAccountStatementRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if req.GetStartTime() == nil {
+		violations = append(violations, fieldViolation("start_time", fmt.Errorf("is required")))
+	}
+
+	if req.GetEndTime() == nil {
+		violations = append(violations, fieldViolation("end_time", fmt.Errorf("is required")))
+	} else if req.GetStartTime() != nil && !req.GetEndTime().AsTime().After(req.GetStartTime().AsTime()) {
+		violations = append(violations, fieldViolation("end_time", fmt.Errorf("must be after start_time")))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,249 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRpcGetAccountStatement(t *testing.T) {
+	user, _ := createRandomUser(t)
+	otherUser, _ := createRandomUser(t)
+
+	account := createRandomAccount(user.Username)
+	otherAccount := createRandomAccount(otherUser.Username)
+
+	endTime := time.Now()
+	startTime := endTime.Add(-24 * time.Hour)
+	pageSize := int32(5)
+
+	entries := make([]db.ListStatementEntriesRow, pageSize+1)
+	for i := range entries {
+		entries[i] = db.ListStatementEntriesRow{
+			ID:               int64(i + 1),
+			AccountID:        account.ID,
+			Amount:           -10,
+			CreatedAt:        startTime.Add(time.Duration(i) * time.Minute),
+			TransferID:       pgtype.Int8{Int64: int64(i + 1), Valid: true},
+			FromAccountID:    pgtype.Int8{Int64: account.ID, Valid: true},
+			ToAccountID:      pgtype.Int8{Int64: otherAccount.ID, Valid: true},
+			FromAccountOwner: pgtype.Text{String: account.Owner, Valid: true},
+			ToAccountOwner:   pgtype.Text{String: otherAccount.Owner, Valid: true},
+		}
+	}
+
+	balances := db.GetStatementBalancesRow{
+		OpeningBalance:     100,
+		ClosingBalance:     40,
+		PageOpeningBalance: 100,
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.GetAccountStatementRequest
+		setupRequest   func(server *Server, req *pb.GetAccountStatementRequest)
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, server *Server, res *pb.GetAccountStatementResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.GetAccountStatementRequest{
+				AccountId: account.ID,
+				StartTime: timestamppb.New(startTime),
+				EndTime:   timestamppb.New(endTime),
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
+				store.EXPECT().GetStatementBalances(gomock.Any(), gomock.Any()).Times(1).Return(balances, nil)
+				store.EXPECT().
+					ListStatementEntries(gomock.Any(), gomock.Eq(db.ListStatementEntriesParams{
+						AccountID: account.ID,
+						StartTime: startTime.UTC(),
+						EndTime:   endTime.UTC(),
+						PageSize:  pageSize + 1,
+					})).
+					Times(1).
+					Return(entries, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.GetAccountStatementResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, balances.OpeningBalance, res.GetOpeningBalance())
+				require.Equal(t, balances.ClosingBalance, res.GetClosingBalance())
+				require.Len(t, res.GetLines(), int(pageSize))
+
+				for i, line := range res.GetLines() {
+					require.Equal(t, balances.PageOpeningBalance-int64(i+1)*10, line.GetBalance())
+					require.Equal(t, otherAccount.ID, line.GetCounterpartyAccountId())
+					require.Equal(t, otherAccount.Owner, line.GetCounterpartyOwner())
+				}
+
+				cursor, err := server.pageTokenMaker.Decode(res.GetNextPageToken())
+				require.NoError(t, err)
+				require.Equal(t, entries[pageSize-1].ID, cursor.ID)
+				require.WithinDuration(t, entries[pageSize-1].CreatedAt, cursor.CreatedAt, time.Microsecond)
+			},
+		},
+		{
+			name: "NextPage",
+			req: &pb.GetAccountStatementRequest{
+				AccountId: account.ID,
+				StartTime: timestamppb.New(startTime),
+				EndTime:   timestamppb.New(endTime),
+				PageSize:  pageSize,
+			},
+			setupRequest: func(server *Server, req *pb.GetAccountStatementRequest) {
+				last := entries[pageSize-1]
+				req.PageToken = server.pageTokenMaker.Encode(util.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
+				store.EXPECT().GetStatementBalances(gomock.Any(), gomock.Any()).Times(1).Return(balances, nil)
+				store.EXPECT().
+					ListStatementEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ListStatementEntriesParams) ([]db.ListStatementEntriesRow, error) {
+						require.Equal(t, entries[pageSize-1].ID, arg.AfterID)
+						return entries[pageSize:], nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.GetAccountStatementResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetLines(), 1)
+				require.Empty(t, res.GetNextPageToken())
+			},
+		},
+		{
+			name: "PermissionDenied",
+			req: &pb.GetAccountStatementRequest{
+				AccountId: otherAccount.ID,
+				StartTime: timestamppb.New(startTime),
+				EndTime:   timestamppb.New(endTime),
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: otherAccount.ID, Username: user.Username})).Times(1).Return(accountMembership(otherAccount, user.Username))
+				store.EXPECT().GetStatementBalances(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListStatementEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.GetAccountStatementResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "BankerCanView",
+			req: &pb.GetAccountStatementRequest{
+				AccountId: otherAccount.ID,
+				StartTime: timestamppb.New(startTime),
+				EndTime:   timestamppb.New(endTime),
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().GetStatementBalances(gomock.Any(), gomock.Any()).Times(1).Return(balances, nil)
+				store.EXPECT().ListStatementEntries(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListStatementEntriesRow{}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.GetAccountStatementResponse, err error) {
+				require.NoError(t, err)
+				require.Empty(t, res.GetLines())
+				require.Empty(t, res.GetNextPageToken())
+			},
+		},
+		{
+			name: "InvalidTimeRange",
+			req: &pb.GetAccountStatementRequest{
+				AccountId: account.ID,
+				StartTime: timestamppb.New(endTime),
+				EndTime:   timestamppb.New(startTime),
+				PageSize:  pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.GetAccountStatementResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidPageToken",
+			req: &pb.GetAccountStatementRequest{
+				AccountId: account.ID,
+				StartTime: timestamppb.New(startTime),
+				EndTime:   timestamppb.New(endTime),
+				PageSize:  pageSize,
+				PageToken: "not-a-token",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.GetAccountStatementResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			if testCase.setupRequest != nil {
+				testCase.setupRequest(server, testCase.req)
+			}
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.GetAccountStatement(ctx, testCase.req)
+
+			testCase.checkResponses(t, server, res, err)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetLimits(ctx context.Context, req *pb.GetLimitsRequest) (*pb.GetLimitsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateGetLimitsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, account, accountViewers)
+	if err != nil {
+		return nil, err
+	}
+
+	usages, err := db.GetTransferUsages(ctx, server.store, account, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get limits: %s", err)
+	}
+
+	rsp := &pb.GetLimitsResponse{
+		Usages: make([]*pb.TransferUsage, len(usages)),
+	}
+	for i, usage := range usages {
+		rsp.Usages[i] = convertTransferUsage(usage)
+	}
+
+	return rsp, nil
+}
+
+func validateGetLimitsRequest(req *pb.GetLimitsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,154 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcGetLimits(t *testing.T) {
+	user, _ := createRandomUser(t)
+	otherUser, _ := createRandomUser(t)
+	account := createRandomAccount(user.Username)
+
+	dailyAccountLimit := db.TransferLimit{
+		ID:        1,
+		AccountID: pgtype.Int8{Int64: account.ID, Valid: true},
+		Scope:     util.LimitScopeAccount,
+		Currency:  account.Currency,
+		Period:    util.LimitPeriodDaily,
+		MaxAmount: pgtype.Int8{Int64: 1000, Valid: true},
+		MaxCount:  pgtype.Int4{Int32: 5, Valid: true},
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.GetLimitsRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.GetLimitsResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req:  &pb.GetLimitsRequest{AccountId: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+
+				store.EXPECT().GetEffectiveTransferLimit(gomock.Any(), gomock.Any()).Times(4).
+					DoAndReturn(func(_ context.Context, arg db.GetEffectiveTransferLimitParams) (db.TransferLimit, error) {
+						require.Equal(t, user.Role, arg.Role)
+						if arg.Scope == util.LimitScopeAccount && arg.Period == util.LimitPeriodDaily {
+							return dailyAccountLimit, nil
+						}
+						return db.TransferLimit{}, db.ErrRecordNotFound
+					})
+				store.EXPECT().GetAccountTransferUsage(gomock.Any(), gomock.Any()).Times(2).
+					Return(db.GetAccountTransferUsageRow{Amount: 400, Count: 2}, nil)
+				store.EXPECT().GetUserTransferUsage(gomock.Any(), gomock.Any()).Times(2).
+					Return(db.GetUserTransferUsageRow{Amount: 900, Count: 3}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.GetLimitsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetUsages(), 4)
+
+				daily := res.GetUsages()[0]
+				require.Equal(t, util.LimitScopeAccount, daily.GetScope())
+				require.Equal(t, util.LimitPeriodDaily, daily.GetPeriod())
+				require.Equal(t, int64(400), daily.GetUsedAmount())
+				require.Equal(t, int64(600), daily.GetRemainingAmount())
+				require.Equal(t, int32(3), daily.GetRemainingCount())
+
+				for _, usage := range res.GetUsages()[1:] {
+					require.Nil(t, usage.MaxAmount)
+					require.Nil(t, usage.RemainingAmount)
+					require.Nil(t, usage.RemainingCount)
+				}
+				require.Equal(t, int64(900), res.GetUsages()[3].GetUsedAmount())
+			},
+		},
+		{
+			name: "PermissionDenied",
+			req:  &pb.GetLimitsRequest{AccountId: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: otherUser.Username})).Times(1).Return(accountMembership(account, otherUser.Username))
+				store.EXPECT().GetEffectiveTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, otherUser.Username, otherUser.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.GetLimitsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "AccountNotFound",
+			req:  &pb.GetLimitsRequest{AccountId: account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.GetLimitsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "InvalidAccountID",
+			req:  &pb.GetLimitsRequest{AccountId: 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.GetLimitsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.GetLimits(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetTrialBalance(ctx context.Context, req *pb.GetTrialBalanceRequest) (*pb.GetTrialBalanceResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	var asOf pgtype.Timestamptz
+	if req.AsOf != nil {
+		asOf = pgtype.Timestamptz{Time: req.GetAsOf().AsTime(), Valid: true}
+	}
+
+	balances, err := db.GetTrialBalance(ctx, server.store, asOf)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get trial balance: %s", err)
+	}
+
+	rsp := &pb.GetTrialBalanceResponse{
+		TrialBalances: make([]*pb.TrialBalance, len(balances)),
+		Balanced:      true,
+	}
+	for i, balance := range balances {
+		rsp.TrialBalances[i] = convertTrialBalance(balance)
+		rsp.Balanced = rsp.Balanced && balance.Balanced()
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,125 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRpcGetTrialBalance(t *testing.T) {
+	user, _ := createRandomUser(t)
+	banker, _ := createRandomUser(t)
+	banker.Role = util.BankerRole
+
+	asOf := time.Now().UTC().Truncate(time.Second)
+
+	balancedRows := []db.ListTrialBalanceRow{
+		{Currency: util.EUR, LedgerCode: db.LedgerCash, Name: "Cash", Type: "asset", Debits: 50},
+		{Currency: util.EUR, LedgerCode: db.LedgerCustomerDeposits, Name: "Customer deposits", Type: "liability", Debits: 20, Credits: 70},
+		{Currency: util.USD, LedgerCode: db.LedgerCash, Name: "Cash", Type: "asset", Debits: 100},
+		{Currency: util.USD, LedgerCode: db.LedgerCustomerDeposits, Name: "Customer deposits", Type: "liability", Credits: 100},
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.GetTrialBalanceRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.GetTrialBalanceResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req:  &pb.GetTrialBalanceRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTrialBalance(gomock.Any(), gomock.Eq(pgtype.Timestamptz{})).Times(1).Return(balancedRows, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.GetTrialBalanceResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetBalanced())
+				require.Len(t, res.GetTrialBalances(), 2)
+
+				eur := res.GetTrialBalances()[0]
+				require.Equal(t, util.EUR, eur.GetCurrency())
+				require.Len(t, eur.GetLines(), 2)
+				require.Equal(t, int64(70), eur.GetTotalDebits())
+				require.Equal(t, int64(70), eur.GetTotalCredits())
+				require.True(t, eur.GetBalanced())
+
+				usd := res.GetTrialBalances()[1]
+				require.Equal(t, util.USD, usd.GetCurrency())
+				require.Equal(t, db.LedgerCash, usd.GetLines()[0].GetLedgerCode())
+				require.Equal(t, int64(100), usd.GetTotalDebits())
+			},
+		},
+		{
+			name: "Unbalanced",
+			req:  &pb.GetTrialBalanceRequest{AsOf: timestamppb.New(asOf)},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := pgtype.Timestamptz{Time: asOf, Valid: true}
+				rows := []db.ListTrialBalanceRow{
+					{Currency: util.USD, LedgerCode: db.LedgerCash, Name: "Cash", Type: "asset", Debits: 100},
+					{Currency: util.USD, LedgerCode: db.LedgerCustomerDeposits, Name: "Customer deposits", Type: "liability", Credits: 90},
+				}
+				store.EXPECT().ListTrialBalance(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rows, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.GetTrialBalanceResponse, err error) {
+				require.NoError(t, err)
+				require.False(t, res.GetBalanced())
+				require.Len(t, res.GetTrialBalances(), 1)
+				require.False(t, res.GetTrialBalances()[0].GetBalanced())
+			},
+		},
+		{
+			name: "NotBanker",
+			req:  &pb.GetTrialBalanceRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTrialBalance(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.GetTrialBalanceResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.GetTrialBalance(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
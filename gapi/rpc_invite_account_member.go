@@ -0,0 +1,96 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) InviteAccountMember(ctx context.Context, req *pb.InviteAccountMemberRequest) (*pb.InviteAccountMemberResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateInviteAccountMemberRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, account, accountOwners)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Owner == db.SystemAccountOwner {
+		return nil, status.Errorf(codes.FailedPrecondition, "the bank's own accounts cannot be shared")
+	}
+
+	arg := db.CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  req.GetUsername(),
+		Role:      req.GetRole(),
+		AddedBy:   authPayload.Username,
+	}
+	if req.GetRole() == util.AccountMemberTransferor {
+		arg.TransferLimit = pgtype.Int8{Int64: req.GetTransferLimit(), Valid: true}
+	}
+
+	member, err := server.store.CreateAccountMember(ctx, arg)
+	if err != nil {
+		switch db.ErrorCode(err) {
+		case db.ForeignKeyViolation:
+			return nil, status.Errorf(codes.NotFound, "user not found: %s", err)
+		case db.UniqueViolation:
+			return nil, status.Errorf(codes.AlreadyExists, "%s is already a member of the account", req.GetUsername())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to invite account member: %s", err)
+	}
+
+	rsp := &pb.InviteAccountMemberResponse{
+		Member: convertAccountMember(member),
+	}
+
+	return rsp, nil
+}
+
+func validateInviteAccountMemberRequest(req *pb.InviteAccountMemberRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+
+	if err := val.ValidateAccountMemberRole(req.GetRole()); err != nil {
+		violations = append(violations, fieldViolation("role", err))
+	}
+
+	if req.GetRole() == util.AccountMemberTransferor {
+		if err := val.ValidateAmount(req.GetTransferLimit()); err != nil {
+			violations = append(violations, fieldViolation("transfer_limit", err))
+		}
+	} else if req.GetTransferLimit() != 0 {
+		violations = append(violations, fieldViolation("transfer_limit", fmt.Errorf("only transferors have a transfer limit")))
+	}
+
+	return violations
+}
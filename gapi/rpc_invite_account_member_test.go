@@ -0,0 +1,210 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcInviteAccountMember(t *testing.T) {
+	owner, _ := createRandomUser(t)
+	invitee, _ := createRandomUser(t)
+
+	account := createRandomAccount(owner.Username)
+	coOwner := db.AccountMember{
+		AccountID: account.ID,
+		Username:  invitee.Username,
+		Role:      util.AccountMemberCoOwner,
+		AddedBy:   owner.Username,
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.InviteAccountMemberRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.InviteAccountMemberResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.InviteAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  invitee.Username,
+				Role:      util.AccountMemberCoOwner,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: owner.Username})).Times(1).Return(accountMembership(account, owner.Username))
+
+				arg := db.CreateAccountMemberParams{
+					AccountID: account.ID,
+					Username:  invitee.Username,
+					Role:      util.AccountMemberCoOwner,
+					AddedBy:   owner.Username,
+				}
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Eq(arg)).Times(1).Return(coOwner, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, owner.Username, owner.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.InviteAccountMemberResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, invitee.Username, res.GetMember().GetUsername())
+				require.Equal(t, util.AccountMemberCoOwner, res.GetMember().GetRole())
+				require.Zero(t, res.GetMember().GetTransferLimit())
+			},
+		},
+		{
+			name: "Transferor",
+			req: &pb.InviteAccountMemberRequest{
+				AccountId:     account.ID,
+				Username:      invitee.Username,
+				Role:          util.AccountMemberTransferor,
+				TransferLimit: 500,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: owner.Username})).Times(1).Return(accountMembership(account, owner.Username))
+
+				arg := db.CreateAccountMemberParams{
+					AccountID:     account.ID,
+					Username:      invitee.Username,
+					Role:          util.AccountMemberTransferor,
+					TransferLimit: pgtype.Int8{Int64: 500, Valid: true},
+					AddedBy:       owner.Username,
+				}
+				member := db.AccountMember{
+					AccountID:     account.ID,
+					Username:      invitee.Username,
+					Role:          util.AccountMemberTransferor,
+					TransferLimit: arg.TransferLimit,
+					AddedBy:       owner.Username,
+				}
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Eq(arg)).Times(1).Return(member, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, owner.Username, owner.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.InviteAccountMemberResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, util.AccountMemberTransferor, res.GetMember().GetRole())
+				require.Equal(t, int64(500), res.GetMember().GetTransferLimit())
+			},
+		},
+		{
+			name: "TransferorWithoutLimit",
+			req: &pb.InviteAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  invitee.Username,
+				Role:      util.AccountMemberTransferor,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, owner.Username, owner.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.InviteAccountMemberResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidRole",
+			req: &pb.InviteAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  invitee.Username,
+				Role:      util.AccountMemberOwner,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, owner.Username, owner.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.InviteAccountMemberResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "CoOwnerCannotInvite",
+			req: &pb.InviteAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  owner.Username,
+				Role:      util.AccountMemberViewer,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: invitee.Username})).Times(1).Return(coOwner, nil)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, invitee.Username, invitee.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.InviteAccountMemberResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "AlreadyMember",
+			req: &pb.InviteAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  invitee.Username,
+				Role:      util.AccountMemberViewer,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: owner.Username})).Times(1).Return(accountMembership(account, owner.Username))
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrUniqueViolation)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, owner.Username, owner.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.InviteAccountMemberResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.AlreadyExists, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.InviteAccountMember(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
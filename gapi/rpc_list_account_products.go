@@ -0,0 +1,31 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccountProducts(ctx context.Context, req *pb.ListAccountProductsRequest) (*pb.ListAccountProductsResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	products, err := server.store.ListAccountProducts(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list account products: %s", err)
+	}
+
+	rsp := &pb.ListAccountProductsResponse{
+		AccountProducts: make([]*pb.AccountProduct, len(products)),
+	}
+	for i, product := range products {
+		rsp.AccountProducts[i] = convertAccountProduct(product)
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,81 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccountStatusChanges(ctx context.Context, req *pb.ListAccountStatusChangesRequest) (*pb.ListAccountStatusChangesResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListAccountStatusChangesRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	cursor, err := server.pageTokenMaker.Decode(req.GetPageToken())
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, account, accountViewers)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := server.store.ListAccountStatusChanges(ctx, db.ListAccountStatusChangesParams{
+		AccountID:      account.ID,
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterID:        cursor.ID,
+		// fetch one extra row to know whether there is a next page
+		PageSize: req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list account status changes: %s", err)
+	}
+
+	rsp := &pb.ListAccountStatusChangesResponse{}
+	if len(changes) > int(req.GetPageSize()) {
+		changes = changes[:req.GetPageSize()]
+		last := changes[len(changes)-1]
+		rsp.NextPageToken = server.pageTokenMaker.Encode(util.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	rsp.Changes = make([]*pb.AccountStatusChange, len(changes))
+	for i, change := range changes {
+		rsp.Changes[i] = convertAccountStatusChange(change)
+	}
+
+	return rsp, nil
+}
+
+func validateListAccountStatusChangesRequest(req *pb.ListAccountStatusChangesRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,100 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	owner := authPayload.Username
+	if req.Owner != nil {
+		owner = req.GetOwner()
+	}
+
+	if authPayload.Role != util.BankerRole && authPayload.Username != owner {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot list other user's accounts")
+	}
+
+	violations := validateListAccountsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	cursor, err := server.pageTokenMaker.Decode(req.GetPageToken())
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	// the accounts owner is a member of, including joint and shared ones.
+	// fetch one extra row to know whether there is a next page
+	arg := db.ListAccountsParams{
+		Username:       owner,
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterID:        cursor.ID,
+		PageSize:       req.GetPageSize() + 1,
+	}
+	if req.Type != nil {
+		arg.Type = pgtype.Text{String: req.GetType(), Valid: true}
+	}
+	if req.Currency != nil {
+		arg.Currency = pgtype.Text{String: req.GetCurrency(), Valid: true}
+	}
+
+	accounts, err := server.store.ListAccounts(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list accounts: %s", err)
+	}
+
+	rsp := &pb.ListAccountsResponse{}
+	if len(accounts) > int(req.GetPageSize()) {
+		accounts = accounts[:req.GetPageSize()]
+		last := accounts[len(accounts)-1]
+		rsp.NextPageToken = server.pageTokenMaker.Encode(util.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	rsp.Accounts = make([]*pb.Account, len(accounts))
+	for i, account := range accounts {
+		rsp.Accounts[i] = convertAccount(account)
+	}
+
+	return rsp, nil
+}
+
+func validateListAccountsRequest(req *pb.ListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Owner != nil {
+		if err := val.ValidateUsername(req.GetOwner()); err != nil {
+			violations = append(violations, fieldViolation("owner", err))
+		}
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	if req.Type != nil {
+		if err := val.ValidateAccountType(req.GetType()); err != nil {
+			violations = append(violations, fieldViolation("type", err))
+		}
+	}
+
+	if req.Currency != nil {
+		if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+			violations = append(violations, fieldViolation("currency", err))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,202 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcListAccounts(t *testing.T) {
+	user, _ := createRandomUser(t)
+	otherUser, _ := createRandomUser(t)
+
+	pageSize := int32(5)
+	accounts := make([]db.Account, pageSize+1)
+	for i := range accounts {
+		accounts[i] = createRandomAccount(user.Username)
+		accounts[i].CreatedAt = time.Now().Add(time.Duration(i) * time.Second).Truncate(time.Microsecond)
+	}
+
+	savingsType := util.AccountTypeSavings
+	usd := util.USD
+	invalidType := "brokerage"
+
+	testCases := []struct {
+		name           string
+		req            *pb.ListAccountsRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, server *Server, res *pb.ListAccountsResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.ListAccountsRequest{
+				PageSize: pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountsParams{
+					Username: user.Username,
+					PageSize: pageSize + 1,
+				}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetAccounts(), int(pageSize))
+
+				cursor, err := server.pageTokenMaker.Decode(res.GetNextPageToken())
+				require.NoError(t, err)
+				require.Equal(t, accounts[pageSize-1].ID, cursor.ID)
+				require.True(t, accounts[pageSize-1].CreatedAt.Equal(cursor.CreatedAt))
+			},
+		},
+		{
+			name: "LastPage",
+			req: &pb.ListAccountsRequest{
+				PageSize: pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return(accounts[:2], nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetAccounts(), 2)
+				require.Empty(t, res.GetNextPageToken())
+			},
+		},
+		{
+			name: "FilterByTypeAndCurrency",
+			req: &pb.ListAccountsRequest{
+				PageSize: pageSize,
+				Type:     &savingsType,
+				Currency: &usd,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountsParams{
+					Username: user.Username,
+					Type:     pgtype.Text{String: util.AccountTypeSavings, Valid: true},
+					Currency: pgtype.Text{String: util.USD, Valid: true},
+					PageSize: pageSize + 1,
+				}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts[:1], nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetAccounts(), 1)
+				require.Empty(t, res.GetNextPageToken())
+			},
+		},
+		{
+			name: "InvalidType",
+			req: &pb.ListAccountsRequest{
+				PageSize: pageSize,
+				Type:     &invalidType,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.ListAccountsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidPageToken",
+			req: &pb.ListAccountsRequest{
+				PageSize:  pageSize,
+				PageToken: "invalid",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.ListAccountsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "PermissionDenied",
+			req: &pb.ListAccountsRequest{
+				Owner:    &otherUser.Username,
+				PageSize: pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.ListAccountsResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "BankerCanList",
+			req: &pb.ListAccountsRequest{
+				Owner:    &otherUser.Username,
+				PageSize: pageSize,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, server *Server, res *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Empty(t, res.GetAccounts())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.ListAccounts(ctx, testCase.req)
+
+			testCase.checkResponses(t, server, res, err)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListCurrencies(ctx context.Context, req *pb.ListCurrenciesRequest) (*pb.ListCurrenciesResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	currencies, err := server.store.ListCurrencies(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list currencies: %s", err)
+	}
+
+	rsp := &pb.ListCurrenciesResponse{
+		Currencies: make([]*pb.Currency, len(currencies)),
+	}
+	for i, currency := range currencies {
+		rsp.Currencies[i] = convertCurrency(currency)
+	}
+
+	return rsp, nil
+}
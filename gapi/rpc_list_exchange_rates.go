@@ -0,0 +1,31 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListExchangeRates(ctx context.Context, req *pb.ListExchangeRatesRequest) (*pb.ListExchangeRatesResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	exchangeRates, err := server.store.ListExchangeRates(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list exchange rates: %s", err)
+	}
+
+	rsp := &pb.ListExchangeRatesResponse{
+		ExchangeRates: make([]*pb.ExchangeRate, len(exchangeRates)),
+	}
+	for i, exchangeRate := range exchangeRates {
+		rsp.ExchangeRates[i] = convertExchangeRate(exchangeRate)
+	}
+
+	return rsp, nil
+}
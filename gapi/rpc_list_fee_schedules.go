@@ -0,0 +1,31 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListFeeSchedules(ctx context.Context, req *pb.ListFeeSchedulesRequest) (*pb.ListFeeSchedulesResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	schedules, err := server.store.ListFeeSchedules(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list fee schedules: %s", err)
+	}
+
+	rsp := &pb.ListFeeSchedulesResponse{
+		FeeSchedules: make([]*pb.FeeSchedule, len(schedules)),
+	}
+	for i, schedule := range schedules {
+		rsp.FeeSchedules[i] = convertFeeSchedule(schedule)
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,80 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListScheduledTransferRuns(ctx context.Context, req *pb.ListScheduledTransferRunsRequest) (*pb.ListScheduledTransferRunsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListScheduledTransferRunsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	cursor, err := server.pageTokenMaker.Decode(req.GetPageToken())
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	schedule, err := server.store.GetScheduledTransfer(ctx, req.GetScheduledTransferId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "scheduled transfer not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get scheduled transfer: %s", err)
+	}
+
+	if err := server.authorizeScheduledTransfer(ctx, authPayload, schedule, accountViewers); err != nil {
+		return nil, err
+	}
+
+	runs, err := server.store.ListScheduledTransferRuns(ctx, db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: schedule.ID,
+		AfterCreatedAt:      cursor.CreatedAt,
+		AfterID:             cursor.ID,
+		// fetch one extra row to know whether there is a next page
+		PageSize: req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled transfer runs: %s", err)
+	}
+
+	rsp := &pb.ListScheduledTransferRunsResponse{}
+	if len(runs) > int(req.GetPageSize()) {
+		runs = runs[:req.GetPageSize()]
+		last := runs[len(runs)-1]
+		rsp.NextPageToken = server.pageTokenMaker.Encode(util.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	rsp.Runs = make([]*pb.ScheduledTransferRun, len(runs))
+	for i, run := range runs {
+		rsp.Runs[i] = convertScheduledTransferRun(run)
+	}
+
+	return rsp, nil
+}
+
+func validateListScheduledTransferRunsRequest(req *pb.ListScheduledTransferRunsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetScheduledTransferId()); err != nil {
+		violations = append(violations, fieldViolation("scheduled_transfer_id", err))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
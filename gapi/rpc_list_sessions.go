@@ -0,0 +1,57 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	username := authPayload.Username
+	if req.Username != nil {
+		username = req.GetUsername()
+	}
+
+	if authPayload.Role != util.BankerRole && authPayload.Username != username {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot list other user's sessions")
+	}
+
+	violations := validateListSessionsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	sessions, err := server.store.ListSessions(ctx, username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list sessions: %s", err)
+	}
+
+	rsp := &pb.ListSessionsResponse{
+		Sessions: make([]*pb.Session, len(sessions)),
+	}
+	for i, session := range sessions {
+		rsp.Sessions[i] = convertSession(session)
+	}
+
+	return rsp, nil
+}
+
+func validateListSessionsRequest(req *pb.ListSessionsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Username != nil {
+		if err := val.ValidateUsername(req.GetUsername()); err != nil {
+			violations = append(violations, fieldViolation("username", err))
+		}
+	}
+
+	return violations
+}
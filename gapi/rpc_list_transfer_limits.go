@@ -0,0 +1,31 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListTransferLimits(ctx context.Context, req *pb.ListTransferLimitsRequest) (*pb.ListTransferLimitsResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	limits, err := server.store.ListTransferLimits(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfer limits: %s", err)
+	}
+
+	rsp := &pb.ListTransferLimitsResponse{
+		TransferLimits: make([]*pb.TransferLimit, len(limits)),
+	}
+	for i, limit := range limits {
+		rsp.TransferLimits[i] = convertTransferLimit(limit)
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListTransferRequests(ctx context.Context, req *pb.ListTransferRequestsRequest) (*pb.ListTransferRequestsResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListTransferRequestsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	cursor, err := server.pageTokenMaker.Decode(req.GetPageToken())
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	requestStatus := req.GetStatus()
+	if requestStatus == "" {
+		requestStatus = db.TransferRequestStatusPending
+	}
+
+	requests, err := server.store.ListTransferRequests(ctx, db.ListTransferRequestsParams{
+		Status:         requestStatus,
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterID:        cursor.ID,
+		// fetch one extra row to know whether there is a next page
+		PageSize: req.GetPageSize() + 1,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfer requests: %s", err)
+	}
+
+	rsp := &pb.ListTransferRequestsResponse{}
+	if len(requests) > int(req.GetPageSize()) {
+		requests = requests[:req.GetPageSize()]
+		last := requests[len(requests)-1]
+		rsp.NextPageToken = server.pageTokenMaker.Encode(util.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	rsp.TransferRequests = make([]*pb.TransferRequest, len(requests))
+	for i, request := range requests {
+		rsp.TransferRequests[i] = convertTransferRequest(request)
+	}
+
+	return rsp, nil
+}
+
+func validateListTransferRequestsRequest(req *pb.ListTransferRequestsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	switch req.GetStatus() {
+	case "", db.TransferRequestStatusPending, db.TransferRequestStatusApproved, db.TransferRequestStatusRejected, db.TransferRequestStatusExpired:
+	default:
+		violations = append(violations, fieldViolation("status", fmt.Errorf("must be one of pending, approved, rejected or expired")))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,125 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateListTransfersRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	cursor, err := server.pageTokenMaker.Decode(req.GetPageToken())
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("page_token", err)})
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, account, accountViewers)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.ListAccountTransfersParams{
+		AccountID:       account.ID,
+		IncludeSent:     req.GetDirection() != pb.TransferDirection_TRANSFER_DIRECTION_RECEIVED,
+		IncludeReceived: req.GetDirection() != pb.TransferDirection_TRANSFER_DIRECTION_SENT,
+		MinAmount: pgtype.Int8{
+			Int64: req.GetMinAmount(),
+			Valid: req.MinAmount != nil,
+		},
+		MaxAmount: pgtype.Int8{
+			Int64: req.GetMaxAmount(),
+			Valid: req.MaxAmount != nil,
+		},
+		StartTime: pgtype.Timestamptz{
+			Time:  req.GetStartTime().AsTime(),
+			Valid: req.StartTime != nil,
+		},
+		EndTime: pgtype.Timestamptz{
+			Time:  req.GetEndTime().AsTime(),
+			Valid: req.EndTime != nil,
+		},
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterID:        cursor.ID,
+		// fetch one extra row to know whether there is a next page
+		PageSize: req.GetPageSize() + 1,
+	}
+
+	transfers, err := server.store.ListAccountTransfers(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list transfers: %s", err)
+	}
+
+	rsp := &pb.ListTransfersResponse{}
+	if len(transfers) > int(req.GetPageSize()) {
+		transfers = transfers[:req.GetPageSize()]
+		last := transfers[len(transfers)-1]
+		rsp.NextPageToken = server.pageTokenMaker.Encode(util.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	rsp.Transfers = make([]*pb.Transfer, len(transfers))
+	for i, transfer := range transfers {
+		rsp.Transfers[i] = convertTransfer(transfer)
+	}
+
+	return rsp, nil
+}
+
+func validateListTransfersRequest(req *pb.ListTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if _, ok := pb.TransferDirection_name[int32(req.GetDirection())]; !ok {
+		violations = append(violations, fieldViolation("direction", fmt.Errorf("is not a supported direction")))
+	}
+
+	if req.MinAmount != nil {
+		if err := val.ValidateAmount(req.GetMinAmount()); err != nil {
+			violations = append(violations, fieldViolation("min_amount", err))
+		}
+	}
+
+	if req.MaxAmount != nil {
+		if err := val.ValidateAmount(req.GetMaxAmount()); err != nil {
+			violations = append(violations, fieldViolation("max_amount", err))
+		} else if req.MinAmount != nil && req.GetMaxAmount() < req.GetMinAmount() {
+			violations = append(violations, fieldViolation("max_amount", fmt.Errorf("must not be less than min_amount")))
+		}
+	}
+
+	if req.StartTime != nil && req.EndTime != nil && !req.GetEndTime().AsTime().After(req.GetStartTime().AsTime()) {
+		violations = append(violations, fieldViolation("end_time", fmt.Errorf("must be after start_time")))
+	}
+
+	if err := val.ValidatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,182 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcListTransfers(t *testing.T) {
+	user, _ := createRandomUser(t)
+	otherUser, _ := createRandomUser(t)
+
+	account := createRandomAccount(user.Username)
+	otherAccount := createRandomAccount(otherUser.Username)
+
+	transfers := []db.Transfer{
+		{ID: 1, FromAccountID: account.ID, ToAccountID: otherAccount.ID, Amount: 10, CreatedAt: time.Now()},
+		{ID: 2, FromAccountID: otherAccount.ID, ToAccountID: account.ID, Amount: 20, CreatedAt: time.Now()},
+	}
+
+	minAmount := int64(10)
+	maxAmount := int64(5)
+
+	testCases := []struct {
+		name           string
+		req            *pb.ListTransfersRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.ListTransfersResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.ListTransfersRequest{
+				AccountId: account.ID,
+				PageSize:  5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ListAccountTransfersParams) ([]db.Transfer, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.True(t, arg.IncludeSent)
+						require.True(t, arg.IncludeReceived)
+						require.False(t, arg.MinAmount.Valid)
+						require.False(t, arg.StartTime.Valid)
+						require.Equal(t, int32(6), arg.PageSize)
+						return transfers, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetTransfers(), len(transfers))
+				require.Empty(t, res.GetNextPageToken())
+			},
+		},
+		{
+			name: "SentWithFilters",
+			req: &pb.ListTransfersRequest{
+				AccountId: account.ID,
+				Direction: pb.TransferDirection_TRANSFER_DIRECTION_SENT,
+				MinAmount: &minAmount,
+				PageSize:  5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user.Username})).Times(1).Return(accountMembership(account, user.Username))
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ListAccountTransfersParams) ([]db.Transfer, error) {
+						require.True(t, arg.IncludeSent)
+						require.False(t, arg.IncludeReceived)
+						require.True(t, arg.MinAmount.Valid)
+						require.Equal(t, minAmount, arg.MinAmount.Int64)
+						require.False(t, arg.MaxAmount.Valid)
+						return transfers[:1], nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetTransfers(), 1)
+			},
+		},
+		{
+			name: "PermissionDenied",
+			req: &pb.ListTransfersRequest{
+				AccountId: otherAccount.ID,
+				PageSize:  5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: otherAccount.ID, Username: user.Username})).Times(1).Return(accountMembership(otherAccount, user.Username))
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "BankerCanList",
+			req: &pb.ListTransfersRequest{
+				AccountId: otherAccount.ID,
+				PageSize:  5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(1).Return(transfers, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetTransfers(), len(transfers))
+			},
+		},
+		{
+			name: "InvalidAmountRange",
+			req: &pb.ListTransfersRequest{
+				AccountId: account.ID,
+				MinAmount: &minAmount,
+				MaxAmount: &maxAmount,
+				PageSize:  5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.ListTransfersResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.ListTransfers(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
@@ -54,6 +54,7 @@ func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (
 		ClientIp:     mtdt.ClientIP,
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiresAt.Time,
+		FamilyID:     refreshPayload.ID,
 	})
 
 	if err != nil {
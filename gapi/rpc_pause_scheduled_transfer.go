@@ -0,0 +1,67 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) PauseScheduledTransfer(ctx context.Context, req *pb.PauseScheduledTransferRequest) (*pb.PauseScheduledTransferResponse, error) {
+	schedule, err := server.setScheduledTransferStatus(ctx, req.GetId(), db.ScheduledTransferStatusPaused)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.PauseScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(schedule),
+	}
+
+	return rsp, nil
+}
+
+// setScheduledTransferStatus authorizes and applies a status change shared by
+// the pause, resume and cancel rpcs
+func (server *Server) setScheduledTransferStatus(ctx context.Context, id int64, newStatus string) (db.ScheduledTransfer, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return db.ScheduledTransfer{}, unauthenticatedError(err)
+	}
+
+	if err := val.ValidateID(id); err != nil {
+		return db.ScheduledTransfer{}, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("id", err)})
+	}
+
+	schedule, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return schedule, status.Errorf(codes.NotFound, "scheduled transfer not found: %s", err)
+		}
+		return schedule, status.Errorf(codes.Internal, "failed to get scheduled transfer: %s", err)
+	}
+
+	if err := server.authorizeScheduledTransfer(ctx, authPayload, schedule, accountHolders); err != nil {
+		return schedule, err
+	}
+
+	schedule, err = server.store.SetScheduledTransferStatusTx(ctx, db.SetScheduledTransferStatusTxParams{
+		ID:     id,
+		Status: newStatus,
+		Now:    time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrScheduleTransition) {
+			return schedule, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return schedule, status.Errorf(codes.Internal, "failed to update scheduled transfer: %s", err)
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,150 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcPauseScheduledTransfer(t *testing.T) {
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+	user3, _ := createRandomUser(t)
+
+	account := createRandomAccount(user1.Username)
+	coOwner := db.AccountMember{AccountID: account.ID, Username: user3.Username, Role: util.AccountMemberCoOwner}
+
+	schedule := db.ScheduledTransfer{
+		ID:            1,
+		Owner:         user1.Username,
+		FromAccountID: account.ID,
+		Amount:        100,
+		Rule:          util.ScheduleDaily,
+		Status:        db.ScheduledTransferStatusActive,
+		CreatedBy:     user3.Username,
+	}
+
+	testCases := []struct {
+		name           string
+		user           db.User
+		buildStubs     func(store *mockdb.MockStore)
+		checkResponses func(t *testing.T, res *pb.PauseScheduledTransferResponse, err error)
+	}{
+		{
+			name: "Ok",
+			user: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user1.Username})).Times(1).Return(accountMembership(account, user1.Username))
+
+				paused := schedule
+				paused.Status = db.ScheduledTransferStatusPaused
+				store.EXPECT().
+					SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.SetScheduledTransferStatusTxParams) (db.ScheduledTransfer, error) {
+						require.Equal(t, schedule.ID, arg.ID)
+						require.Equal(t, db.ScheduledTransferStatusPaused, arg.Status)
+						return paused, nil
+					})
+			},
+			checkResponses: func(t *testing.T, res *pb.PauseScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.ScheduledTransferStatusPaused, res.GetScheduledTransfer().GetStatus())
+			},
+		},
+		{
+			name: "CoOwner",
+			user: user3,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user3.Username})).Times(1).Return(coOwner, nil)
+
+				paused := schedule
+				paused.Status = db.ScheduledTransferStatusPaused
+				store.EXPECT().SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(paused, nil)
+			},
+			checkResponses: func(t *testing.T, res *pb.PauseScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.ScheduledTransferStatusPaused, res.GetScheduledTransfer().GetStatus())
+			},
+		},
+		{
+			name: "NotMember",
+			user: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user2.Username})).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.PauseScheduledTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "NotActive",
+			user: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: user1.Username})).Times(1).Return(accountMembership(account, user1.Username))
+				store.EXPECT().
+					SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ScheduledTransfer{}, db.ErrScheduleTransition)
+			},
+			checkResponses: func(t *testing.T, res *pb.PauseScheduledTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "NotFound",
+			user: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(db.ScheduledTransfer{}, db.ErrRecordNotFound)
+				store.EXPECT().SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.PauseScheduledTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := newContextWithBearerToken(t, server.tokenMaker, testCase.user.Username, testCase.user.Role, time.Minute)
+			res, err := server.PauseScheduledTransfer(ctx, &pb.PauseScheduledTransferRequest{Id: schedule.ID})
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/billy-le/simple-bank/worker"
+	"github.com/hibiken/asynq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultHoldDuration = 7 * 24 * time.Hour
+	maxHoldDuration     = 30 * 24 * time.Hour
+)
+
+func (server *Server) PlaceHold(ctx context.Context, req *pb.PlaceHoldRequest) (*pb.PlaceHoldResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validatePlaceHoldRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	expiresAt := time.Now().Add(defaultHoldDuration)
+	if req.ExpiresAt != nil {
+		expiresAt = req.GetExpiresAt().AsTime()
+	}
+
+	arg := db.PlaceHoldTxParams{
+		CreateHoldParams: db.CreateHoldParams{
+			AccountID:   req.GetAccountId(),
+			Amount:      req.GetAmount(),
+			Description: req.GetDescription(),
+			CreatedBy:   authPayload.Username,
+			ExpiresAt:   expiresAt,
+		},
+		AfterCreate: func(hold db.Hold) error {
+			taskPayload := &worker.PayloadExpireHold{
+				HoldID: hold.ID,
+			}
+
+			opts := []asynq.Option{
+				asynq.MaxRetry(10),
+				asynq.ProcessAt(hold.ExpiresAt),
+				asynq.Queue(worker.QueueCritical),
+			}
+
+			return server.taskDistributor.DistributeTaskExpireHold(ctx, taskPayload, opts...)
+		},
+	}
+
+	txResult, err := server.store.PlaceHoldTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) ||
+			errors.Is(err, db.ErrAccountFrozen) ||
+			errors.Is(err, db.ErrAccountDormant) ||
+			errors.Is(err, db.ErrAccountClosed) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to place hold: %s", err)
+	}
+
+	rsp := &pb.PlaceHoldResponse{
+		Hold:    convertHold(txResult.Hold),
+		Account: convertAccount(txResult.Account),
+	}
+
+	return rsp, nil
+}
+
+func validatePlaceHoldRequest(req *pb.PlaceHoldRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := val.ValidateAmount(req.GetAmount()); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+
+	if err := val.ValidateString(req.GetDescription(), 0, 200); err != nil {
+		violations = append(violations, fieldViolation("description", err))
+	}
+
+	if req.ExpiresAt != nil {
+		expiresIn := time.Until(req.GetExpiresAt().AsTime())
+		if expiresIn <= 0 || expiresIn > maxHoldDuration {
+			violations = append(violations, fieldViolation("expires_at", fmt.Errorf("must be in the next %s", maxHoldDuration)))
+		}
+	}
+
+	return violations
+}
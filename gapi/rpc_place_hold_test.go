@@ -0,0 +1,163 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/worker"
+	mockwk "github.com/billy-le/simple-bank/worker/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRpcPlaceHold(t *testing.T) {
+	banker, _ := createRandomUser(t)
+	depositor, _ := createRandomUser(t)
+
+	account := createRandomAccount(depositor.Username)
+	amount := int64(10)
+
+	hold := db.Hold{
+		ID:        1,
+		AccountID: account.ID,
+		Amount:    amount,
+		Status:    db.HoldStatusPending,
+		CreatedBy: banker.Username,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.PlaceHoldRequest
+		buildStubs     func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.PlaceHoldResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.PlaceHoldRequest{
+				AccountId:   account.ID,
+				Amount:      amount,
+				Description: "card authorization",
+				ExpiresAt:   timestamppb.New(hold.ExpiresAt),
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().
+					PlaceHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.PlaceHoldTxParams) (db.PlaceHoldTxResult, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, amount, arg.Amount)
+						require.Equal(t, banker.Username, arg.CreatedBy)
+						require.WithinDuration(t, hold.ExpiresAt, arg.ExpiresAt, time.Second)
+
+						err := arg.AfterCreate(hold)
+						return db.PlaceHoldTxResult{Hold: hold, Account: account}, err
+					})
+
+				taskPayload := &worker.PayloadExpireHold{
+					HoldID: hold.ID,
+				}
+				taskDistributor.EXPECT().DistributeTaskExpireHold(gomock.Any(), taskPayload, gomock.Any()).Times(1).Return(nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.PlaceHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, hold.ID, res.GetHold().GetId())
+				require.Equal(t, db.HoldStatusPending, res.GetHold().GetStatus())
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req: &pb.PlaceHoldRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().PlaceHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, depositor.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.PlaceHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			req: &pb.PlaceHoldRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().PlaceHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.PlaceHoldTxResult{}, db.ErrInsufficientFunds)
+				taskDistributor.EXPECT().DistributeTaskExpireHold(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.PlaceHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "ExpiresInThePast",
+			req: &pb.PlaceHoldRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				ExpiresAt: timestamppb.New(time.Now().Add(-time.Minute)),
+			},
+			buildStubs: func(store *mockdb.MockStore, taskDistributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().PlaceHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.PlaceHoldResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			taskCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+			defer taskCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			taskDistributor := mockwk.NewMockTaskDistributor(taskCtrl)
+
+			testCase.buildStubs(store, taskDistributor)
+
+			server := newTestServer(t, store, taskDistributor)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.PlaceHold(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
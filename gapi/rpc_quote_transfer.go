@@ -0,0 +1,69 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) QuoteTransfer(ctx context.Context, req *pb.QuoteTransferRequest) (*pb.QuoteTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, fromAccount, accountSenders)
+	if err != nil {
+		return nil, err
+	}
+
+	toCurrency := req.GetToCurrency()
+	if toCurrency == "" {
+		toCurrency = req.GetCurrency()
+	}
+
+	_, err = server.validAccount(ctx, req.GetToAccountId(), toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := server.store.QuoteTransfer(ctx, db.TransferTxParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        req.GetAmount(),
+		CrossCurrency: toCurrency != req.GetCurrency(),
+		Username:      authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrExchangeRateNotFound) || errors.Is(err, db.ErrConvertedAmountTooSmall) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to quote transfer: %s", err)
+	}
+
+	rsp := &pb.QuoteTransferResponse{
+		Amount:       quote.Amount,
+		ToAmount:     quote.ToAmount,
+		ExchangeRate: convertNumeric(quote.ExchangeRate),
+		SpreadBps:    quote.SpreadBps.Int32,
+		Fee:          quote.Fee,
+		TotalDebit:   quote.TotalDebit,
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,60 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RejectTransferRequest(ctx context.Context, req *pb.RejectTransferRequestRequest) (*pb.RejectTransferRequestResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateRejectTransferRequestRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	request, err := server.store.CloseTransferRequestTx(ctx, db.CloseTransferRequestTxParams{
+		ID:        req.GetId(),
+		Status:    db.TransferRequestStatusRejected,
+		DecidedBy: authPayload.Username,
+		Reason:    req.GetReason(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "transfer request not found: %s", err)
+		}
+		if errors.Is(err, db.ErrTransferRequestNotPending) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reject transfer request: %s", err)
+	}
+
+	rsp := &pb.RejectTransferRequestResponse{
+		TransferRequest: convertTransferRequest(request),
+	}
+
+	return rsp, nil
+}
+
+func validateRejectTransferRequestRequest(req *pb.RejectTransferRequestRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+
+	if err := val.ValidateString(req.GetReason(), 1, 200); err != nil {
+		violations = append(violations, fieldViolation("reason", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,86 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RemoveAccountMember(ctx context.Context, req *pb.RemoveAccountMemberRequest) (*pb.RemoveAccountMemberResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateRemoveAccountMemberRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	// members may leave an account on their own, only its owner removes others
+	memberRoles := accountOwners
+	if req.GetUsername() == authPayload.Username {
+		memberRoles = accountViewers
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, account, memberRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := server.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  req.GetUsername(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "%s is not a member of the account", req.GetUsername())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get account member: %s", err)
+	}
+
+	if member.Role == util.AccountMemberOwner {
+		return nil, status.Errorf(codes.FailedPrecondition, "the owner of an account cannot be removed from it")
+	}
+
+	err = server.store.DeleteAccountMember(ctx, db.DeleteAccountMemberParams{
+		AccountID: member.AccountID,
+		Username:  member.Username,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to remove account member: %s", err)
+	}
+
+	rsp := &pb.RemoveAccountMemberResponse{
+		IsRemoved: true,
+	}
+
+	return rsp, nil
+}
+
+func validateRemoveAccountMemberRequest(req *pb.RemoveAccountMemberRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := val.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+
+	return violations
+}
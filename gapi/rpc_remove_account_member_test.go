@@ -0,0 +1,161 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcRemoveAccountMember(t *testing.T) {
+	owner, _ := createRandomUser(t)
+	viewer, _ := createRandomUser(t)
+	stranger, _ := createRandomUser(t)
+
+	account := createRandomAccount(owner.Username)
+	viewerMember := db.AccountMember{
+		AccountID: account.ID,
+		Username:  viewer.Username,
+		Role:      util.AccountMemberViewer,
+		AddedBy:   owner.Username,
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.RemoveAccountMemberRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.RemoveAccountMemberResponse, err error)
+	}{
+		{
+			name: "OwnerRemovesMember",
+			req: &pb.RemoveAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  viewer.Username,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: owner.Username})).Times(1).Return(accountMembership(account, owner.Username))
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: viewer.Username})).Times(1).Return(viewerMember, nil)
+				store.EXPECT().DeleteAccountMember(gomock.Any(), gomock.Eq(db.DeleteAccountMemberParams{AccountID: account.ID, Username: viewer.Username})).Times(1).Return(nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, owner.Username, owner.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.RemoveAccountMemberResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsRemoved())
+			},
+		},
+		{
+			name: "MemberLeaves",
+			req: &pb.RemoveAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  viewer.Username,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: viewer.Username})).Times(2).Return(viewerMember, nil)
+				store.EXPECT().DeleteAccountMember(gomock.Any(), gomock.Eq(db.DeleteAccountMemberParams{AccountID: account.ID, Username: viewer.Username})).Times(1).Return(nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, viewer.Username, viewer.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.RemoveAccountMemberResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetIsRemoved())
+			},
+		},
+		{
+			name: "MemberCannotRemoveOthers",
+			req: &pb.RemoveAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  owner.Username,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: viewer.Username})).Times(1).Return(viewerMember, nil)
+				store.EXPECT().DeleteAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, viewer.Username, viewer.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.RemoveAccountMemberResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "OwnerCannotBeRemoved",
+			req: &pb.RemoveAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  owner.Username,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: owner.Username})).Times(2).Return(accountMembership(account, owner.Username))
+				store.EXPECT().DeleteAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, owner.Username, owner.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.RemoveAccountMemberResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "NotAMember",
+			req: &pb.RemoveAccountMemberRequest{
+				AccountId: account.ID,
+				Username:  stranger.Username,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: owner.Username})).Times(1).Return(accountMembership(account, owner.Username))
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: stranger.Username})).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().DeleteAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, owner.Username, owner.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.RemoveAccountMemberResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.RemoveAccountMember(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
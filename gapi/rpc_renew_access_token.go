@@ -0,0 +1,115 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (server *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	violations := validateRenewAccessTokenRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.GetRefreshToken())
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "session not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %s", err)
+	}
+
+	if session.IsBlocked {
+		return nil, status.Errorf(codes.Unauthenticated, "blocked session")
+	}
+
+	if session.IsRotated {
+		return nil, server.blockReusedSession(ctx, session)
+	}
+
+	if session.Username != refreshPayload.Username {
+		return nil, status.Errorf(codes.Unauthenticated, "incorrect session user")
+	}
+
+	if session.RefreshToken != req.GetRefreshToken() {
+		return nil, status.Errorf(codes.Unauthenticated, "mismatched session token")
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return nil, status.Errorf(codes.Unauthenticated, "expired session")
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(session.Username, refreshPayload.Role, server.config.AccessTokenDuration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create access token: %s", err)
+	}
+
+	// the rotated refresh token keeps the expiry of the original login
+	refreshToken, newRefreshPayload, err := server.tokenMaker.CreateToken(session.Username, refreshPayload.Role, time.Until(session.ExpiresAt))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create refresh token: %s", err)
+	}
+
+	mtdt := server.extractMetaData(ctx)
+
+	txResult, err := server.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		OldSessionID: session.ID,
+		CreateSessionParams: db.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			Username:     session.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    mtdt.UserAgent,
+			ClientIp:     mtdt.ClientIP,
+			IsBlocked:    false,
+			ExpiresAt:    newRefreshPayload.ExpiresAt.Time,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrSessionRotated) {
+			return nil, server.blockReusedSession(ctx, session)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot rotate session: %s", err)
+	}
+
+	rsp := &pb.RenewAccessTokenResponse{
+		SessionId:             txResult.Session.ID.String(),
+		AccessToken:           accessToken,
+		RefreshToken:          refreshToken,
+		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiresAt.Time),
+		RefreshTokenExpiresAt: timestamppb.New(newRefreshPayload.ExpiresAt.Time),
+	}
+
+	return rsp, nil
+}
+
+// blockReusedSession blocks every session of the login when a refresh token that
+// was already rotated is presented again, since it may have been stolen.
+func (server *Server) blockReusedSession(ctx context.Context, session db.Session) error {
+	if err := server.store.BlockSessionFamily(ctx, session.FamilyID); err != nil {
+		return status.Errorf(codes.Internal, "failed to block session: %s", err)
+	}
+
+	return status.Errorf(codes.Unauthenticated, "refresh token reuse detected, session has been blocked")
+}
+
+func validateRenewAccessTokenRequest(req *pb.RenewAccessTokenRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateString(req.GetRefreshToken(), 1, 1024); err != nil {
+		violations = append(violations, fieldViolation("refresh_token", err))
+	}
+
+	return violations
+}
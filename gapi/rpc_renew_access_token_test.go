@@ -0,0 +1,209 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcRenewAccessToken(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	testCases := []struct {
+		name           string
+		modifySession  func(session *db.Session)
+		buildStubs     func(store *mockdb.MockStore, session db.Session)
+		checkResponses func(t *testing.T, res *pb.RenewAccessTokenResponse, err error)
+	}{
+		{
+			name:          "Ok",
+			modifySession: func(session *db.Session) {},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+						require.Equal(t, session.ID, arg.OldSessionID)
+						require.NotEqual(t, session.ID, arg.ID)
+						require.Equal(t, session.Username, arg.Username)
+						require.WithinDuration(t, session.ExpiresAt, arg.ExpiresAt, time.Second)
+						return db.RotateSessionTxResult{
+							Session: db.Session{
+								ID:           arg.ID,
+								Username:     arg.Username,
+								RefreshToken: arg.RefreshToken,
+								FamilyID:     session.FamilyID,
+								ExpiresAt:    arg.ExpiresAt,
+							},
+						}, nil
+					})
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+				require.NotEmpty(t, res.GetSessionId())
+			},
+		},
+		{
+			name:          "NotFound",
+			modifySession: func(session *db.Session) {},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(db.Session{}, db.ErrRecordNotFound)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "BlockedSession",
+			modifySession: func(session *db.Session) {
+				session.IsBlocked = true
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "ReusedToken",
+			modifySession: func(session *db.Session) {
+				session.IsRotated = true
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name:          "ConcurrentRotation",
+			modifySession: func(session *db.Session) {},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(1).Return(db.RotateSessionTxResult{}, db.ErrSessionRotated)
+				store.EXPECT().BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(nil)
+			},
+			checkResponses: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "MismatchedToken",
+			modifySession: func(session *db.Session) {
+				session.RefreshToken = "other-token"
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "IncorrectUser",
+			modifySession: func(session *db.Session) {
+				session.Username = "someone-else"
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RotateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.RenewAccessTokenResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			server := newTestServer(t, store, nil)
+
+			session := createRandomSession(t, server.tokenMaker, user)
+			refreshToken := session.RefreshToken
+			testCase.modifySession(&session)
+			testCase.buildStubs(store, session)
+
+			res, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{
+				RefreshToken: refreshToken,
+			})
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
+
+func TestRpcRenewAccessTokenInvalidToken(t *testing.T) {
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+
+	store := mockdb.NewMockStore(storeCtrl)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store, nil)
+
+	res, err := server.RenewAccessToken(context.Background(), &pb.RenewAccessTokenRequest{
+		RefreshToken: "invalid",
+	})
+	require.Error(t, err)
+	require.Nil(t, res)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unauthenticated, st.Code())
+}
+
+func createRandomSession(t *testing.T, tokenMaker token.Maker, user db.User) db.Session {
+	refreshToken, payload, err := tokenMaker.CreateToken(user.Username, user.Role, time.Hour)
+	require.NoError(t, err)
+
+	return db.Session{
+		ID:           payload.ID,
+		Username:     user.Username,
+		RefreshToken: refreshToken,
+		FamilyID:     payload.ID,
+		ExpiresAt:    payload.ExpiresAt.Time,
+	}
+}
@@ -0,0 +1,21 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+)
+
+func (server *Server) ResumeScheduledTransfer(ctx context.Context, req *pb.ResumeScheduledTransferRequest) (*pb.ResumeScheduledTransferResponse, error) {
+	schedule, err := server.setScheduledTransferStatus(ctx, req.GetId(), db.ScheduledTransferStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.ResumeScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(schedule),
+	}
+
+	return rsp, nil
+}
@@ -0,0 +1,95 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateReverseTransferRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.GetTransferId())
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "transfer not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get transfer: %s", err)
+	}
+
+	// the money comes back out of the account that received it, so only its
+	// owner or a banker may give it back
+	toAccount, err := server.store.GetAccount(ctx, transfer.ToAccountID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get account: %s", err)
+	}
+
+	_, err = server.authorizeAccount(ctx, authPayload, toAccount, accountHolders)
+	if err != nil {
+		return nil, err
+	}
+
+	txResult, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+		Amount:     req.GetAmount(),
+		ReasonCode: req.GetReasonCode(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTransferAlreadyReversed) ||
+			errors.Is(err, db.ErrReversalExceedsTransfer) ||
+			errors.Is(err, db.ErrCannotReverseReversal) ||
+			errors.Is(err, db.ErrConvertedAmountTooSmall) ||
+			errors.Is(err, db.ErrInsufficientFunds) ||
+			errors.Is(err, db.ErrAccountFrozen) ||
+			errors.Is(err, db.ErrAccountDormant) ||
+			errors.Is(err, db.ErrAccountClosed) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reverse transfer: %s", err)
+	}
+
+	rsp := &pb.ReverseTransferResponse{
+		OriginalTransfer: convertTransfer(txResult.OriginalTransfer),
+		Transfer:         convertTransfer(txResult.Transfer),
+		FromAccount:      convertAccount(txResult.FromAccount),
+		ToAccount:        convertAccount(txResult.ToAccount),
+		FromEntry:        convertEntry(txResult.FromEntry),
+		ToEntry:          convertEntry(txResult.ToEntry),
+	}
+
+	return rsp, nil
+}
+
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetTransferId()); err != nil {
+		violations = append(violations, fieldViolation("transfer_id", err))
+	}
+
+	// zero reverses the rest of the transfer
+	if req.GetAmount() != 0 {
+		if err := val.ValidateAmount(req.GetAmount()); err != nil {
+			violations = append(violations, fieldViolation("amount", err))
+		}
+	}
+
+	if err := val.ValidateReasonCode(req.GetReasonCode()); err != nil {
+		violations = append(violations, fieldViolation("reason_code", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,200 @@
+package gapi
+
+import (
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcReverseTransfer(t *testing.T) {
+	user1, _ := createRandomUser(t)
+	user2, _ := createRandomUser(t)
+	banker, _ := createRandomUser(t)
+	banker.Role = util.BankerRole
+
+	account1 := createRandomAccount(user1.Username)
+	account2 := createRandomAccount(user2.Username)
+	account2.ID = account1.ID + 1
+
+	transfer := db.Transfer{
+		ID:            1,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ToAmount:      10,
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.ReverseTransferRequest
+		user           db.User
+		buildStubs     func(store *mockdb.MockStore)
+		checkResponses func(t *testing.T, res *pb.ReverseTransferResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+				Amount:     4,
+				ReasonCode: util.ReasonCodeCustomerRequest,
+			},
+			user: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account2.ID, Username: user2.Username})).Times(1).Return(accountMembership(account2, user2.Username))
+
+				arg := db.ReverseTransferTxParams{
+					TransferID: transfer.ID,
+					Amount:     4,
+					ReasonCode: util.ReasonCodeCustomerRequest,
+				}
+				original := transfer
+				original.ReversedAmount = 4
+				result := db.ReverseTransferTxResult{
+					OriginalTransfer: original,
+					TransferTxResult: db.TransferTxResult{
+						Transfer: db.Transfer{
+							ID:            2,
+							FromAccountID: account2.ID,
+							ToAccountID:   account1.ID,
+							Amount:        4,
+							ToAmount:      4,
+							ReversalOf:    pgtype.Int8{Int64: transfer.ID, Valid: true},
+							ReasonCode:    pgtype.Text{String: util.ReasonCodeCustomerRequest, Valid: true},
+						},
+					},
+				}
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponses: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(4), res.GetOriginalTransfer().GetReversedAmount())
+				require.Equal(t, transfer.ID, res.GetTransfer().GetReversalOf())
+				require.Equal(t, util.ReasonCodeCustomerRequest, res.GetTransfer().GetReasonCode())
+				require.Equal(t, account2.ID, res.GetTransfer().GetFromAccountId())
+			},
+		},
+		{
+			name: "SenderCannotReverse",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+				ReasonCode: util.ReasonCodeFraud,
+			},
+			user: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account2.ID, Username: user1.Username})).Times(1).Return(accountMembership(account2, user1.Username))
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.PermissionDenied, st.Code())
+			},
+		},
+		{
+			name: "BankerCanReverse",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+				ReasonCode: util.ReasonCodeFraud,
+			},
+			user: banker,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, nil)
+			},
+			checkResponses: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyReversed",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+				ReasonCode: util.ReasonCodeDuplicate,
+			},
+			user: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account2.ID, Username: user2.Username})).Times(1).Return(accountMembership(account2, user2.Username))
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, db.ErrTransferAlreadyReversed)
+			},
+			checkResponses: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "AccountClosed",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+				ReasonCode: util.ReasonCodeDuplicate,
+			},
+			user: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account2.ID, Username: user2.Username})).Times(1).Return(accountMembership(account2, user2.Username))
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, db.ErrAccountClosed)
+			},
+			checkResponses: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "InvalidReasonCode",
+			req: &pb.ReverseTransferRequest{
+				TransferId: transfer.ID,
+				ReasonCode: "changed_my_mind",
+			},
+			user: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponses: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := newContextWithBearerToken(t, server.tokenMaker, testCase.user.Username, testCase.user.Role, time.Minute)
+			res, err := server.ReverseTransfer(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
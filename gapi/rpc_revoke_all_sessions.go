@@ -0,0 +1,54 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) RevokeAllSessions(ctx context.Context, req *pb.RevokeAllSessionsRequest) (*pb.RevokeAllSessionsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.DepositorRole, util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	username := authPayload.Username
+	if req.Username != nil {
+		username = req.GetUsername()
+	}
+
+	if authPayload.Role != util.BankerRole && authPayload.Username != username {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot revoke other user's sessions")
+	}
+
+	violations := validateRevokeAllSessionsRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	revoked, err := server.store.BlockUserSessions(ctx, username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke sessions: %s", err)
+	}
+
+	rsp := &pb.RevokeAllSessionsResponse{
+		RevokedSessions: revoked,
+	}
+
+	return rsp, nil
+}
+
+func validateRevokeAllSessionsRequest(req *pb.RevokeAllSessionsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.Username != nil {
+		if err := val.ValidateUsername(req.GetUsername()); err != nil {
+			violations = append(violations, fieldViolation("username", err))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,193 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcSetAccountStatus(t *testing.T) {
+	banker, _ := createRandomUser(t)
+	depositor, _ := createRandomUser(t)
+
+	account := createRandomAccount(depositor.Username)
+	frozenAccount := account
+	frozenAccount.Status = util.AccountStatusFrozen
+
+	change := db.AccountStatusChange{
+		ID:         1,
+		AccountID:  account.ID,
+		FromStatus: util.AccountStatusActive,
+		ToStatus:   util.AccountStatusFrozen,
+		Reason:     "suspected fraud",
+		ChangedBy:  pgtype.Text{String: banker.Username, Valid: true},
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.SetAccountStatusRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.SetAccountStatusResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.SetAccountStatusRequest{
+				AccountId: account.ID,
+				Status:    util.AccountStatusFrozen,
+				Reason:    change.Reason,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SetAccountStatusTxParams{
+					AccountID: account.ID,
+					Status:    util.AccountStatusFrozen,
+					Reason:    change.Reason,
+					ChangedBy: banker.Username,
+				}
+				result := db.SetAccountStatusTxResult{
+					Account: frozenAccount,
+					Change:  change,
+				}
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetAccountStatusResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, util.AccountStatusFrozen, res.GetAccount().GetStatus())
+				require.Equal(t, util.AccountStatusActive, res.GetChange().GetFromStatus())
+				require.Equal(t, change.Reason, res.GetChange().GetReason())
+				require.Equal(t, banker.Username, res.GetChange().GetChangedBy())
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req: &pb.SetAccountStatusRequest{
+				AccountId: account.ID,
+				Status:    util.AccountStatusFrozen,
+				Reason:    change.Reason,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, depositor.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetAccountStatusResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "Dormant",
+			req: &pb.SetAccountStatusRequest{
+				AccountId: account.ID,
+				Status:    util.AccountStatusDormant,
+				Reason:    change.Reason,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetAccountStatusResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "MissingReason",
+			req: &pb.SetAccountStatusRequest{
+				AccountId: account.ID,
+				Status:    util.AccountStatusFrozen,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetAccountStatusResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidTransition",
+			req: &pb.SetAccountStatusRequest{
+				AccountId: account.ID,
+				Status:    util.AccountStatusActive,
+				Reason:    change.Reason,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.SetAccountStatusTxResult{}, db.ErrAccountStatusTransition)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetAccountStatusResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "NotFound",
+			req: &pb.SetAccountStatusRequest{
+				AccountId: account.ID,
+				Status:    util.AccountStatusFrozen,
+				Reason:    change.Reason,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SetAccountStatusTx(gomock.Any(), gomock.Any()).Times(1).Return(db.SetAccountStatusTxResult{}, db.ErrRecordNotFound)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetAccountStatusResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.SetAccountStatus(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetExchangeRate(ctx context.Context, req *pb.SetExchangeRateRequest) (*pb.SetExchangeRateResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetExchangeRateRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	var rate pgtype.Numeric
+	if err := rate.Scan(req.GetRate()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("rate", err)})
+	}
+
+	exchangeRate, err := server.store.UpsertExchangeRate(ctx, db.UpsertExchangeRateParams{
+		FromCurrency: req.GetFromCurrency(),
+		ToCurrency:   req.GetToCurrency(),
+		Rate:         rate,
+		SpreadBps:    req.GetSpreadBps(),
+		UpdatedBy:    authPayload.Username,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set exchange rate: %s", err)
+	}
+
+	rsp := &pb.SetExchangeRateResponse{
+		ExchangeRate: convertExchangeRate(exchangeRate),
+	}
+
+	return rsp, nil
+}
+
+func validateSetExchangeRateRequest(req *pb.SetExchangeRateRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateCurrency(req.GetFromCurrency()); err != nil {
+		violations = append(violations, fieldViolation("from_currency", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetToCurrency()); err != nil {
+		violations = append(violations, fieldViolation("to_currency", err))
+	}
+
+	if req.GetFromCurrency() == req.GetToCurrency() {
+		violations = append(violations, fieldViolation("to_currency", fmt.Errorf("must be different from from_currency")))
+	}
+
+	if err := val.ValidateExchangeRate(req.GetRate()); err != nil {
+		violations = append(violations, fieldViolation("rate", err))
+	}
+
+	if err := val.ValidateSpreadBps(req.GetSpreadBps()); err != nil {
+		violations = append(violations, fieldViolation("spread_bps", err))
+	}
+
+	return violations
+}
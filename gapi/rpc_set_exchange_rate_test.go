@@ -0,0 +1,148 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcSetExchangeRate(t *testing.T) {
+	banker, _ := createRandomUser(t)
+	depositor, _ := createRandomUser(t)
+
+	testCases := []struct {
+		name           string
+		req            *pb.SetExchangeRateRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.SetExchangeRateResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.SetExchangeRateRequest{
+				FromCurrency: util.USD,
+				ToCurrency:   util.EUR,
+				Rate:         "0.92",
+				SpreadBps:    50,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertExchangeRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpsertExchangeRateParams) (db.ExchangeRate, error) {
+						require.Equal(t, util.USD, arg.FromCurrency)
+						require.Equal(t, util.EUR, arg.ToCurrency)
+						require.Equal(t, int32(50), arg.SpreadBps)
+						require.Equal(t, banker.Username, arg.UpdatedBy)
+						require.Equal(t, int64(92), arg.Rate.Int.Int64())
+						require.Equal(t, int32(-2), arg.Rate.Exp)
+
+						return db.ExchangeRate{
+							FromCurrency: arg.FromCurrency,
+							ToCurrency:   arg.ToCurrency,
+							Rate:         arg.Rate,
+							SpreadBps:    arg.SpreadBps,
+							UpdatedBy:    arg.UpdatedBy,
+							UpdatedAt:    time.Now(),
+						}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetExchangeRateResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "0.92", res.GetExchangeRate().GetRate())
+				require.Equal(t, banker.Username, res.GetExchangeRate().GetUpdatedBy())
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req: &pb.SetExchangeRateRequest{
+				FromCurrency: util.USD,
+				ToCurrency:   util.EUR,
+				Rate:         "0.92",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertExchangeRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, depositor.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetExchangeRateResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "InvalidRate",
+			req: &pb.SetExchangeRateRequest{
+				FromCurrency: util.USD,
+				ToCurrency:   util.EUR,
+				Rate:         "0.00",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertExchangeRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetExchangeRateResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "SameCurrency",
+			req: &pb.SetExchangeRateRequest{
+				FromCurrency: util.USD,
+				ToCurrency:   util.USD,
+				Rate:         "1",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertExchangeRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetExchangeRateResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.SetExchangeRate(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
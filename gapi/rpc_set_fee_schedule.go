@@ -0,0 +1,88 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetFeeSchedule(ctx context.Context, req *pb.SetFeeScheduleRequest) (*pb.SetFeeScheduleResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetFeeScheduleRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.UpsertFeeScheduleParams{
+		TransferType: req.GetTransferType(),
+		Currency:     req.GetCurrency(),
+		Role:         req.GetRole(),
+		MinAmount:    req.GetMinAmount(),
+		FlatFee:      req.GetFlatFee(),
+		RateBps:      req.GetRateBps(),
+		MinFee:       req.GetMinFee(),
+		UpdatedBy:    authPayload.Username,
+	}
+	if req.MaxFee != nil {
+		arg.MaxFee = pgtype.Int8{Int64: req.GetMaxFee(), Valid: true}
+	}
+
+	schedule, err := server.store.UpsertFeeSchedule(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set fee schedule: %s", err)
+	}
+
+	rsp := &pb.SetFeeScheduleResponse{
+		FeeSchedule: convertFeeSchedule(schedule),
+	}
+
+	return rsp, nil
+}
+
+func validateSetFeeScheduleRequest(req *pb.SetFeeScheduleRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateTransferType(req.GetTransferType()); err != nil {
+		violations = append(violations, fieldViolation("transfer_type", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if err := val.ValidateRole(req.GetRole()); err != nil {
+		violations = append(violations, fieldViolation("role", err))
+	}
+
+	if err := val.ValidateFeeAmount(req.GetMinAmount()); err != nil {
+		violations = append(violations, fieldViolation("min_amount", err))
+	}
+
+	if err := val.ValidateFeeAmount(req.GetFlatFee()); err != nil {
+		violations = append(violations, fieldViolation("flat_fee", err))
+	}
+
+	if err := val.ValidateRateBps(req.GetRateBps()); err != nil {
+		violations = append(violations, fieldViolation("rate_bps", err))
+	}
+
+	if err := val.ValidateFeeAmount(req.GetMinFee()); err != nil {
+		violations = append(violations, fieldViolation("min_fee", err))
+	}
+
+	if req.MaxFee != nil && req.GetMaxFee() < req.GetMinFee() {
+		violations = append(violations, fieldViolation("max_fee", fmt.Errorf("must not be less than min_fee")))
+	}
+
+	return violations
+}
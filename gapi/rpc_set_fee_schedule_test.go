@@ -0,0 +1,171 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcSetFeeSchedule(t *testing.T) {
+	banker, _ := createRandomUser(t)
+	depositor, _ := createRandomUser(t)
+
+	maxFee := int64(500)
+	lowMaxFee := int64(50)
+
+	testCases := []struct {
+		name           string
+		req            *pb.SetFeeScheduleRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.SetFeeScheduleResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.SetFeeScheduleRequest{
+				TransferType: util.TransferTypeCrossCurrency,
+				Currency:     util.USD,
+				Role:         util.DepositorRole,
+				MinAmount:    10000,
+				FlatFee:      100,
+				RateBps:      150,
+				MinFee:       200,
+				MaxFee:       &maxFee,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertFeeSchedule(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpsertFeeScheduleParams) (db.FeeSchedule, error) {
+						require.Equal(t, util.TransferTypeCrossCurrency, arg.TransferType)
+						require.Equal(t, util.USD, arg.Currency)
+						require.Equal(t, util.DepositorRole, arg.Role)
+						require.Equal(t, int64(10000), arg.MinAmount)
+						require.Equal(t, int32(150), arg.RateBps)
+						require.True(t, arg.MaxFee.Valid)
+						require.Equal(t, maxFee, arg.MaxFee.Int64)
+						require.Equal(t, banker.Username, arg.UpdatedBy)
+
+						return db.FeeSchedule{
+							TransferType: arg.TransferType,
+							Currency:     arg.Currency,
+							Role:         arg.Role,
+							MinAmount:    arg.MinAmount,
+							FlatFee:      arg.FlatFee,
+							RateBps:      arg.RateBps,
+							MinFee:       arg.MinFee,
+							MaxFee:       arg.MaxFee,
+							UpdatedBy:    arg.UpdatedBy,
+							UpdatedAt:    time.Now(),
+						}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetFeeScheduleResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(100), res.GetFeeSchedule().GetFlatFee())
+				require.NotNil(t, res.GetFeeSchedule().MaxFee)
+				require.Equal(t, maxFee, res.GetFeeSchedule().GetMaxFee())
+			},
+		},
+		{
+			name: "Uncapped",
+			req: &pb.SetFeeScheduleRequest{
+				TransferType: util.TransferTypeTransfer,
+				Currency:     util.EUR,
+				Role:         util.DepositorRole,
+				FlatFee:      25,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertFeeSchedule(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpsertFeeScheduleParams) (db.FeeSchedule, error) {
+						require.False(t, arg.MaxFee.Valid)
+						return db.FeeSchedule{TransferType: arg.TransferType, FlatFee: arg.FlatFee}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetFeeScheduleResponse, err error) {
+				require.NoError(t, err)
+				require.Nil(t, res.GetFeeSchedule().MaxFee)
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req: &pb.SetFeeScheduleRequest{
+				TransferType: util.TransferTypeTransfer,
+				Currency:     util.USD,
+				Role:         util.DepositorRole,
+				FlatFee:      25,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, depositor.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetFeeScheduleResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "InvalidSchedule",
+			req: &pb.SetFeeScheduleRequest{
+				TransferType: "wire",
+				Currency:     util.USD,
+				Role:         util.DepositorRole,
+				RateBps:      20000,
+				MinFee:       100,
+				MaxFee:       &lowMaxFee,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertFeeSchedule(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetFeeScheduleResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.SetFeeSchedule(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
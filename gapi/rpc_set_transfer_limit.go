@@ -0,0 +1,127 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) SetTransferLimit(ctx context.Context, req *pb.SetTransferLimitRequest) (*pb.SetTransferLimitResponse, error) {
+	authPayload, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateSetTransferLimitRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.UpsertTransferLimitParams{
+		Scope:     req.GetScope(),
+		Currency:  req.GetCurrency(),
+		Period:    req.GetPeriod(),
+		UpdatedBy: authPayload.Username,
+	}
+	if req.Role != nil {
+		arg.Role = pgtype.Text{String: req.GetRole(), Valid: true}
+	}
+	if req.Username != nil {
+		arg.Username = pgtype.Text{String: req.GetUsername(), Valid: true}
+	}
+	if req.AccountId != nil {
+		arg.AccountID = pgtype.Int8{Int64: req.GetAccountId(), Valid: true}
+
+		_, err = server.validAccount(ctx, req.GetAccountId(), req.GetCurrency())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.MaxAmount != nil {
+		arg.MaxAmount = pgtype.Int8{Int64: req.GetMaxAmount(), Valid: true}
+	}
+	if req.MaxCount != nil {
+		arg.MaxCount = pgtype.Int4{Int32: req.GetMaxCount(), Valid: true}
+	}
+
+	limit, err := server.store.UpsertTransferLimit(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			return nil, status.Errorf(codes.NotFound, "user not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to set transfer limit: %s", err)
+	}
+
+	rsp := &pb.SetTransferLimitResponse{
+		TransferLimit: convertTransferLimit(limit),
+	}
+
+	return rsp, nil
+}
+
+func validateSetTransferLimitRequest(req *pb.SetTransferLimitRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	subjects := 0
+
+	if req.Role != nil {
+		subjects++
+		if err := val.ValidateRole(req.GetRole()); err != nil {
+			violations = append(violations, fieldViolation("role", err))
+		}
+	}
+
+	if req.Username != nil {
+		subjects++
+		if err := val.ValidateUsername(req.GetUsername()); err != nil {
+			violations = append(violations, fieldViolation("username", err))
+		}
+		if req.GetScope() != util.LimitScopeUser {
+			violations = append(violations, fieldViolation("scope", fmt.Errorf("must be user when overriding the limit of a user")))
+		}
+	}
+
+	if req.AccountId != nil {
+		subjects++
+		if err := val.ValidateID(req.GetAccountId()); err != nil {
+			violations = append(violations, fieldViolation("account_id", err))
+		}
+		if req.GetScope() != util.LimitScopeAccount {
+			violations = append(violations, fieldViolation("scope", fmt.Errorf("must be account when overriding the limit of an account")))
+		}
+	}
+
+	if subjects != 1 {
+		violations = append(violations, fieldViolation("role", fmt.Errorf("exactly one of role, username or account_id must be set")))
+	}
+
+	if err := val.ValidateLimitScope(req.GetScope()); err != nil {
+		violations = append(violations, fieldViolation("scope", err))
+	}
+
+	if err := val.ValidateCurrency(req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if err := val.ValidateLimitPeriod(req.GetPeriod()); err != nil {
+		violations = append(violations, fieldViolation("period", err))
+	}
+
+	if req.MaxAmount != nil {
+		if err := val.ValidateFeeAmount(req.GetMaxAmount()); err != nil {
+			violations = append(violations, fieldViolation("max_amount", err))
+		}
+	}
+
+	if req.MaxCount != nil && req.GetMaxCount() < 0 {
+		violations = append(violations, fieldViolation("max_count", fmt.Errorf("must not be negative")))
+	}
+
+	return violations
+}
@@ -0,0 +1,199 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcSetTransferLimit(t *testing.T) {
+	banker, _ := createRandomUser(t)
+	depositor, _ := createRandomUser(t)
+	account := createRandomAccount(depositor.Username)
+
+	role := util.DepositorRole
+	maxAmount := int64(100_000)
+	maxCount := int32(10)
+
+	testCases := []struct {
+		name           string
+		req            *pb.SetTransferLimitRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.SetTransferLimitResponse, err error)
+	}{
+		{
+			name: "Role",
+			req: &pb.SetTransferLimitRequest{
+				Role:      &role,
+				Scope:     util.LimitScopeUser,
+				Currency:  util.USD,
+				Period:    util.LimitPeriodDaily,
+				MaxAmount: &maxAmount,
+				MaxCount:  &maxCount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertTransferLimitParams{
+					Role:      pgtype.Text{String: role, Valid: true},
+					Scope:     util.LimitScopeUser,
+					Currency:  util.USD,
+					Period:    util.LimitPeriodDaily,
+					MaxAmount: pgtype.Int8{Int64: maxAmount, Valid: true},
+					MaxCount:  pgtype.Int4{Int32: maxCount, Valid: true},
+					UpdatedBy: banker.Username,
+				}
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Eq(arg)).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpsertTransferLimitParams) (db.TransferLimit, error) {
+						return db.TransferLimit{
+							ID:        1,
+							Role:      arg.Role,
+							Scope:     arg.Scope,
+							Currency:  arg.Currency,
+							Period:    arg.Period,
+							MaxAmount: arg.MaxAmount,
+							MaxCount:  arg.MaxCount,
+							UpdatedBy: arg.UpdatedBy,
+							UpdatedAt: time.Now(),
+						}, nil
+					})
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.NoError(t, err)
+				limit := res.GetTransferLimit()
+				require.Equal(t, role, limit.GetRole())
+				require.Nil(t, limit.Username)
+				require.Nil(t, limit.AccountId)
+				require.Equal(t, maxAmount, limit.GetMaxAmount())
+				require.Equal(t, maxCount, limit.GetMaxCount())
+			},
+		},
+		{
+			name: "AccountOverride",
+			req: &pb.SetTransferLimitRequest{
+				AccountId: &account.ID,
+				Scope:     util.LimitScopeAccount,
+				Currency:  account.Currency,
+				Period:    util.LimitPeriodMonthly,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				// no maximums lift the limit for the account
+				arg := db.UpsertTransferLimitParams{
+					AccountID: pgtype.Int8{Int64: account.ID, Valid: true},
+					Scope:     util.LimitScopeAccount,
+					Currency:  account.Currency,
+					Period:    util.LimitPeriodMonthly,
+					UpdatedBy: banker.Username,
+				}
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferLimit{ID: 2, AccountID: arg.AccountID, Scope: arg.Scope}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.ID, res.GetTransferLimit().GetAccountId())
+				require.Nil(t, res.GetTransferLimit().MaxAmount)
+			},
+		},
+		{
+			name: "TwoSubjects",
+			req: &pb.SetTransferLimitRequest{
+				Role:      &role,
+				AccountId: &account.ID,
+				Scope:     util.LimitScopeAccount,
+				Currency:  account.Currency,
+				Period:    util.LimitPeriodDaily,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "UserOverrideWithAccountScope",
+			req: &pb.SetTransferLimitRequest{
+				Username: &depositor.Username,
+				Scope:    util.LimitScopeAccount,
+				Currency: util.USD,
+				Period:   util.LimitPeriodDaily,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req: &pb.SetTransferLimitRequest{
+				Username:  &depositor.Username,
+				Scope:     util.LimitScopeUser,
+				Currency:  util.USD,
+				Period:    util.LimitPeriodDaily,
+				MaxAmount: &maxAmount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, depositor.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.SetTransferLimitResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.SetTransferLimit(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) UpdateAccountProduct(ctx context.Context, req *pb.UpdateAccountProductRequest) (*pb.UpdateAccountProductResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateUpdateAccountProductRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// a new rate applies from the next accrual, days already accrued keep
+	// the rate they were accrued at
+	product, err := server.store.UpdateAccountProduct(ctx, db.UpdateAccountProductParams{
+		Code: req.GetCode(),
+		InterestRateBps: pgtype.Int4{
+			Int32: req.GetInterestRateBps(),
+			Valid: req.InterestRateBps != nil,
+		},
+		DayCount: pgtype.Text{
+			String: req.GetDayCount(),
+			Valid:  req.DayCount != nil,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account product not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update account product: %s", err)
+	}
+
+	rsp := &pb.UpdateAccountProductResponse{
+		AccountProduct: convertAccountProduct(product),
+	}
+
+	return rsp, nil
+}
+
+func validateUpdateAccountProductRequest(req *pb.UpdateAccountProductRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateAccountType(req.GetCode()); err != nil {
+		violations = append(violations, fieldViolation("code", err))
+	}
+
+	if req.InterestRateBps != nil {
+		if err := val.ValidateRateBps(req.GetInterestRateBps()); err != nil {
+			violations = append(violations, fieldViolation("interest_rate_bps", err))
+		}
+	}
+
+	if req.DayCount != nil {
+		if err := val.ValidateDayCount(req.GetDayCount()); err != nil {
+			violations = append(violations, fieldViolation("day_count", err))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,184 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcUpdateAccountProduct(t *testing.T) {
+	banker, _ := createRandomUser(t)
+	depositor, _ := createRandomUser(t)
+
+	rate := int32(200)
+	invalidRate := int32(10001)
+	dayCount := util.DayCount30360
+	invalidDayCount := "act_act"
+
+	savings := db.AccountProduct{
+		Code:            util.AccountTypeSavings,
+		Name:            "Savings",
+		InterestRateBps: rate,
+		DayCount:        util.DayCountActual365,
+		UpdatedAt:       time.Now(),
+	}
+
+	testCases := []struct {
+		name           string
+		req            *pb.UpdateAccountProductRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.UpdateAccountProductResponse, err error)
+	}{
+		{
+			name: "Rate",
+			req: &pb.UpdateAccountProductRequest{
+				Code:            util.AccountTypeSavings,
+				InterestRateBps: &rate,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateAccountProductParams{
+					Code:            util.AccountTypeSavings,
+					InterestRateBps: pgtype.Int4{Int32: rate, Valid: true},
+				}
+				store.EXPECT().UpdateAccountProduct(gomock.Any(), gomock.Eq(arg)).Times(1).Return(savings, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateAccountProductResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, rate, res.GetAccountProduct().GetInterestRateBps())
+				require.Equal(t, util.DayCountActual365, res.GetAccountProduct().GetDayCount())
+			},
+		},
+		{
+			name: "DayCount",
+			req: &pb.UpdateAccountProductRequest{
+				Code:     util.AccountTypeSavings,
+				DayCount: &dayCount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateAccountProductParams{
+					Code:     util.AccountTypeSavings,
+					DayCount: pgtype.Text{String: dayCount, Valid: true},
+				}
+				product := savings
+				product.DayCount = dayCount
+				store.EXPECT().UpdateAccountProduct(gomock.Any(), gomock.Eq(arg)).Times(1).Return(product, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateAccountProductResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, dayCount, res.GetAccountProduct().GetDayCount())
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req: &pb.UpdateAccountProductRequest{
+				Code:            util.AccountTypeSavings,
+				InterestRateBps: &rate,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, depositor.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateAccountProductResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "NotFound",
+			req: &pb.UpdateAccountProductRequest{
+				Code:            util.AccountTypeSavings,
+				InterestRateBps: &rate,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountProduct(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountProduct{}, db.ErrRecordNotFound)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateAccountProductResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "InvalidRate",
+			req: &pb.UpdateAccountProductRequest{
+				Code:            util.AccountTypeSavings,
+				InterestRateBps: &invalidRate,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateAccountProductResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "InvalidDayCount",
+			req: &pb.UpdateAccountProductRequest{
+				Code:     util.AccountTypeSavings,
+				DayCount: &invalidDayCount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountProduct(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateAccountProductResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.UpdateAccountProduct(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
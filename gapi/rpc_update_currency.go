@@ -0,0 +1,71 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) UpdateCurrency(ctx context.Context, req *pb.UpdateCurrencyRequest) (*pb.UpdateCurrencyResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateUpdateCurrencyRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	currency, err := server.store.UpdateCurrency(ctx, db.UpdateCurrencyParams{
+		Code: req.GetCode(),
+		Enabled: pgtype.Bool{
+			Bool:  req.GetEnabled(),
+			Valid: req.Enabled != nil,
+		},
+		ApprovalThreshold: pgtype.Int8{
+			Int64: req.GetApprovalThreshold(),
+			Valid: req.ApprovalThreshold != nil,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "currency not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update currency: %s", err)
+	}
+
+	// validation reads the registry, so reload it for the change to take effect
+	err = db.LoadCurrencyRegistry(ctx, server.store)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reload currencies: %s", err)
+	}
+
+	rsp := &pb.UpdateCurrencyResponse{
+		Currency: convertCurrency(currency),
+	}
+
+	return rsp, nil
+}
+
+func validateUpdateCurrencyRequest(req *pb.UpdateCurrencyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateCurrencyCode(req.GetCode()); err != nil {
+		violations = append(violations, fieldViolation("code", err))
+	}
+
+	if req.ApprovalThreshold != nil {
+		if err := val.ValidateApprovalThreshold(req.GetApprovalThreshold()); err != nil {
+			violations = append(violations, fieldViolation("approval_threshold", err))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,187 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcUpdateCurrency(t *testing.T) {
+	launched := util.Currencies()
+	defer util.SetCurrencies(launched)
+
+	banker, _ := createRandomUser(t)
+	depositor, _ := createRandomUser(t)
+
+	jpy := db.Currency{Code: util.JPY, NumericCode: "392", Name: "Yen", MinorUnits: 0, Enabled: true}
+	enabled := true
+	threshold := int64(1_000_000)
+	negativeThreshold := int64(-1)
+
+	testCases := []struct {
+		name           string
+		req            *pb.UpdateCurrencyRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.UpdateCurrencyResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.UpdateCurrencyRequest{
+				Code:    util.JPY,
+				Enabled: &enabled,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateCurrencyParams{
+					Code:    util.JPY,
+					Enabled: pgtype.Bool{Bool: true, Valid: true},
+				}
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Eq(arg)).Times(1).Return(jpy, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{jpy}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateCurrencyResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, res.GetCurrency().GetEnabled())
+				require.Zero(t, res.GetCurrency().GetMinorUnits())
+
+				// the registry picked up the change
+				require.True(t, util.IsCurrencySupported(util.JPY))
+				require.Equal(t, 0, util.MinorUnits(util.JPY))
+			},
+		},
+		{
+			name: "ApprovalThreshold",
+			req: &pb.UpdateCurrencyRequest{
+				Code:              util.JPY,
+				ApprovalThreshold: &threshold,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateCurrencyParams{
+					Code:              util.JPY,
+					ApprovalThreshold: pgtype.Int8{Int64: threshold, Valid: true},
+				}
+				updated := jpy
+				updated.ApprovalThreshold = threshold
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Eq(arg)).Times(1).Return(updated, nil)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(1).Return([]db.Currency{updated}, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateCurrencyResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, threshold, res.GetCurrency().GetApprovalThreshold())
+				require.True(t, util.RequiresApproval(util.JPY, threshold+1))
+			},
+		},
+		{
+			name: "NegativeApprovalThreshold",
+			req: &pb.UpdateCurrencyRequest{
+				Code:              util.JPY,
+				ApprovalThreshold: &negativeThreshold,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateCurrencyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+		{
+			name: "DepositorNotAllowed",
+			req: &pb.UpdateCurrencyRequest{
+				Code:    util.JPY,
+				Enabled: &enabled,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, depositor.Username, util.DepositorRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateCurrencyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+		{
+			name: "NotFound",
+			req: &pb.UpdateCurrencyRequest{
+				Code:    "XYZ",
+				Enabled: &enabled,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Any()).Times(1).Return(db.Currency{}, db.ErrRecordNotFound)
+				store.EXPECT().ListCurrencies(gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateCurrencyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "InvalidCode",
+			req: &pb.UpdateCurrencyRequest{
+				Code:    "usd",
+				Enabled: &enabled,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCurrency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, util.BankerRole, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.UpdateCurrencyResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.InvalidArgument, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.UpdateCurrency(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
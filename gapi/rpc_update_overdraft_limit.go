@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/billy-le/simple-bank/val"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) UpdateOverdraftLimit(ctx context.Context, req *pb.UpdateOverdraftLimitRequest) (*pb.UpdateOverdraftLimitResponse, error) {
+	_, err := server.authorizeUser(ctx, []string{util.BankerRole})
+	if err != nil {
+		return nil, unauthenticatedError(err)
+	}
+
+	violations := validateUpdateOverdraftLimitRequest(req)
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.UpdateAccountOverdraftLimit(ctx, db.UpdateAccountOverdraftLimitParams{
+		ID:             req.GetAccountId(),
+		OverdraftLimit: req.GetOverdraftLimit(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account not found: %s", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update overdraft limit: %s", err)
+	}
+
+	rsp := &pb.UpdateOverdraftLimitResponse{
+		Account: convertAccount(account),
+	}
+
+	return rsp, nil
+}
+
+func validateUpdateOverdraftLimitRequest(req *pb.UpdateOverdraftLimitRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := val.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := val.ValidateOverdraftLimit(req.GetOverdraftLimit()); err != nil {
+		violations = append(violations, fieldViolation("overdraft_limit", err))
+	}
+
+	return violations
+}
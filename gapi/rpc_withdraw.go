@@ -0,0 +1,23 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/billy-le/simple-bank/pb"
+)
+
+func (server *Server) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	txResult, err := server.moveCash(ctx, req.GetAccountId(), req.GetAmount(), req.GetCurrency(), server.store.WithdrawTx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.WithdrawResponse{
+		Transfer: convertTransfer(txResult.Transfer),
+		Account:  convertAccount(txResult.Account),
+		Entry:    convertEntry(txResult.Entry),
+		Fees:     convertTransfers(txResult.Fees),
+	}
+
+	return rsp, nil
+}
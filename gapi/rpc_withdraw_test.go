@@ -0,0 +1,184 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/pb"
+	"github.com/billy-le/simple-bank/token"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRpcWithdraw(t *testing.T) {
+	amount := int64(100)
+
+	user, _ := createRandomUser(t)
+	banker, _ := createRandomUser(t)
+	banker.Role = util.BankerRole
+
+	account := createRandomAccount(user.Username)
+	account.Currency = util.USD
+
+	testCases := []struct {
+		name           string
+		req            *pb.WithdrawRequest
+		buildStubs     func(store *mockdb.MockStore)
+		buildContext   func(t *testing.T, tokenMaker token.Maker) context.Context
+		checkResponses func(t *testing.T, res *pb.WithdrawResponse, err error)
+	}{
+		{
+			name: "Ok",
+			req: &pb.WithdrawRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.CashTxParams{
+					AccountID: account.ID,
+					Amount:    amount,
+					Username:  banker.Username,
+				}
+				result := db.CashTxResult{
+					Transfer: db.Transfer{ID: 1, FromAccountID: account.ID, Amount: amount},
+					Account:  account,
+					Entry:    db.Entry{ID: 1, AccountID: account.ID, Amount: -amount},
+				}
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.WithdrawResponse, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, res)
+				require.Equal(t, amount, res.GetTransfer().Amount)
+				require.Equal(t, account.ID, res.GetAccount().Id)
+				require.Equal(t, -amount, res.GetEntry().Amount)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			req: &pb.WithdrawRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, db.ErrInsufficientFunds)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.WithdrawResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+			},
+		},
+		{
+			name: "TransferLimitExceeded",
+			req: &pb.WithdrawRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				limitErr := &db.TransferLimitError{
+					AccountID: account.ID,
+					Owner:     user.Username,
+					Usage: db.TransferUsage{
+						Scope:    util.LimitScopeAccount,
+						Period:   util.LimitPeriodDaily,
+						Currency: util.USD,
+						Count:    1,
+						ResetsAt: time.Now().Add(time.Hour),
+					},
+				}
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CashTxResult{}, limitErr)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.WithdrawResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.ResourceExhausted, st.Code())
+			},
+		},
+		{
+			name: "AccountNotFound",
+			req: &pb.WithdrawRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, banker.Username, banker.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.WithdrawResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.NotFound, st.Code())
+			},
+		},
+		{
+			name: "WithdrawerNotBanker",
+			req: &pb.WithdrawRequest{
+				AccountId: account.ID,
+				Amount:    amount,
+				Currency:  util.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().WithdrawTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			buildContext: func(t *testing.T, tokenMaker token.Maker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, user.Username, user.Role, time.Minute)
+			},
+			checkResponses: func(t *testing.T, res *pb.WithdrawResponse, err error) {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				require.Equal(t, codes.Unauthenticated, st.Code())
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			server := newTestServer(t, store, nil)
+
+			ctx := testCase.buildContext(t, server.tokenMaker)
+			res, err := server.Withdraw(ctx, testCase.req)
+
+			testCase.checkResponses(t, res, err)
+		})
+	}
+}
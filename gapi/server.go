@@ -15,6 +15,7 @@ type Server struct {
 	store           db.Store
 	config          util.Config
 	tokenMaker      token.Maker
+	pageTokenMaker  *util.PageTokenMaker
 	taskDistributor worker.TaskDistributor
 }
 
@@ -24,7 +25,13 @@ func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDi
 		return nil, fmt.Errorf("cannot create token mater: %w", err)
 	}
 
-	server := &Server{store: store, tokenMaker: tokenMaker, config: config, taskDistributor: taskDistributor}
+	server := &Server{
+		store:           store,
+		tokenMaker:      tokenMaker,
+		pageTokenMaker:  util.NewPageTokenMaker(config.TokenSymmetricKey),
+		config:          config,
+		taskDistributor: taskDistributor,
+	}
 
 	return server, nil
 }
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"time"
 
 	db "github.com/billy-le/simple-bank/db/sqlc"
 
@@ -30,6 +32,8 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
+const currencyRefreshInterval = time.Minute
+
 func main() {
 	config, err := util.LoadConfig(".")
 
@@ -40,21 +44,41 @@ func main() {
 	if err != nil {
 		log.Fatal().Msg("cannot load config")
 	}
+
+	if config.TransferRequestDuration <= 0 {
+		log.Fatal().Msg("TRANSFER_REQUEST_DURATION must be positive")
+	}
+
 	conn, err := pgxpool.New(context.Background(), config.DBSource)
 	if err != nil {
 		log.Fatal().Msg("cannot connect to db")
 	}
 
 	store := db.NewStore(conn)
+
+	// `main reconcile` checks the ledger once and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		os.Exit(runReconcile(store))
+	}
+
 	runDBMigration(config.MigrationURL, config.DBSource)
 
+	err = db.LoadCurrencyRegistry(context.Background(), store)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot load currencies")
+	}
+	go runCurrencyRefresher(store)
+
 	redisOpts := asynq.RedisClientOpt{
 		Addr: config.RedisServerAddress,
 	}
 
 	taskDistributor := worker.NewRedisTaskDistributor(redisOpts)
 
-	go runTaskProcessor(config, redisOpts, store)
+	go runTaskProcessor(config, redisOpts, store, taskDistributor)
+	if config.TaskSchedulerEnabled {
+		go runTaskScheduler(redisOpts)
+	}
 	go runGatewayServer(config, store, taskDistributor)
 	runGrpcServer(config, store, taskDistributor)
 }
@@ -72,9 +96,49 @@ func runDBMigration(migrationURL string, dbSource string) {
 	log.Info().Msg("db migrated successfully")
 }
 
-func runTaskProcessor(config util.Config, redisOpt asynq.RedisClientOpt, store db.Store) {
+// runCurrencyRefresher picks up currencies enabled or disabled by another
+// instance of the server
+func runCurrencyRefresher(store db.Store) {
+	ticker := time.NewTicker(currencyRefreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := db.LoadCurrencyRegistry(context.Background(), store)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to refresh currencies")
+		}
+	}
+}
+
+// runReconcile prints every ledger discrepancy on its own line and returns
+// the exit code, which is 1 when the ledger is out of balance
+func runReconcile(store db.Store) int {
+	report, err := db.CheckLedger(context.Background(), store)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to check ledger")
+		return 2
+	}
+
+	for _, discrepancy := range report.Discrepancies() {
+		fmt.Println(discrepancy)
+	}
+
+	if !report.Balanced() {
+		log.Error().
+			Int("accounts", len(report.AccountMismatches)).
+			Int("transfers", len(report.TransferMismatches)).
+			Int("journals", len(report.JournalMismatches)).
+			Msg("ledger is out of balance")
+		return 1
+	}
+
+	log.Info().Msg("ledger is balanced")
+	return 0
+}
+
+func runTaskProcessor(config util.Config, redisOpt asynq.RedisClientOpt, store db.Store, taskDistributor worker.TaskDistributor) {
 	mailer := mail.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer)
+	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer, taskDistributor)
 	log.Info().Msg("start task processor")
 	err := taskProcessor.Start()
 	if err != nil {
@@ -82,6 +146,15 @@ func runTaskProcessor(config util.Config, redisOpt asynq.RedisClientOpt, store d
 	}
 }
 
+func runTaskScheduler(redisOpt asynq.RedisClientOpt) {
+	taskScheduler := worker.NewRedisTaskScheduler(redisOpt)
+	log.Info().Msg("start task scheduler")
+	err := taskScheduler.Start()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to start task scheduler")
+	}
+}
+
 func runGrpcServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) {
 	server, err := gapi.NewServer(config, store, taskDistributor)
 	if err != nil {
@@ -119,7 +192,9 @@ func runGatewayServer(config util.Config, store db.Store, taskDistributor worker
 		},
 	})
 
-	grpcMux := runtime.NewServeMux(jsonOption)
+	headerMatcher := runtime.WithIncomingHeaderMatcher(gapi.IncomingHeaderMatcher)
+
+	grpcMux := runtime.NewServeMux(jsonOption, headerMatcher)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -129,6 +204,11 @@ func runGatewayServer(config util.Config, store db.Store, taskDistributor worker
 		log.Fatal().Msg("cannot register handler server")
 	}
 
+	err = grpcMux.HandlePath(http.MethodGet, gapi.ExportStatementPath, server.ExportStatementHandler(grpcMux))
+	if err != nil {
+		log.Fatal().Msg("cannot register statement export handler")
+	}
+
 	mux := http.NewServeMux()
 	mux.Handle("/", grpcMux)
 
@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.25.1
+// source: rpc_list_fee_schedules.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListFeeSchedulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListFeeSchedulesRequest) Reset() {
+	*x = ListFeeSchedulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_list_fee_schedules_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFeeSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeeSchedulesRequest) ProtoMessage() {}
+
+func (x *ListFeeSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_fee_schedules_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeeSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListFeeSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_list_fee_schedules_proto_rawDescGZIP(), []int{0}
+}
+
+type ListFeeSchedulesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FeeSchedules []*FeeSchedule `protobuf:"bytes,1,rep,name=fee_schedules,json=feeSchedules,proto3" json:"fee_schedules,omitempty"`
+}
+
+func (x *ListFeeSchedulesResponse) Reset() {
+	*x = ListFeeSchedulesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_list_fee_schedules_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFeeSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeeSchedulesResponse) ProtoMessage() {}
+
+func (x *ListFeeSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_fee_schedules_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeeSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListFeeSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_list_fee_schedules_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListFeeSchedulesResponse) GetFeeSchedules() []*FeeSchedule {
+	if x != nil {
+		return x.FeeSchedules
+	}
+	return nil
+}
+
+var File_rpc_list_fee_schedules_proto protoreflect.FileDescriptor
+
+var file_rpc_list_fee_schedules_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x73,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x70, 0x62, 0x1a, 0x12, 0x66, 0x65, 0x65, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x19, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65,
+	0x65, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0x50, 0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x65, 0x65, 0x53, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a,
+	0x0d, 0x66, 0x65, 0x65, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x46, 0x65, 0x65, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x0c, 0x66, 0x65, 0x65, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x73, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x62, 0x69, 0x6c, 0x6c, 0x79, 0x2d, 0x6c, 0x65, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c,
+	0x65, 0x2d, 0x62, 0x61, 0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_rpc_list_fee_schedules_proto_rawDescOnce sync.Once
+	file_rpc_list_fee_schedules_proto_rawDescData = file_rpc_list_fee_schedules_proto_rawDesc
+)
+
+func file_rpc_list_fee_schedules_proto_rawDescGZIP() []byte {
+	file_rpc_list_fee_schedules_proto_rawDescOnce.Do(func() {
+		file_rpc_list_fee_schedules_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_list_fee_schedules_proto_rawDescData)
+	})
+	return file_rpc_list_fee_schedules_proto_rawDescData
+}
+
+var file_rpc_list_fee_schedules_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_list_fee_schedules_proto_goTypes = []interface{}{
+	(*ListFeeSchedulesRequest)(nil),  // 0: pb.ListFeeSchedulesRequest
+	(*ListFeeSchedulesResponse)(nil), // 1: pb.ListFeeSchedulesResponse
+	(*FeeSchedule)(nil),              // 2: pb.FeeSchedule
+}
+var file_rpc_list_fee_schedules_proto_depIdxs = []int32{
+	2, // 0: pb.ListFeeSchedulesResponse.fee_schedules:type_name -> pb.FeeSchedule
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_list_fee_schedules_proto_init() }
+func file_rpc_list_fee_schedules_proto_init() {
+	if File_rpc_list_fee_schedules_proto != nil {
+		return
+	}
+	file_fee_schedule_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_list_fee_schedules_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFeeSchedulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_list_fee_schedules_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFeeSchedulesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_list_fee_schedules_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_list_fee_schedules_proto_goTypes,
+		DependencyIndexes: file_rpc_list_fee_schedules_proto_depIdxs,
+		MessageInfos:      file_rpc_list_fee_schedules_proto_msgTypes,
+	}.Build()
+	File_rpc_list_fee_schedules_proto = out.File
+	file_rpc_list_fee_schedules_proto_rawDesc = nil
+	file_rpc_list_fee_schedules_proto_goTypes = nil
+	file_rpc_list_fee_schedules_proto_depIdxs = nil
+}
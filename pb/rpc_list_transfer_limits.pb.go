@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.25.1
+// source: rpc_list_transfer_limits.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListTransferLimitsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListTransferLimitsRequest) Reset() {
+	*x = ListTransferLimitsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_list_transfer_limits_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTransferLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransferLimitsRequest) ProtoMessage() {}
+
+func (x *ListTransferLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_transfer_limits_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransferLimitsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransferLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_list_transfer_limits_proto_rawDescGZIP(), []int{0}
+}
+
+type ListTransferLimitsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransferLimits []*TransferLimit `protobuf:"bytes,1,rep,name=transfer_limits,json=transferLimits,proto3" json:"transfer_limits,omitempty"`
+}
+
+func (x *ListTransferLimitsResponse) Reset() {
+	*x = ListTransferLimitsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_list_transfer_limits_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTransferLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransferLimitsResponse) ProtoMessage() {}
+
+func (x *ListTransferLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_list_transfer_limits_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransferLimitsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransferLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_list_transfer_limits_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListTransferLimitsResponse) GetTransferLimits() []*TransferLimit {
+	if x != nil {
+		return x.TransferLimits
+	}
+	return nil
+}
+
+var File_rpc_list_transfer_limits_proto protoreflect.FileDescriptor
+
+var file_rpc_list_transfer_limits_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x72, 0x70, 0x63, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x65, 0x72, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x02, 0x70, 0x62, 0x1a, 0x14, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x1b, 0x0a, 0x19, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x58, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x62, 0x69, 0x6c, 0x6c, 0x79, 0x2d, 0x6c, 0x65, 0x2f, 0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x2d,
+	0x62, 0x61, 0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_list_transfer_limits_proto_rawDescOnce sync.Once
+	file_rpc_list_transfer_limits_proto_rawDescData = file_rpc_list_transfer_limits_proto_rawDesc
+)
+
+func file_rpc_list_transfer_limits_proto_rawDescGZIP() []byte {
+	file_rpc_list_transfer_limits_proto_rawDescOnce.Do(func() {
+		file_rpc_list_transfer_limits_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_list_transfer_limits_proto_rawDescData)
+	})
+	return file_rpc_list_transfer_limits_proto_rawDescData
+}
+
+var file_rpc_list_transfer_limits_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_list_transfer_limits_proto_goTypes = []interface{}{
+	(*ListTransferLimitsRequest)(nil),  // 0: pb.ListTransferLimitsRequest
+	(*ListTransferLimitsResponse)(nil), // 1: pb.ListTransferLimitsResponse
+	(*TransferLimit)(nil),              // 2: pb.TransferLimit
+}
+var file_rpc_list_transfer_limits_proto_depIdxs = []int32{
+	2, // 0: pb.ListTransferLimitsResponse.transfer_limits:type_name -> pb.TransferLimit
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_list_transfer_limits_proto_init() }
+func file_rpc_list_transfer_limits_proto_init() {
+	if File_rpc_list_transfer_limits_proto != nil {
+		return
+	}
+	file_transfer_limit_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_list_transfer_limits_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTransferLimitsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_list_transfer_limits_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTransferLimitsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_list_transfer_limits_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_list_transfer_limits_proto_goTypes,
+		DependencyIndexes: file_rpc_list_transfer_limits_proto_depIdxs,
+		MessageInfos:      file_rpc_list_transfer_limits_proto_msgTypes,
+	}.Build()
+	File_rpc_list_transfer_limits_proto = out.File
+	file_rpc_list_transfer_limits_proto_rawDesc = nil
+	file_rpc_list_transfer_limits_proto_goTypes = nil
+	file_rpc_list_transfer_limits_proto_depIdxs = nil
+}
@@ -0,0 +1,225 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.25.1
+// source: rpc_reject_transfer_request.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RejectTransferRequestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *RejectTransferRequestRequest) Reset() {
+	*x = RejectTransferRequestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_reject_transfer_request_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RejectTransferRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectTransferRequestRequest) ProtoMessage() {}
+
+func (x *RejectTransferRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_reject_transfer_request_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectTransferRequestRequest.ProtoReflect.Descriptor instead.
+func (*RejectTransferRequestRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_reject_transfer_request_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RejectTransferRequestRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RejectTransferRequestRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RejectTransferRequestResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TransferRequest *TransferRequest `protobuf:"bytes,1,opt,name=transfer_request,json=transferRequest,proto3" json:"transfer_request,omitempty"`
+}
+
+func (x *RejectTransferRequestResponse) Reset() {
+	*x = RejectTransferRequestResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_reject_transfer_request_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RejectTransferRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectTransferRequestResponse) ProtoMessage() {}
+
+func (x *RejectTransferRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_reject_transfer_request_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectTransferRequestResponse.ProtoReflect.Descriptor instead.
+func (*RejectTransferRequestResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_reject_transfer_request_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RejectTransferRequestResponse) GetTransferRequest() *TransferRequest {
+	if x != nil {
+		return x.TransferRequest
+	}
+	return nil
+}
+
+var File_rpc_reject_transfer_request_proto protoreflect.FileDescriptor
+
+var file_rpc_reject_transfer_request_proto_rawDesc = []byte{
+	0x0a, 0x21, 0x72, 0x70, 0x63, 0x5f, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x16, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x46, 0x0a, 0x1c, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x5f, 0x0a, 0x1d, 0x52, 0x65, 0x6a, 0x65, 0x63,
+	0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x10, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x69, 0x6c, 0x6c, 0x79, 0x2d, 0x6c, 0x65, 0x2f,
+	0x73, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x2d, 0x62, 0x61, 0x6e, 0x6b, 0x2f, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_reject_transfer_request_proto_rawDescOnce sync.Once
+	file_rpc_reject_transfer_request_proto_rawDescData = file_rpc_reject_transfer_request_proto_rawDesc
+)
+
+func file_rpc_reject_transfer_request_proto_rawDescGZIP() []byte {
+	file_rpc_reject_transfer_request_proto_rawDescOnce.Do(func() {
+		file_rpc_reject_transfer_request_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_reject_transfer_request_proto_rawDescData)
+	})
+	return file_rpc_reject_transfer_request_proto_rawDescData
+}
+
+var file_rpc_reject_transfer_request_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_reject_transfer_request_proto_goTypes = []interface{}{
+	(*RejectTransferRequestRequest)(nil),  // 0: pb.RejectTransferRequestRequest
+	(*RejectTransferRequestResponse)(nil), // 1: pb.RejectTransferRequestResponse
+	(*TransferRequest)(nil),               // 2: pb.TransferRequest
+}
+var file_rpc_reject_transfer_request_proto_depIdxs = []int32{
+	2, // 0: pb.RejectTransferRequestResponse.transfer_request:type_name -> pb.TransferRequest
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_rpc_reject_transfer_request_proto_init() }
+func file_rpc_reject_transfer_request_proto_init() {
+	if File_rpc_reject_transfer_request_proto != nil {
+		return
+	}
+	file_transfer_request_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_reject_transfer_request_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RejectTransferRequestRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_reject_transfer_request_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RejectTransferRequestResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_reject_transfer_request_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rpc_reject_transfer_request_proto_goTypes,
+		DependencyIndexes: file_rpc_reject_transfer_request_proto_depIdxs,
+		MessageInfos:      file_rpc_reject_transfer_request_proto_msgTypes,
+	}.Build()
+	File_rpc_reject_transfer_request_proto = out.File
+	file_rpc_reject_transfer_request_proto_rawDesc = nil
+	file_rpc_reject_transfer_request_proto_goTypes = nil
+	file_rpc_reject_transfer_request_proto_depIdxs = nil
+}
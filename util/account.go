@@ -0,0 +1,22 @@
+package util
+
+// Account lifecycle states
+const (
+	AccountStatusActive  = "active"
+	AccountStatusFrozen  = "frozen"
+	AccountStatusDormant = "dormant"
+	AccountStatusClosed  = "closed"
+)
+
+// DormantAfterMonths is how long a customer account can go without activity
+// before the bank marks it dormant
+const DormantAfterMonths = 12
+
+// Roles a user can have on an account. Transferors may send money from the
+// account up to a limit on each transfer.
+const (
+	AccountMemberOwner      = "owner"
+	AccountMemberCoOwner    = "co_owner"
+	AccountMemberViewer     = "viewer"
+	AccountMemberTransferor = "transferor"
+)
@@ -7,18 +7,22 @@ import (
 )
 
 type Config struct {
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	MigrationURL         string        `mapstructure:"MIGRATION_URL"`
-	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
-	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
-	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
-	Environment          string        `mapstructure:"ENVIRONMENT"`
-	RedisServerAddress   string        `mapstructure:"REDIS_SERVER_ADDRESS"`
-	EmailSenderName      string        `mapstructure:"EMAIL_SENDER_NAME"`
-	EmailSenderAddress   string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
-	EmailSenderPassword  string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
+	DBSource                string        `mapstructure:"DB_SOURCE"`
+	MigrationURL            string        `mapstructure:"MIGRATION_URL"`
+	HTTPServerAddress       string        `mapstructure:"HTTP_SERVER_ADDRESS"`
+	GRPCServerAddress       string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	TokenSymmetricKey       string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration     time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration    time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	Environment             string        `mapstructure:"ENVIRONMENT"`
+	RedisServerAddress      string        `mapstructure:"REDIS_SERVER_ADDRESS"`
+	EmailSenderName         string        `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderAddress      string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	EmailSenderPassword     string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
+	TransferRequestDuration time.Duration `mapstructure:"TRANSFER_REQUEST_DURATION"`
+	// TaskSchedulerEnabled is set on the one instance that queues the
+	// periodic tasks, every other instance would queue them again
+	TaskSchedulerEnabled bool `mapstructure:"TASK_SCHEDULER_ENABLED"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -28,6 +32,9 @@ func LoadConfig(path string) (config Config, err error) {
 
 	viper.AutomaticEnv()
 
+	// a request has to outlive the time it takes a banker to get to it
+	viper.SetDefault("TRANSFER_REQUEST_DURATION", 72*time.Hour)
+
 	err = viper.ReadInConfig()
 	if err != nil {
 		return
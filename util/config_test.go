@@ -0,0 +1,22 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigDefaults(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.env"), []byte("ENVIRONMENT=test\n"), 0o600)
+	require.NoError(t, err)
+
+	config, err := LoadConfig(dir)
+	require.NoError(t, err)
+	require.Equal(t, "test", config.Environment)
+	require.Equal(t, 72*time.Hour, config.TransferRequestDuration)
+	require.False(t, config.TaskSchedulerEnabled)
+}
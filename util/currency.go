@@ -1,15 +1,98 @@
 package util
 
+import (
+	"sort"
+	"sync"
+)
+
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
+	JPY = "JPY"
+	BHD = "BHD"
 )
 
-func IsCurrencySupported(currency string) bool {
-	switch currency {
-	case USD, EUR, CAD:
-		return true
+// Currency is an ISO 4217 currency as held in the currencies table
+type Currency struct {
+	Code        string
+	NumericCode string
+	Name        string
+	// MinorUnits is the number of decimal places of the currency, which is how
+	// many digits of an amount stored in the smallest unit are after the point
+	MinorUnits int
+	Enabled    bool
+	// ApprovalThreshold is the amount above which a transfer waits for a
+	// banker's approval, or 0 when transfers never do
+	ApprovalThreshold int64
+}
+
+// currencyRegistry starts out with the currencies the bank launched with, so
+// code that runs before SetCurrencies (and tests) still sees them
+var currencyRegistry = struct {
+	sync.RWMutex
+	currencies map[string]Currency
+}{
+	currencies: map[string]Currency{
+		USD: {Code: USD, NumericCode: "840", Name: "US Dollar", MinorUnits: 2, Enabled: true},
+		EUR: {Code: EUR, NumericCode: "978", Name: "Euro", MinorUnits: 2, Enabled: true},
+		CAD: {Code: CAD, NumericCode: "124", Name: "Canadian Dollar", MinorUnits: 2, Enabled: true},
+	},
+}
+
+// SetCurrencies replaces the registry with the given currencies
+func SetCurrencies(currencies []Currency) {
+	registry := make(map[string]Currency, len(currencies))
+	for _, currency := range currencies {
+		registry[currency.Code] = currency
+	}
+
+	currencyRegistry.Lock()
+	defer currencyRegistry.Unlock()
+	currencyRegistry.currencies = registry
+}
+
+// Currencies returns every currency in the registry, ordered by code
+func Currencies() []Currency {
+	currencyRegistry.RLock()
+	defer currencyRegistry.RUnlock()
+
+	currencies := make([]Currency, 0, len(currencyRegistry.currencies))
+	for _, currency := range currencyRegistry.currencies {
+		currencies = append(currencies, currency)
 	}
-	return false
+	sort.Slice(currencies, func(i, j int) bool {
+		return currencies[i].Code < currencies[j].Code
+	})
+	return currencies
+}
+
+func LookupCurrency(code string) (Currency, bool) {
+	currencyRegistry.RLock()
+	defer currencyRegistry.RUnlock()
+
+	currency, ok := currencyRegistry.currencies[code]
+	return currency, ok
+}
+
+// IsCurrencySupported reports whether new accounts and transfers may use the currency
+func IsCurrencySupported(code string) bool {
+	currency, ok := LookupCurrency(code)
+	return ok && currency.Enabled
+}
+
+// MinorUnits returns the number of decimal places of a currency, defaulting
+// to 2 for a currency missing from the registry
+func MinorUnits(code string) int {
+	if currency, ok := LookupCurrency(code); ok {
+		return currency.MinorUnits
+	}
+	return 2
+}
+
+// RequiresApproval reports whether a transfer of amount in the currency must be
+// approved by a banker before it is made
+func RequiresApproval(code string, amount int64) bool {
+	currency, ok := LookupCurrency(code)
+	return ok && currency.ApprovalThreshold > 0 && amount > currency.ApprovalThreshold
 }
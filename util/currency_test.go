@@ -0,0 +1,51 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrencyRegistry(t *testing.T) {
+	launched := Currencies()
+	defer SetCurrencies(launched)
+
+	require.True(t, IsCurrencySupported(USD))
+	require.False(t, IsCurrencySupported(JPY))
+	require.Equal(t, 2, MinorUnits(JPY))
+
+	SetCurrencies([]Currency{
+		{Code: USD, NumericCode: "840", MinorUnits: 2, Enabled: true},
+		{Code: JPY, NumericCode: "392", MinorUnits: 0, Enabled: true},
+		{Code: BHD, NumericCode: "048", MinorUnits: 3, Enabled: false},
+	})
+
+	require.True(t, IsCurrencySupported(JPY))
+	require.False(t, IsCurrencySupported(BHD))
+	require.False(t, IsCurrencySupported(EUR))
+	require.Equal(t, 0, MinorUnits(JPY))
+	require.Equal(t, 3, MinorUnits(BHD))
+
+	currencies := Currencies()
+	require.Len(t, currencies, 3)
+	require.Equal(t, BHD, currencies[0].Code)
+
+	currency, ok := LookupCurrency(BHD)
+	require.True(t, ok)
+	require.Equal(t, "048", currency.NumericCode)
+}
+
+func TestRequiresApproval(t *testing.T) {
+	launched := Currencies()
+	defer SetCurrencies(launched)
+
+	SetCurrencies([]Currency{
+		{Code: USD, NumericCode: "840", MinorUnits: 2, Enabled: true, ApprovalThreshold: 1_000_000},
+		{Code: EUR, NumericCode: "978", MinorUnits: 2, Enabled: true},
+	})
+
+	require.False(t, RequiresApproval(USD, 1_000_000))
+	require.True(t, RequiresApproval(USD, 1_000_001))
+	require.False(t, RequiresApproval(EUR, 1_000_000_000))
+	require.False(t, RequiresApproval(JPY, 1_000_000_000))
+}
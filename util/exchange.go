@@ -0,0 +1,41 @@
+package util
+
+import (
+	"errors"
+	"math/big"
+)
+
+var ErrConversionOverflow = errors.New("converted amount is out of range")
+
+// ConvertAmount converts an amount in the minor unit of currency from into
+// the minor unit of currency to, at rate units of to per unit of from, less
+// spreadBps basis points.
+// The result is rounded down to the minor unit of the destination currency,
+// so a conversion never credits more than the rate covers.
+func ConvertAmount(amount int64, from string, to string, rate *big.Rat, spreadBps int32) (int64, error) {
+	converted := new(big.Rat).SetInt64(amount)
+	converted.Mul(converted, rate)
+	converted.Mul(converted, big.NewRat(10000-int64(spreadBps), 10000))
+
+	exponent := MinorUnits(to) - MinorUnits(from)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exponent))), nil)
+	if exponent > 0 {
+		converted.Mul(converted, new(big.Rat).SetInt(scale))
+	} else if exponent < 0 {
+		converted.Quo(converted, new(big.Rat).SetInt(scale))
+	}
+
+	result := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if !result.IsInt64() {
+		return 0, ErrConversionOverflow
+	}
+
+	return result.Int64(), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
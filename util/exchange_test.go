@@ -0,0 +1,80 @@
+package util
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertAmount(t *testing.T) {
+	testCases := []struct {
+		name      string
+		amount    int64
+		rate      *big.Rat
+		spreadBps int32
+		expected  int64
+	}{
+		{
+			name:     "SameScale",
+			amount:   10000,
+			rate:     big.NewRat(135, 100),
+			expected: 13500,
+		},
+		{
+			name:      "Spread",
+			amount:    10000,
+			rate:      big.NewRat(135, 100),
+			spreadBps: 100,
+			expected:  13365,
+		},
+		{
+			name:     "RoundsDown",
+			amount:   1,
+			rate:     big.NewRat(9, 10),
+			expected: 0,
+		},
+		{
+			name:     "RoundsDownFraction",
+			amount:   333,
+			rate:     big.NewRat(1, 3),
+			expected: 111,
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			converted, err := ConvertAmount(testCase.amount, USD, EUR, testCase.rate, testCase.spreadBps)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, converted)
+		})
+	}
+}
+
+func TestConvertAmountOverflow(t *testing.T) {
+	_, err := ConvertAmount(math.MaxInt64, USD, EUR, big.NewRat(2, 1), 0)
+	require.ErrorIs(t, err, ErrConversionOverflow)
+}
+
+func TestConvertAmountMinorUnits(t *testing.T) {
+	launched := Currencies()
+	defer SetCurrencies(launched)
+
+	SetCurrencies([]Currency{
+		{Code: USD, MinorUnits: 2, Enabled: true},
+		{Code: JPY, MinorUnits: 0, Enabled: true},
+		{Code: BHD, MinorUnits: 3, Enabled: true},
+	})
+
+	// 10.00 USD at 150 JPY per USD
+	converted, err := ConvertAmount(1000, USD, JPY, big.NewRat(150, 1), 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1500), converted)
+
+	// 1500 JPY at 0.0025 BHD per JPY is 3.750 BHD
+	converted, err = ConvertAmount(1500, JPY, BHD, big.NewRat(25, 10000), 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(3750), converted)
+}
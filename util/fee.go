@@ -0,0 +1,49 @@
+package util
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Kinds of money movement a fee schedule can price
+const (
+	TransferTypeTransfer      = "transfer"
+	TransferTypeCrossCurrency = "cross_currency"
+	TransferTypeDeposit       = "deposit"
+	TransferTypeWithdrawal    = "withdrawal"
+)
+
+var ErrFeeOverflow = errors.New("fee is out of range")
+
+// FeeRule prices a transfer as a flat fee plus a percentage of its amount,
+// kept between a minimum and an optional maximum
+type FeeRule struct {
+	FlatFee int64
+	RateBps int32
+	MinFee  int64
+	// MaxFee caps the fee when HasMaxFee is set
+	MaxFee    int64
+	HasMaxFee bool
+}
+
+// Fee returns the fee for amount. The percentage part is rounded up to the
+// minor unit, so a rate never charges less than it states.
+func (rule FeeRule) Fee(amount int64) (int64, error) {
+	fee := new(big.Int).Mul(big.NewInt(amount), big.NewInt(int64(rule.RateBps)))
+	fee.Add(fee, big.NewInt(9999))
+	fee.Quo(fee, big.NewInt(10000))
+	fee.Add(fee, big.NewInt(rule.FlatFee))
+
+	if fee.Cmp(big.NewInt(rule.MinFee)) < 0 {
+		return rule.MinFee, nil
+	}
+	if rule.HasMaxFee && fee.Cmp(big.NewInt(rule.MaxFee)) > 0 {
+		return rule.MaxFee, nil
+	}
+
+	if !fee.IsInt64() {
+		return 0, ErrFeeOverflow
+	}
+
+	return fee.Int64(), nil
+}
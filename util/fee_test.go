@@ -0,0 +1,79 @@
+package util
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeRuleFee(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     FeeRule
+		amount   int64
+		expected int64
+	}{
+		{
+			name:     "Flat",
+			rule:     FeeRule{FlatFee: 150},
+			amount:   10000,
+			expected: 150,
+		},
+		{
+			name:     "Percentage",
+			rule:     FeeRule{RateBps: 250},
+			amount:   10000,
+			expected: 250,
+		},
+		{
+			name:     "PercentageRoundsUp",
+			rule:     FeeRule{RateBps: 100},
+			amount:   150,
+			expected: 2,
+		},
+		{
+			name:     "FlatPlusPercentage",
+			rule:     FeeRule{FlatFee: 30, RateBps: 290},
+			amount:   10000,
+			expected: 320,
+		},
+		{
+			name:     "MinFee",
+			rule:     FeeRule{RateBps: 100, MinFee: 50},
+			amount:   1000,
+			expected: 50,
+		},
+		{
+			name:     "MaxFee",
+			rule:     FeeRule{RateBps: 100, MaxFee: 500, HasMaxFee: true},
+			amount:   1000000,
+			expected: 500,
+		},
+		{
+			name:     "ZeroMaxFee",
+			rule:     FeeRule{FlatFee: 100, HasMaxFee: true},
+			amount:   1000,
+			expected: 0,
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			fee, err := testCase.rule.Fee(testCase.amount)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, fee)
+		})
+	}
+}
+
+func TestFeeRuleFeeOverflow(t *testing.T) {
+	_, err := FeeRule{RateBps: 10000, FlatFee: 1}.Fee(math.MaxInt64)
+	require.ErrorIs(t, err, ErrFeeOverflow)
+
+	// a cap keeps the fee in range
+	fee, err := FeeRule{RateBps: 10000, FlatFee: 1, MaxFee: 1000, HasMaxFee: true}.Fee(math.MaxInt64)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), fee)
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"errors"
+	"math/big"
+	"time"
+)
+
+// Account products
+const (
+	AccountTypeChecking = "checking"
+	AccountTypeSavings  = "savings"
+)
+
+// Day-count conventions an account product can accrue interest under
+const (
+	DayCountActual365 = "act_365"
+	DayCount30360     = "30_360"
+)
+
+var ErrUnsupportedDayCount = errors.New("unsupported day-count convention")
+
+// DayFraction returns the part of a year that day earns interest for. ACT/365
+// counts every calendar day as 1/365. 30/360 counts every month as 30 days of
+// 1/360, so the 31st earns nothing and the last day of February makes up the
+// rest of its month.
+func DayFraction(dayCount string, day time.Time) (*big.Rat, error) {
+	switch dayCount {
+	case DayCountActual365:
+		return big.NewRat(1, 365), nil
+	case DayCount30360:
+		days := int64(1)
+		if day.Day() == 31 {
+			days = 0
+		} else if day.Month() == time.February && day.AddDate(0, 0, 1).Month() == time.March {
+			days = int64(30 - day.Day() + 1)
+		}
+		return big.NewRat(days, 360), nil
+	}
+	return nil, ErrUnsupportedDayCount
+}
+
+// DailyInterest returns the interest a balance earns over day at an annual
+// rate in basis points, in unrounded fractions of the minor unit
+func DailyInterest(balance int64, rateBps int32, dayCount string, day time.Time) (*big.Rat, error) {
+	fraction, err := DayFraction(dayCount, day)
+	if err != nil {
+		return nil, err
+	}
+
+	interest := new(big.Rat).SetInt64(balance)
+	interest.Mul(interest, big.NewRat(int64(rateBps), 10000))
+	return interest.Mul(interest, fraction), nil
+}
@@ -0,0 +1,92 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDayFraction(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dayCount string
+		day      string
+		expected *big.Rat
+	}{
+		{
+			name:     "Actual365",
+			dayCount: DayCountActual365,
+			day:      "2024-03-31",
+			expected: big.NewRat(1, 365),
+		},
+		{
+			name:     "Actual365LeapDay",
+			dayCount: DayCountActual365,
+			day:      "2024-02-29",
+			expected: big.NewRat(1, 365),
+		},
+		{
+			name:     "30360",
+			dayCount: DayCount30360,
+			day:      "2024-03-15",
+			expected: big.NewRat(1, 360),
+		},
+		{
+			name:     "30360ThirtyFirst",
+			dayCount: DayCount30360,
+			day:      "2024-03-31",
+			expected: new(big.Rat),
+		},
+		{
+			name:     "30360EndOfFebruary",
+			dayCount: DayCount30360,
+			day:      "2023-02-28",
+			expected: big.NewRat(3, 360),
+		},
+		{
+			name:     "30360EndOfFebruaryLeapYear",
+			dayCount: DayCount30360,
+			day:      "2024-02-29",
+			expected: big.NewRat(2, 360),
+		},
+		{
+			name:     "30360FebruaryTwentyEighthLeapYear",
+			dayCount: DayCount30360,
+			day:      "2024-02-28",
+			expected: big.NewRat(1, 360),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			day, err := time.Parse(time.DateOnly, tc.day)
+			require.NoError(t, err)
+
+			fraction, err := DayFraction(tc.dayCount, day)
+			require.NoError(t, err)
+			require.Zero(t, tc.expected.Cmp(fraction), "got %s", fraction)
+		})
+	}
+}
+
+func TestDayFractionUnsupported(t *testing.T) {
+	_, err := DayFraction("act_act", time.Now())
+	require.ErrorIs(t, err, ErrUnsupportedDayCount)
+}
+
+func TestDailyInterest(t *testing.T) {
+	day := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	// 1,000,000 at 1.5% a year earns 15,000 a year, 15,000/365 a day
+	interest, err := DailyInterest(1_000_000, 150, DayCountActual365, day)
+	require.NoError(t, err)
+	require.Zero(t, big.NewRat(15_000, 365).Cmp(interest))
+
+	interest, err = DailyInterest(1_000_000, 150, DayCount30360, day)
+	require.NoError(t, err)
+	require.Zero(t, big.NewRat(15_000, 360).Cmp(interest))
+}
@@ -0,0 +1,30 @@
+package util
+
+import (
+	"time"
+)
+
+// Whose outgoing transfers a velocity limit adds up
+const (
+	LimitScopeAccount = "account"
+	LimitScopeUser    = "user"
+)
+
+// Windows a velocity limit adds up transfers over
+const (
+	LimitPeriodDaily   = "daily"
+	LimitPeriodMonthly = "monthly"
+)
+
+// LimitWindow returns the UTC day or month that now falls in, from its start
+// up to when the limit resets
+func LimitWindow(period string, now time.Time) (start time.Time, end time.Time) {
+	now = now.UTC()
+	if period == LimitPeriodMonthly {
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+
+	start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
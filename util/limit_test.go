@@ -0,0 +1,26 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitWindow(t *testing.T) {
+	// late on Jan 31 in New York is already Feb 1 in UTC
+	newYork := time.FixedZone("EST", -5*60*60)
+	now := time.Date(2024, time.January, 31, 22, 30, 0, 0, newYork)
+
+	start, end := LimitWindow(LimitPeriodDaily, now)
+	require.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = LimitWindow(LimitPeriodMonthly, now)
+	require.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = LimitWindow(LimitPeriodMonthly, time.Date(2024, time.December, 15, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), end)
+}
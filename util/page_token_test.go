@@ -0,0 +1,50 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageToken(t *testing.T) {
+	maker := NewPageTokenMaker(RandomString(32))
+
+	cursor := PageCursor{
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		ID:        RandomInt(1, 1000),
+	}
+
+	token := maker.Encode(cursor)
+	require.NotEmpty(t, token)
+
+	decoded, err := maker.Decode(token)
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+}
+
+func TestEmptyPageToken(t *testing.T) {
+	maker := NewPageTokenMaker(RandomString(32))
+
+	cursor, err := maker.Decode("")
+	require.NoError(t, err)
+	require.True(t, cursor.IsZero())
+}
+
+func TestInvalidPageToken(t *testing.T) {
+	maker := NewPageTokenMaker(RandomString(32))
+	token := maker.Encode(PageCursor{CreatedAt: time.Now(), ID: 1})
+
+	// signed with another key
+	_, err := NewPageTokenMaker(RandomString(32)).Decode(token)
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+
+	// tampered
+	tampered := []byte(token)
+	tampered[0] ^= 1
+	_, err = maker.Decode(string(tampered))
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+
+	_, err = maker.Decode("not a token")
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+}
@@ -0,0 +1,9 @@
+package util
+
+// Reasons a banker can give for reversing a transfer
+const (
+	ReasonCodeDuplicate       = "duplicate"
+	ReasonCodeFraud           = "fraud"
+	ReasonCodeCustomerRequest = "customer_request"
+	ReasonCodeProcessingError = "processing_error"
+)
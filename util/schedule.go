@@ -0,0 +1,91 @@
+package util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Recurrence rules of a scheduled transfer
+const (
+	ScheduleOnce    = "once"
+	ScheduleDaily   = "daily"
+	ScheduleWeekly  = "weekly"
+	ScheduleMonthly = "monthly"
+	ScheduleCron    = "cron"
+)
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ParseCronExpression checks a standard five field cron expression
+func ParseCronExpression(expr string) error {
+	_, err := cronParser.Parse(expr)
+	return err
+}
+
+// NextOccurrence returns the first occurrence of a schedule starting at start
+// that falls strictly after after. ok is false once a one-off schedule has
+// happened.
+// Monthly schedules keep the day of month of start, falling back to the last
+// day of shorter months, so a schedule starting on the 31st runs on Feb 28.
+func NextOccurrence(rule string, cronExpr string, start time.Time, after time.Time) (next time.Time, ok bool, err error) {
+	switch rule {
+	case ScheduleOnce:
+		return start, after.Before(start), nil
+	case ScheduleDaily:
+		return nextByDays(start, after, 1), true, nil
+	case ScheduleWeekly:
+		return nextByDays(start, after, 7), true, nil
+	case ScheduleMonthly:
+		if after.Before(start) {
+			return start, true, nil
+		}
+		months := (after.Year()-start.Year())*12 + int(after.Month()-start.Month())
+		for {
+			next = addMonths(start, months)
+			if next.After(after) {
+				return next, true, nil
+			}
+			months++
+		}
+	case ScheduleCron:
+		schedule, err := cronParser.Parse(cronExpr)
+		if err != nil {
+			return next, false, err
+		}
+		if after.Before(start) {
+			after = start.Add(-time.Nanosecond)
+		}
+		return schedule.Next(after), true, nil
+	}
+
+	return next, false, fmt.Errorf("unknown schedule rule %q", rule)
+}
+
+func nextByDays(start time.Time, after time.Time, days int) time.Time {
+	if after.Before(start) {
+		return start
+	}
+
+	n := int(after.Sub(start)/(time.Duration(days)*24*time.Hour)) * days
+	for {
+		next := start.AddDate(0, 0, n)
+		if next.After(after) {
+			return next
+		}
+		n += days
+	}
+}
+
+func addMonths(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
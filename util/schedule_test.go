@@ -0,0 +1,98 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		rule     string
+		cronExpr string
+		after    time.Time
+		next     time.Time
+		ok       bool
+	}{
+		{
+			name:  "OnceBeforeStart",
+			rule:  ScheduleOnce,
+			after: start.Add(-time.Hour),
+			next:  start,
+			ok:    true,
+		},
+		{
+			name:  "OnceAfterStart",
+			rule:  ScheduleOnce,
+			after: start,
+			ok:    false,
+		},
+		{
+			name:  "DailyBeforeStart",
+			rule:  ScheduleDaily,
+			after: start.Add(-48 * time.Hour),
+			next:  start,
+			ok:    true,
+		},
+		{
+			name:  "DailyOnOccurrence",
+			rule:  ScheduleDaily,
+			after: start.AddDate(0, 0, 3),
+			next:  start.AddDate(0, 0, 4),
+			ok:    true,
+		},
+		{
+			name:  "Weekly",
+			rule:  ScheduleWeekly,
+			after: start.AddDate(0, 0, 8),
+			next:  start.AddDate(0, 0, 14),
+			ok:    true,
+		},
+		{
+			name:  "MonthlyClampsToShortMonth",
+			rule:  ScheduleMonthly,
+			after: start,
+			next:  time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC),
+			ok:    true,
+		},
+		{
+			name:  "MonthlyKeepsDayOfMonth",
+			rule:  ScheduleMonthly,
+			after: time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC),
+			next:  time.Date(2024, time.March, 31, 9, 0, 0, 0, time.UTC),
+			ok:    true,
+		},
+		{
+			name:     "Cron",
+			rule:     ScheduleCron,
+			cronExpr: "0 8 1 * *",
+			after:    start,
+			next:     time.Date(2024, time.February, 1, 8, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			next, ok, err := NextOccurrence(testCase.rule, testCase.cronExpr, start, testCase.after)
+			require.NoError(t, err)
+			require.Equal(t, testCase.ok, ok)
+			if ok {
+				require.Equal(t, testCase.next, next)
+			}
+		})
+	}
+}
+
+func TestNextOccurrenceInvalid(t *testing.T) {
+	_, _, err := NextOccurrence(ScheduleCron, "every tuesday", time.Now(), time.Now())
+	require.Error(t, err)
+
+	_, _, err = NextOccurrence("yearly", "", time.Now(), time.Now())
+	require.Error(t, err)
+}
@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"net/mail"
 	"regexp"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/google/uuid"
 )
 
 var (
-	isValidUsername = regexp.MustCompile(`^[a-z0-9_]+$`).MatchString
-	isValidFullName = regexp.MustCompile(`^[a-zA-Z\s]+$`).MatchString
+	isValidUsername     = regexp.MustCompile(`^[a-z0-9_]+$`).MatchString
+	isValidFullName     = regexp.MustCompile(`^[a-zA-Z\s]+$`).MatchString
+	isValidCurrencyCode = regexp.MustCompile(`^[A-Z]{3}$`).MatchString
+	isValidRate         = regexp.MustCompile(`^[0-9]{1,10}(\.[0-9]{1,10})?$`).MatchString
+	isZeroRate          = regexp.MustCompile(`^[0.]+$`).MatchString
 )
 
 func ValidateString(value string, minLength int, maxLength int) error {
@@ -70,3 +76,187 @@ func ValidateEmailId(value int64) error {
 func ValidateSecretCode(value string) error {
 	return ValidateString(value, 32, 128)
 }
+
+func ValidateID(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func ValidateCurrency(value string) error {
+	if !util.IsCurrencySupported(value) {
+		return fmt.Errorf("is not a supported currency")
+	}
+	return nil
+}
+
+func ValidateCurrencyCode(value string) error {
+	if !isValidCurrencyCode(value) {
+		return fmt.Errorf("must be a three letter ISO 4217 code")
+	}
+	return nil
+}
+
+func ValidatePageSize(value int32) error {
+	if value < 5 || value > 10 {
+		return fmt.Errorf("must be from 5-10")
+	}
+	return nil
+}
+
+func ValidateAmount(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func ValidateOverdraftLimit(value int64) error {
+	if value < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+func ValidateApprovalThreshold(value int64) error {
+	if value < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+func ValidateIdempotencyKey(value string) error {
+	return ValidateString(value, 1, 255)
+}
+
+func ValidateUUID(value string) error {
+	if _, err := uuid.Parse(value); err != nil {
+		return fmt.Errorf("is not a valid uuid")
+	}
+	return nil
+}
+
+func ValidateExchangeRate(value string) error {
+	if !isValidRate(value) {
+		return fmt.Errorf("must be a decimal number with at most 10 digits before and after the point")
+	}
+
+	if isZeroRate(value) {
+		return fmt.Errorf("must be greater than 0")
+	}
+
+	return nil
+}
+
+func ValidateSpreadBps(value int32) error {
+	if value < 0 || value >= 10000 {
+		return fmt.Errorf("must be from 0-9999")
+	}
+	return nil
+}
+
+func ValidateFeeAmount(value int64) error {
+	if value < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+func ValidateRateBps(value int32) error {
+	if value < 0 || value > 10000 {
+		return fmt.Errorf("must be from 0-10000")
+	}
+	return nil
+}
+
+func ValidateTransferType(value string) error {
+	switch value {
+	case util.TransferTypeTransfer, util.TransferTypeCrossCurrency, util.TransferTypeDeposit, util.TransferTypeWithdrawal:
+		return nil
+	}
+	return fmt.Errorf("must be one of transfer, cross_currency, deposit or withdrawal")
+}
+
+func ValidateRole(value string) error {
+	switch value {
+	case util.DepositorRole, util.BankerRole:
+		return nil
+	}
+	return fmt.Errorf("must be one of depositor or banker")
+}
+
+func ValidateScheduleRule(value string) error {
+	switch value {
+	case util.ScheduleOnce, util.ScheduleDaily, util.ScheduleWeekly, util.ScheduleMonthly, util.ScheduleCron:
+		return nil
+	}
+	return fmt.Errorf("must be one of once, daily, weekly, monthly or cron")
+}
+
+func ValidateCronExpression(value string) error {
+	if err := util.ParseCronExpression(value); err != nil {
+		return fmt.Errorf("is not a valid cron expression: %s", err)
+	}
+	return nil
+}
+
+func ValidateReasonCode(value string) error {
+	switch value {
+	case util.ReasonCodeDuplicate, util.ReasonCodeFraud, util.ReasonCodeCustomerRequest, util.ReasonCodeProcessingError:
+		return nil
+	}
+	return fmt.Errorf("must be one of duplicate, fraud, customer_request or processing_error")
+}
+
+func ValidateAccountType(value string) error {
+	switch value {
+	case util.AccountTypeChecking, util.AccountTypeSavings:
+		return nil
+	}
+	return fmt.Errorf("must be one of checking or savings")
+}
+
+func ValidateAccountNickname(value string) error {
+	return ValidateString(value, 1, 50)
+}
+
+func ValidateAccountStatus(value string) error {
+	switch value {
+	case util.AccountStatusActive, util.AccountStatusFrozen, util.AccountStatusClosed:
+		return nil
+	}
+	return fmt.Errorf("must be one of active, frozen or closed")
+}
+
+func ValidateAccountMemberRole(value string) error {
+	switch value {
+	case util.AccountMemberCoOwner, util.AccountMemberViewer, util.AccountMemberTransferor:
+		return nil
+	}
+	return fmt.Errorf("must be one of co_owner, viewer or transferor")
+}
+
+func ValidateDayCount(value string) error {
+	switch value {
+	case util.DayCountActual365, util.DayCount30360:
+		return nil
+	}
+	return fmt.Errorf("must be one of act_365 or 30_360")
+}
+
+func ValidateLimitScope(value string) error {
+	switch value {
+	case util.LimitScopeAccount, util.LimitScopeUser:
+		return nil
+	}
+	return fmt.Errorf("must be one of account or user")
+}
+
+func ValidateLimitPeriod(value string) error {
+	switch value {
+	case util.LimitPeriodDaily, util.LimitPeriodMonthly:
+		return nil
+	}
+	return fmt.Errorf("must be one of daily or monthly")
+}
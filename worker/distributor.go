@@ -8,6 +8,9 @@ import (
 
 type TaskDistributor interface {
 	DistributeTaskSendVerifyEmail(ctx context.Context, payload *PayloadSendVerifyEmail, opts ...asynq.Option) error
+	DistributeTaskExpireHold(ctx context.Context, payload *PayloadExpireHold, opts ...asynq.Option) error
+	DistributeTaskExecuteScheduledTransfer(ctx context.Context, payload *PayloadExecuteScheduledTransfer, opts ...asynq.Option) error
+	DistributeTaskExpireTransferRequest(ctx context.Context, payload *PayloadExpireTransferRequest, opts ...asynq.Option) error
 }
 
 type RedisTaskDistributor struct {
@@ -41,6 +41,63 @@ func (m *MockTaskDistributor) EXPECT() *MockTaskDistributorMockRecorder {
 	return m.recorder
 }
 
+// DistributeTaskExecuteScheduledTransfer mocks base method.
+func (m *MockTaskDistributor) DistributeTaskExecuteScheduledTransfer(arg0 context.Context, arg1 *worker.PayloadExecuteScheduledTransfer, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskExecuteScheduledTransfer", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskExecuteScheduledTransfer indicates an expected call of DistributeTaskExecuteScheduledTransfer.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskExecuteScheduledTransfer(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskExecuteScheduledTransfer", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskExecuteScheduledTransfer), varargs...)
+}
+
+// DistributeTaskExpireHold mocks base method.
+func (m *MockTaskDistributor) DistributeTaskExpireHold(arg0 context.Context, arg1 *worker.PayloadExpireHold, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskExpireHold", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskExpireHold indicates an expected call of DistributeTaskExpireHold.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskExpireHold(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskExpireHold", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskExpireHold), varargs...)
+}
+
+// DistributeTaskExpireTransferRequest mocks base method.
+func (m *MockTaskDistributor) DistributeTaskExpireTransferRequest(arg0 context.Context, arg1 *worker.PayloadExpireTransferRequest, arg2 ...asynq.Option) error {
+	m.ctrl.T.Helper()
+	varargs := []any{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DistributeTaskExpireTransferRequest", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DistributeTaskExpireTransferRequest indicates an expected call of DistributeTaskExpireTransferRequest.
+func (mr *MockTaskDistributorMockRecorder) DistributeTaskExpireTransferRequest(arg0, arg1 any, arg2 ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributeTaskExpireTransferRequest", reflect.TypeOf((*MockTaskDistributor)(nil).DistributeTaskExpireTransferRequest), varargs...)
+}
+
 // DistributeTaskSendVerifyEmail mocks base method.
 func (m *MockTaskDistributor) DistributeTaskSendVerifyEmail(arg0 context.Context, arg1 *worker.PayloadSendVerifyEmail, arg2 ...asynq.Option) error {
 	m.ctrl.T.Helper()
@@ -17,15 +17,24 @@ const (
 type TaskProcessor interface {
 	Start() error
 	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExpireHold(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExpireTransferRequest(ctx context.Context, task *asynq.Task) error
+	ProcessTaskScheduleDueTransfers(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExecuteScheduledTransfer(ctx context.Context, task *asynq.Task) error
+	ProcessTaskReconcileLedger(ctx context.Context, task *asynq.Task) error
+	ProcessTaskAccrueInterest(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPostInterest(ctx context.Context, task *asynq.Task) error
+	ProcessTaskMarkDormantAccounts(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
-	server *asynq.Server
-	store  db.Store
-	mailer mail.EmailSender
+	server      *asynq.Server
+	store       db.Store
+	mailer      mail.EmailSender
+	distributor TaskDistributor
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer mail.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer mail.EmailSender, distributor TaskDistributor) TaskProcessor {
 	server := asynq.NewServer(
 		redisOpt,
 		asynq.Config{
@@ -45,14 +54,23 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, mailer
 	)
 
 	return &RedisTaskProcessor{
-		server: server,
-		store:  store,
-		mailer: mailer,
+		server:      server,
+		store:       store,
+		mailer:      mailer,
+		distributor: distributor,
 	}
 }
 
 func (processor *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(TaskSendVerifyEmail, processor.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(TaskExpireHold, processor.ProcessTaskExpireHold)
+	mux.HandleFunc(TaskExpireTransferRequest, processor.ProcessTaskExpireTransferRequest)
+	mux.HandleFunc(TaskScheduleDueTransfers, processor.ProcessTaskScheduleDueTransfers)
+	mux.HandleFunc(TaskExecuteScheduledTransfer, processor.ProcessTaskExecuteScheduledTransfer)
+	mux.HandleFunc(TaskReconcileLedger, processor.ProcessTaskReconcileLedger)
+	mux.HandleFunc(TaskAccrueInterest, processor.ProcessTaskAccrueInterest)
+	mux.HandleFunc(TaskPostInterest, processor.ProcessTaskPostInterest)
+	mux.HandleFunc(TaskMarkDormantAccounts, processor.ProcessTaskMarkDormantAccounts)
 	return processor.server.Start(mux)
 }
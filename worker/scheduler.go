@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// scheduleDueTransfersInterval is how often due scheduled transfers are
+	// looked for, which bounds how late an occurrence can run
+	scheduleDueTransfersInterval = "@every 1m"
+	reconcileLedgerInterval      = "@daily"
+	// interest accrues for the day that just ended in UTC and is paid out on
+	// the first of the month
+	accrueInterestInterval = "5 0 * * *"
+	postInterestInterval   = "30 0 1 * *"
+	markDormantInterval    = "0 2 * * *"
+)
+
+type TaskScheduler interface {
+	Start() error
+}
+
+type RedisTaskScheduler struct {
+	scheduler *asynq.Scheduler
+}
+
+func NewRedisTaskScheduler(redisOpt asynq.RedisClientOpt) TaskScheduler {
+	scheduler := asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{
+		Logger: NewLogger(),
+	})
+
+	return &RedisTaskScheduler{
+		scheduler: scheduler,
+	}
+}
+
+func (scheduler *RedisTaskScheduler) Start() error {
+	_, err := scheduler.scheduler.Register(
+		scheduleDueTransfersInterval,
+		asynq.NewTask(TaskScheduleDueTransfers, nil),
+		asynq.Queue(QueueCritical),
+		// the next tick picks up whatever a failed one missed
+		asynq.MaxRetry(0),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = scheduler.scheduler.Register(
+		reconcileLedgerInterval,
+		asynq.NewTask(TaskReconcileLedger, nil),
+		asynq.MaxRetry(3),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = scheduler.scheduler.Register(
+		accrueInterestInterval,
+		asynq.NewTask(TaskAccrueInterest, nil),
+		asynq.MaxRetry(10),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = scheduler.scheduler.Register(
+		postInterestInterval,
+		asynq.NewTask(TaskPostInterest, nil),
+		asynq.MaxRetry(10),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = scheduler.scheduler.Register(
+		markDormantInterval,
+		asynq.NewTask(TaskMarkDormantAccounts, nil),
+		asynq.MaxRetry(3),
+	)
+	if err != nil {
+		return err
+	}
+
+	return scheduler.scheduler.Start()
+}
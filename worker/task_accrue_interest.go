@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskAccrueInterest = "task:accrue_interest"
+
+func (processor *RedisTaskProcessor) ProcessTaskAccrueInterest(ctx context.Context, task *asynq.Task) error {
+	// the task runs just after midnight UTC and accrues the day that ended
+	day := time.Now().UTC().AddDate(0, 0, -1)
+
+	accrued, err := db.AccrueInterest(ctx, processor.store, day)
+	if err != nil {
+		return fmt.Errorf("failed to accrue interest: %w", err)
+	}
+
+	log.Info().
+		Str("type", task.Type()).
+		Str("day", day.Format(time.DateOnly)).
+		Int("accruals", accrued).
+		Msg("processed task")
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskExecuteScheduledTransfer = "task:execute_scheduled_transfer"
+
+type PayloadExecuteScheduledTransfer struct {
+	RunID int64 `json:"run_id"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskExecuteScheduledTransfer(ctx context.Context, payload *PayloadExecuteScheduledTransfer, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskExecuteScheduledTransfer, jsonPayload, opts...)
+	taskInfo, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", taskInfo.Type).Bytes("payload", task.Payload()).Str("queue", taskInfo.Queue).Int("max_retry", taskInfo.MaxRetry).Msg("enqueued task")
+
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskExecuteScheduledTransfer(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExecuteScheduledTransfer
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	run, err := processor.store.GetScheduledTransferRun(ctx, payload.RunID)
+	if err != nil {
+		// a run that doesn't exist yet may belong to a transaction that
+		// hasn't committed, so it is retried rather than dropped
+		return fmt.Errorf("failed to get scheduled transfer run: %w", err)
+	}
+
+	if run.Status != db.ScheduledTransferRunStatusPending {
+		return nil
+	}
+
+	schedule, err := processor.store.GetScheduledTransfer(ctx, run.ScheduledTransferID)
+	if err != nil {
+		return fmt.Errorf("failed to get scheduled transfer: %w", err)
+	}
+
+	update := db.UpdateScheduledTransferRunParams{
+		ID:     run.ID,
+		Status: db.ScheduledTransferRunStatusSucceeded,
+	}
+
+	if schedule.Status == db.ScheduledTransferStatusPaused || schedule.Status == db.ScheduledTransferStatusCancelled {
+		update.Status = db.ScheduledTransferRunStatusSkipped
+		update.Error = fmt.Sprintf("schedule was %s", schedule.Status)
+	} else if isMember, err := processor.isScheduleCreatorMember(ctx, schedule); err != nil {
+		return fmt.Errorf("failed to check the schedule creator: %w", err)
+	} else if !isMember {
+		// stop the runs a former member set up on an account they have left
+		_, err = processor.store.SetScheduledTransferStatusTx(ctx, db.SetScheduledTransferStatusTxParams{
+			ID:     schedule.ID,
+			Status: db.ScheduledTransferStatusPaused,
+			Now:    time.Now(),
+		})
+		if err != nil && !errors.Is(err, db.ErrScheduleTransition) {
+			return fmt.Errorf("failed to pause scheduled transfer: %w", err)
+		}
+
+		update.Status = db.ScheduledTransferRunStatusSkipped
+		update.Error = fmt.Sprintf("%s is no longer a member of account [%d]", schedule.CreatedBy, schedule.FromAccountID)
+	} else {
+		result, err := processor.executeScheduledTransfer(ctx, schedule, run)
+		switch {
+		case err == nil:
+			update.TransferID = pgtype.Int8{Int64: result.Transfer.ID, Valid: true}
+		case errors.Is(err, db.ErrInsufficientFunds),
+			errors.Is(err, db.ErrExchangeRateNotFound),
+			errors.Is(err, db.ErrConvertedAmountTooSmall),
+			errors.Is(err, db.ErrIdempotencyKeyConflict),
+			errors.Is(err, db.ErrTransferLimitExceeded),
+			errors.Is(err, db.ErrAccountFrozen),
+			errors.Is(err, db.ErrAccountDormant),
+			errors.Is(err, db.ErrAccountClosed),
+			errors.Is(err, db.ErrApprovalRequired):
+			update.Status = db.ScheduledTransferRunStatusFailed
+			update.Error = err.Error()
+		default:
+			return fmt.Errorf("failed to execute scheduled transfer: %w", err)
+		}
+	}
+
+	_, err = processor.store.UpdateScheduledTransferRun(ctx, update)
+	if err != nil {
+		return fmt.Errorf("failed to update scheduled transfer run: %w", err)
+	}
+
+	log.Info().
+		Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Int64("scheduled_transfer_id", schedule.ID).
+		Str("status", update.Status).
+		Msg("processed task")
+
+	return nil
+}
+
+// isScheduleCreatorMember reports whether the user who set a schedule up can
+// still manage its source account. Bankers set schedules up for customers
+// without being members of their accounts.
+func (processor *RedisTaskProcessor) isScheduleCreatorMember(ctx context.Context, schedule db.ScheduledTransfer) (bool, error) {
+	member, err := processor.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: schedule.FromAccountID,
+		Username:  schedule.CreatedBy,
+	})
+	if err == nil {
+		return member.Role == util.AccountMemberOwner || member.Role == util.AccountMemberCoOwner, nil
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return false, err
+	}
+
+	user, err := processor.store.GetUser(ctx, schedule.CreatedBy)
+	if err != nil {
+		return false, err
+	}
+
+	return user.Role == util.BankerRole, nil
+}
+
+// executeScheduledTransfer makes the transfer of a run. Nothing runs
+// unattended above the approval threshold of the source account's currency,
+// which may have been lowered since the schedule was set up.
+func (processor *RedisTaskProcessor) executeScheduledTransfer(ctx context.Context, schedule db.ScheduledTransfer, run db.ScheduledTransferRun) (db.TransferTxResult, error) {
+	fromAccount, err := processor.store.GetAccount(ctx, schedule.FromAccountID)
+	if err != nil {
+		return db.TransferTxResult{}, err
+	}
+
+	if util.RequiresApproval(fromAccount.Currency, schedule.Amount) {
+		return db.TransferTxResult{}, db.ErrApprovalRequired
+	}
+
+	// the key makes a retry after a crash return the transfer that was
+	// already made instead of paying twice
+	return processor.store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountID:  schedule.FromAccountID,
+		ToAccountID:    schedule.ToAccountID,
+		Amount:         schedule.Amount,
+		CrossCurrency:  schedule.CrossCurrency,
+		Username:       schedule.Owner,
+		IdempotencyKey: fmt.Sprintf("scheduled-transfer-run:%d", run.ID),
+	})
+}
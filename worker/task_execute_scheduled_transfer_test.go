@@ -0,0 +1,182 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	mockdb "github.com/billy-le/simple-bank/db/mock"
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/billy-le/simple-bank/util"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessTaskExecuteScheduledTransfer(t *testing.T) {
+	launched := util.Currencies()
+	defer util.SetCurrencies(launched)
+
+	threshold := int64(1_000)
+	util.SetCurrencies([]util.Currency{
+		{Code: util.USD, NumericCode: "840", MinorUnits: 2, Enabled: true, ApprovalThreshold: threshold},
+	})
+
+	owner := util.RandomOwner()
+	coOwner := util.RandomOwner()
+	fromAccount := db.Account{ID: 1, Owner: owner, Currency: util.USD, Status: util.AccountStatusActive}
+	run := db.ScheduledTransferRun{ID: 7, ScheduledTransferID: 3, Status: db.ScheduledTransferRunStatusPending}
+
+	ownerMember := db.GetAccountMemberParams{AccountID: fromAccount.ID, Username: owner}
+	coOwnerMember := db.GetAccountMemberParams{AccountID: fromAccount.ID, Username: coOwner}
+
+	newSchedule := func(amount int64) db.ScheduledTransfer {
+		return db.ScheduledTransfer{
+			ID:            run.ScheduledTransferID,
+			Owner:         owner,
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   2,
+			Amount:        amount,
+			Rule:          util.ScheduleOnce,
+			Status:        db.ScheduledTransferStatusActive,
+			CreatedBy:     owner,
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name: "Ok",
+			buildStubs: func(store *mockdb.MockStore) {
+				schedule := newSchedule(threshold)
+				store.EXPECT().GetScheduledTransferRun(gomock.Any(), gomock.Eq(run.ID)).Times(1).Return(run, nil)
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(ownerMember)).Times(1).Return(db.AccountMember{Role: util.AccountMemberOwner}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+
+				result := db.TransferTxResult{Transfer: db.Transfer{ID: 11}}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+
+				update := db.UpdateScheduledTransferRunParams{
+					ID:         run.ID,
+					Status:     db.ScheduledTransferRunStatusSucceeded,
+					TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+				}
+				store.EXPECT().UpdateScheduledTransferRun(gomock.Any(), gomock.Eq(update)).Times(1).Return(db.ScheduledTransferRun{}, nil)
+			},
+		},
+		{
+			name: "AboveApprovalThreshold",
+			buildStubs: func(store *mockdb.MockStore) {
+				schedule := newSchedule(threshold + 1)
+				store.EXPECT().GetScheduledTransferRun(gomock.Any(), gomock.Eq(run.ID)).Times(1).Return(run, nil)
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(ownerMember)).Times(1).Return(db.AccountMember{Role: util.AccountMemberOwner}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+				update := db.UpdateScheduledTransferRunParams{
+					ID:     run.ID,
+					Status: db.ScheduledTransferRunStatusFailed,
+					Error:  db.ErrApprovalRequired.Error(),
+				}
+				store.EXPECT().UpdateScheduledTransferRun(gomock.Any(), gomock.Eq(update)).Times(1).Return(db.ScheduledTransferRun{}, nil)
+			},
+		},
+		{
+			name: "CreatorLeftAccount",
+			buildStubs: func(store *mockdb.MockStore) {
+				schedule := newSchedule(threshold)
+				schedule.CreatedBy = coOwner
+				store.EXPECT().GetScheduledTransferRun(gomock.Any(), gomock.Eq(run.ID)).Times(1).Return(run, nil)
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(coOwnerMember)).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(coOwner)).Times(1).Return(db.User{Username: coOwner, Role: util.DepositorRole}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+				store.EXPECT().
+					SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.SetScheduledTransferStatusTxParams) (db.ScheduledTransfer, error) {
+						require.Equal(t, schedule.ID, arg.ID)
+						require.Equal(t, db.ScheduledTransferStatusPaused, arg.Status)
+						return db.ScheduledTransfer{}, nil
+					})
+
+				store.EXPECT().
+					UpdateScheduledTransferRun(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.UpdateScheduledTransferRunParams) (db.ScheduledTransferRun, error) {
+						require.Equal(t, db.ScheduledTransferRunStatusSkipped, arg.Status)
+						require.Contains(t, arg.Error, coOwner)
+						return db.ScheduledTransferRun{}, nil
+					})
+			},
+		},
+		{
+			name: "CreatedByBanker",
+			buildStubs: func(store *mockdb.MockStore) {
+				banker := util.RandomOwner()
+				schedule := newSchedule(threshold)
+				schedule.CreatedBy = banker
+				store.EXPECT().GetScheduledTransferRun(gomock.Any(), gomock.Eq(run.ID)).Times(1).Return(run, nil)
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: fromAccount.ID, Username: banker})).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(banker)).Times(1).Return(db.User{Username: banker, Role: util.BankerRole}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).Times(0)
+
+				result := db.TransferTxResult{Transfer: db.Transfer{ID: 12}}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(result, nil)
+
+				update := db.UpdateScheduledTransferRunParams{
+					ID:         run.ID,
+					Status:     db.ScheduledTransferRunStatusSucceeded,
+					TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+				}
+				store.EXPECT().UpdateScheduledTransferRun(gomock.Any(), gomock.Eq(update)).Times(1).Return(db.ScheduledTransferRun{}, nil)
+			},
+		},
+	}
+
+	for i := range testCases {
+		testCase := testCases[i]
+		t.Run(testCase.name, func(t *testing.T) {
+			storeCtrl := gomock.NewController(t)
+			defer storeCtrl.Finish()
+
+			store := mockdb.NewMockStore(storeCtrl)
+			testCase.buildStubs(store)
+
+			processor := &RedisTaskProcessor{store: store}
+
+			payload, err := json.Marshal(PayloadExecuteScheduledTransfer{RunID: run.ID})
+			require.NoError(t, err)
+
+			err = processor.ProcessTaskExecuteScheduledTransfer(context.Background(), asynq.NewTask(TaskExecuteScheduledTransfer, payload))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestProcessTaskExecuteScheduledTransferRunNotVisible(t *testing.T) {
+	storeCtrl := gomock.NewController(t)
+	defer storeCtrl.Finish()
+
+	store := mockdb.NewMockStore(storeCtrl)
+	store.EXPECT().GetScheduledTransferRun(gomock.Any(), gomock.Any()).Times(1).Return(db.ScheduledTransferRun{}, db.ErrRecordNotFound)
+	store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+
+	processor := &RedisTaskProcessor{store: store}
+
+	payload, err := json.Marshal(PayloadExecuteScheduledTransfer{RunID: 7})
+	require.NoError(t, err)
+
+	// the transaction that created the run may not have committed yet
+	err = processor.ProcessTaskExecuteScheduledTransfer(context.Background(), asynq.NewTask(TaskExecuteScheduledTransfer, payload))
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+	require.NotErrorIs(t, err, asynq.SkipRetry)
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskExpireHold = "task:expire_hold"
+
+type PayloadExpireHold struct {
+	HoldID int64 `json:"hold_id"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskExpireHold(ctx context.Context, payload *PayloadExpireHold, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskExpireHold, jsonPayload, opts...)
+	taskInfo, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", taskInfo.Type).Bytes("payload", task.Payload()).Str("queue", taskInfo.Queue).Int("max_retry", taskInfo.MaxRetry).Msg("enqueued task")
+
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskExpireHold(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExpireHold
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	_, err := processor.store.ReleaseHoldTx(ctx, db.ReleaseHoldTxParams{
+		HoldID: payload.HoldID,
+		Status: db.HoldStatusExpired,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("hold doesn't exist: %w", asynq.SkipRetry)
+		}
+		// captured or released before it ran out, so there is nothing to do
+		if errors.Is(err, db.ErrHoldNotPending) {
+			return nil
+		}
+		return fmt.Errorf("failed to expire hold: %w", err)
+	}
+
+	log.Info().
+		Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Int64("hold_id", payload.HoldID).
+		Msg("processed task")
+
+	return nil
+}
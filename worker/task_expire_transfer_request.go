@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskExpireTransferRequest = "task:expire_transfer_request"
+
+type PayloadExpireTransferRequest struct {
+	TransferRequestID int64 `json:"transfer_request_id"`
+}
+
+func (distributor *RedisTaskDistributor) DistributeTaskExpireTransferRequest(ctx context.Context, payload *PayloadExpireTransferRequest, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskExpireTransferRequest, jsonPayload, opts...)
+	taskInfo, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", taskInfo.Type).Bytes("payload", task.Payload()).Str("queue", taskInfo.Queue).Int("max_retry", taskInfo.MaxRetry).Msg("enqueued task")
+
+	return nil
+}
+
+func (processor *RedisTaskProcessor) ProcessTaskExpireTransferRequest(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExpireTransferRequest
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	_, err := processor.store.CloseTransferRequestTx(ctx, db.CloseTransferRequestTxParams{
+		ID:     payload.TransferRequestID,
+		Status: db.TransferRequestStatusExpired,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("transfer request doesn't exist: %w", asynq.SkipRetry)
+		}
+		// approved or rejected before it ran out, so there is nothing to do
+		if errors.Is(err, db.ErrTransferRequestNotPending) {
+			return nil
+		}
+		return fmt.Errorf("failed to expire transfer request: %w", err)
+	}
+
+	log.Info().
+		Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Int64("transfer_request_id", payload.TransferRequestID).
+		Msg("processed task")
+
+	return nil
+}
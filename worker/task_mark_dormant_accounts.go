@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billy-le/simple-bank/util"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskMarkDormantAccounts = "task:mark_dormant_accounts"
+
+func (processor *RedisTaskProcessor) ProcessTaskMarkDormantAccounts(ctx context.Context, task *asynq.Task) error {
+	inactiveSince := time.Now().UTC().AddDate(0, -util.DormantAfterMonths, 0)
+
+	accounts, err := processor.store.MarkDormantAccountsTx(ctx, inactiveSince)
+	if err != nil {
+		return fmt.Errorf("failed to mark dormant accounts: %w", err)
+	}
+
+	log.Info().
+		Str("type", task.Type()).
+		Str("inactive_since", inactiveSince.Format(time.DateOnly)).
+		Int("accounts", len(accounts)).
+		Msg("processed task")
+
+	return nil
+}
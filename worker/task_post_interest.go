@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskPostInterest = "task:post_interest"
+
+func (processor *RedisTaskProcessor) ProcessTaskPostInterest(ctx context.Context, task *asynq.Task) error {
+	// the task runs on the first of the month and pays out everything accrued
+	// before it
+	now := time.Now().UTC()
+	before := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	accountIDs, err := processor.store.ListAccountsWithUnpostedInterest(ctx, pgtype.Date{Time: before, Valid: true})
+	if err != nil {
+		return fmt.Errorf("failed to list accounts with unposted interest: %w", err)
+	}
+
+	posted := 0
+	failed := 0
+	for _, accountID := range accountIDs {
+		result, err := processor.store.PostInterestTx(ctx, db.PostInterestTxParams{
+			AccountID: accountID,
+			Before:    before,
+		})
+		if err != nil {
+			// keep paying the other accounts, a retry picks up the ones that failed
+			log.Error().Err(err).
+				Str("type", task.Type()).
+				Int64("account_id", accountID).
+				Msg("failed to post interest")
+			failed++
+			continue
+		}
+		if result.Transfer.ID != 0 {
+			posted++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to post interest to %d accounts", failed)
+	}
+
+	log.Info().
+		Str("type", task.Type()).
+		Int("accounts", posted).
+		Msg("processed task")
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskReconcileLedger = "task:reconcile_ledger"
+
+func (processor *RedisTaskProcessor) ProcessTaskReconcileLedger(ctx context.Context, task *asynq.Task) error {
+	report, err := db.CheckLedger(ctx, processor.store)
+	if err != nil {
+		return fmt.Errorf("failed to check ledger: %w", err)
+	}
+
+	// a discrepancy needs a person to look at it, running again won't fix it
+	for _, discrepancy := range report.Discrepancies() {
+		log.Error().Str("type", task.Type()).Msg(discrepancy)
+	}
+
+	if !report.Balanced() {
+		log.Error().
+			Str("type", task.Type()).
+			Int("accounts", len(report.AccountMismatches)).
+			Int("transfers", len(report.TransferMismatches)).
+			Int("journals", len(report.JournalMismatches)).
+			Msg("ledger is out of balance")
+		return nil
+	}
+
+	log.Info().
+		Str("type", task.Type()).
+		Msg("processed task")
+
+	return nil
+}
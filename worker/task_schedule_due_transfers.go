@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/billy-le/simple-bank/db/sqlc"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskScheduleDueTransfers = "task:schedule_due_transfers"
+
+const scheduleDueTransfersBatchSize = 100
+
+func (processor *RedisTaskProcessor) ProcessTaskScheduleDueTransfers(ctx context.Context, task *asynq.Task) error {
+	now := time.Now()
+	runs := 0
+
+	for {
+		result, err := processor.store.ScheduleDueTransfersTx(ctx, db.ScheduleDueTransfersTxParams{
+			Now:       now,
+			BatchSize: scheduleDueTransfersBatchSize,
+			AfterCreate: func(run db.ScheduledTransferRun) error {
+				// the run is queued before the transaction commits, so give it
+				// time to become visible
+				opts := []asynq.Option{
+					asynq.MaxRetry(10),
+					asynq.ProcessIn(10 * time.Second),
+					asynq.Queue(QueueCritical),
+				}
+				return processor.distributor.DistributeTaskExecuteScheduledTransfer(ctx, &PayloadExecuteScheduledTransfer{RunID: run.ID}, opts...)
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to schedule due transfers: %w", err)
+		}
+
+		runs += len(result.Runs)
+		if result.Schedules < scheduleDueTransfersBatchSize {
+			break
+		}
+	}
+
+	log.Info().
+		Str("type", task.Type()).
+		Int("runs", runs).
+		Msg("processed task")
+
+	return nil
+}